@@ -0,0 +1,58 @@
+// Package account pembuatan user yang dipakai bersama oleh registrasi API dan CLI, agar
+// keduanya menulis audit log dan mengirim event UserRegistered yang sama.
+package account
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"mkp/audit"
+	"mkp/events"
+	"mkp/models"
+	"mkp/notify"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrEmailTaken email sudah terdaftar
+var ErrEmailTaken = errors.New("email already registered")
+
+// Create membuat user baru dengan role tersebut. Input dianggap sudah divalidasi; locale kosong
+// diganti bahasa default. Setelah commit events.UserRegistered dikirim.
+func Create(ctx context.Context, db *sql.DB, actor audit.Actor, input models.RegisterRequest, role string) (models.User, error) {
+	var user models.User
+
+	var exists bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)", input.Email).Scan(&exists); err != nil {
+		return user, err
+	}
+	if exists {
+		return user, ErrEmailTaken
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return user, err
+	}
+
+	// Bahasa notifikasi, default Bahasa Indonesia
+	if input.Locale == "" {
+		input.Locale = notify.DefaultLocale
+	}
+
+	_, err = audit.WithTx(db, actor, audit.ActionCreate, "users", 0, func(tx *sql.Tx) (int, error) {
+		err := tx.QueryRow(`
+			INSERT INTO users (fullname, email, password_hash, role, locale, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+			RETURNING id, fullname, email, role, locale, created_at, updated_at
+		`, input.Fullname, input.Email, string(hashedPassword), role, input.Locale).
+			Scan(&user.ID, &user.Fullname, &user.Email, &user.Role, &user.Locale, &user.CreatedAt, &user.UpdatedAt)
+		return user.ID, err
+	})
+	if err != nil {
+		return user, err
+	}
+
+	events.Publish(ctx, events.UserRegistered{UserID: user.ID, Email: user.Email})
+	return user, nil
+}
@@ -0,0 +1,136 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ContentType media type untuk error response sesuai RFC 7807
+const ContentType = "application/problem+json"
+
+// RequestIDHeader header yang membawa ID unik setiap request
+const RequestIDHeader = "X-Request-ID"
+
+// Kode error yang stabil, dipakai client untuk branching logic
+const (
+	CodeMethodNotAllowed         = "METHOD_NOT_ALLOWED"
+	CodeInvalidRequestBody       = "INVALID_REQUEST_BODY"
+	CodeValidationFailed         = "VALIDATION_FAILED"
+	CodeUnsupportedMediaType     = "UNSUPPORTED_MEDIA_TYPE"
+	CodeInvalidID                = "INVALID_ID"
+	CodeNotFound                 = "NOT_FOUND"
+	CodeScheduleNotFound         = "SCHEDULE_NOT_FOUND"
+	CodeScheduleNotBookable      = "SCHEDULE_NOT_BOOKABLE"
+	CodeScheduleHasPaidTickets   = "SCHEDULE_HAS_PAID_TICKETS"
+	CodeScheduleNotDeleted       = "SCHEDULE_NOT_DELETED"
+	CodeScheduleOverlap          = "SCHEDULE_OVERLAP"
+	CodeBulkScheduleInvalid      = "BULK_SCHEDULE_INVALID"
+	CodeImportInvalid            = "IMPORT_INVALID"
+	CodeMovieNotFound            = "MOVIE_NOT_FOUND"
+	CodeCinemaNotFound           = "CINEMA_NOT_FOUND"
+	CodePricingRuleNotFound      = "PRICING_RULE_NOT_FOUND"
+	CodeSeatNotFound             = "SEAT_NOT_FOUND"
+	CodeSeatUnavailable          = "SEAT_UNAVAILABLE"
+	CodeBookingNotFound          = "BOOKING_NOT_FOUND"
+	CodeBookingNotPayable        = "BOOKING_NOT_PAYABLE"
+	CodeBookingNotCancellable    = "BOOKING_NOT_CANCELLABLE"
+	CodeWaitlistNotFound         = "WAITLIST_NOT_FOUND"
+	CodeWaitlistNotNeeded        = "WAITLIST_NOT_NEEDED"
+	CodeWaitlistAlreadyJoined    = "WAITLIST_ALREADY_JOINED"
+	CodeWebhookNotFound          = "WEBHOOK_NOT_FOUND"
+	CodeWebhookDeliveryNotFound  = "WEBHOOK_DELIVERY_NOT_FOUND"
+	CodePromotionNotFound        = "PROMOTION_NOT_FOUND"
+	CodePromoCodeExists          = "PROMO_CODE_EXISTS"
+	CodePromoNotFound            = "PROMO_NOT_FOUND"
+	CodePromoNotActive           = "PROMO_NOT_ACTIVE"
+	CodePromoUsageLimit          = "PROMO_USAGE_LIMIT_REACHED"
+	CodePromoUserLimit           = "PROMO_USER_LIMIT_REACHED"
+	CodePromoMinSpend            = "PROMO_MIN_SPEND_NOT_MET"
+	CodePromoNotApplicable       = "PROMO_NOT_APPLICABLE"
+	CodeEmailAlreadyRegistered   = "EMAIL_ALREADY_REGISTERED"
+	CodeInvalidCredentials       = "INVALID_CREDENTIALS"
+	CodeAuthRequired             = "AUTH_REQUIRED"
+	CodeInvalidAuthHeader        = "INVALID_AUTH_HEADER"
+	CodeInvalidToken             = "INVALID_TOKEN"
+	CodeForbidden                = "FORBIDDEN"
+	CodeInvalidIdempotencyKey    = "INVALID_IDEMPOTENCY_KEY"
+	CodeIdempotencyKeyReused     = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyKeyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	CodePreconditionRequired     = "PRECONDITION_REQUIRED"
+	CodePreconditionFailed       = "PRECONDITION_FAILED"
+	CodeDatabaseError            = "DATABASE_ERROR"
+	CodeInternalError            = "INTERNAL_ERROR"
+)
+
+// FieldError detail error untuk satu field pada request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Problem representasi error response (RFC 7807)
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// New membuat Problem baru dengan type dan title yang diturunkan dari code dan status
+func New(status int, code, detail string) *Problem {
+	return &Problem{
+		Type:   TypeURI(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// WithErrors menambahkan daftar field error ke Problem
+func (p *Problem) WithErrors(errs ...FieldError) *Problem {
+	p.Errors = append(p.Errors, errs...)
+	return p
+}
+
+// Error mengimplementasikan interface error
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Code + ": " + p.Detail
+	}
+	return p.Code
+}
+
+// TypeURI membuat URI relatif untuk field type, misal: /problems/schedule-not-found
+func TypeURI(code string) string {
+	return "/problems/" + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+// Write mengirim Problem sebagai response problem+json
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.RequestID == "" && r != nil {
+		p.RequestID = r.Header.Get(RequestIDHeader)
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		w.Header().Set("Content-Type", ContentType)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"type":"/problems/internal-error","title":"Internal Server Error","status":500,"code":"INTERNAL_ERROR"}`))
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	w.Write(body)
+}
+
+// Respond shortcut untuk membuat dan mengirim Problem sekaligus
+func Respond(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	Write(w, r, New(status, code, detail))
+}
@@ -0,0 +1,161 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"mkp/apierror"
+	"mkp/middleware"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// Aksi yang dicatat di audit log
+const (
+	ActionCreate = "CREATE"
+	ActionUpdate = "UPDATE"
+	ActionDelete = "DELETE"
+	ActionImport = "IMPORT"
+)
+
+// redactedColumns kolom yang tidak boleh ikut tersimpan di snapshot
+const redactedColumns = " - 'password_hash' - 'calendar_token' - 'search_vector' - 'secret'"
+
+// Querier dipenuhi oleh *sql.DB dan *sql.Tx
+type Querier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Actor siapa yang melakukan perubahan. UserID nil untuk aksi anonim (misal register) atau CLI.
+type Actor struct {
+	UserID    *int
+	RequestID string
+}
+
+// Change nilai lama dan baru satu kolom
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Entry satu baris audit log
+type Entry struct {
+	ID         int64             `json:"id"`
+	ActorID    *int              `json:"actor_id"`
+	ActorEmail *string           `json:"actor_email"`
+	Action     string            `json:"action"`
+	EntityType string            `json:"entity_type"`
+	EntityID   *int              `json:"entity_id"`
+	Before     json.RawMessage   `json:"before"`
+	After      json.RawMessage   `json:"after"`
+	Changes    map[string]Change `json:"changes"`
+	RequestID  *string           `json:"request_id"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// FromRequest actor dari user yang sedang login dan request ID
+func FromRequest(r *http.Request) Actor {
+	actor := Actor{RequestID: r.Header.Get(apierror.RequestIDHeader)}
+	if userID, ok := middleware.UserID(r.Context()); ok {
+		actor.UserID = &userID
+	}
+	return actor
+}
+
+// Snapshot mengambil isi satu baris sebagai JSON (tanpa kolom rahasia) dan mengunci baris
+// tersebut sampai transaksi selesai. Mengembalikan sql.ErrNoRows jika baris tidak ada.
+func Snapshot(q Querier, table string, id int) (json.RawMessage, error) {
+	var snapshot []byte
+	query := "SELECT to_jsonb(t)" + redactedColumns + " FROM " + table + " t WHERE id = $1 FOR UPDATE"
+	if err := q.QueryRow(query, id).Scan(&snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Record menyimpan satu entry audit log. before nil untuk create, after nil untuk delete;
+// changes dihitung dari kolom yang berbeda antara keduanya.
+func Record(q Querier, actor Actor, action, entityType string, entityID *int, before, after json.RawMessage) error {
+	changes, err := json.Marshal(Diff(before, after))
+	if err != nil {
+		return err
+	}
+
+	var requestID *string
+	if actor.RequestID != "" {
+		requestID = &actor.RequestID
+	}
+
+	_, err = q.Exec(`
+		INSERT INTO audit_log (actor_id, action, entity_type, entity_id, before, after, changes, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+	`, actor.UserID, action, entityType, entityID, nullJSON(before), nullJSON(after), changes, requestID)
+	return err
+}
+
+// WithTx menjalankan mutasi satu baris dalam transaksi dan mencatatnya ke audit log.
+// Untuk UPDATE/DELETE snapshot sebelum diambil lebih dulu (sql.ErrNoRows jika id tidak ada);
+// untuk CREATE mutate mengembalikan id baris baru. Mengembalikan id entity.
+func WithTx(db *sql.DB, actor Actor, action, table string, id int, mutate func(tx *sql.Tx) (int, error)) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var before, after json.RawMessage
+	if action != ActionCreate {
+		if before, err = Snapshot(tx, table, id); err != nil {
+			return 0, err
+		}
+	}
+
+	newID, err := mutate(tx)
+	if err != nil {
+		return 0, err
+	}
+	if action == ActionCreate {
+		id = newID
+	}
+
+	// Setelah hard delete baris sudah tidak ada; soft delete tetap punya snapshot sesudah
+	after, err = Snapshot(tx, table, id)
+	if err != nil && !(err == sql.ErrNoRows && action == ActionDelete) {
+		return 0, err
+	}
+
+	if err := Record(tx, actor, action, table, &id, before, after); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// Diff membandingkan dua snapshot JSON object dan mengembalikan kolom yang berubah
+func Diff(before, after json.RawMessage) map[string]Change {
+	oldValues := map[string]interface{}{}
+	newValues := map[string]interface{}{}
+	json.Unmarshal(before, &oldValues)
+	json.Unmarshal(after, &newValues)
+
+	changes := map[string]Change{}
+	for key, oldValue := range oldValues {
+		if newValue, ok := newValues[key]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes[key] = Change{From: oldValue, To: newValues[key]}
+		}
+	}
+	for key, newValue := range newValues {
+		if _, ok := oldValues[key]; !ok {
+			changes[key] = Change{From: nil, To: newValue}
+		}
+	}
+	return changes
+}
+
+// nullJSON mengubah snapshot kosong menjadi NULL di database
+func nullJSON(value json.RawMessage) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	return []byte(value)
+}
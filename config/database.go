@@ -10,19 +10,22 @@ import (
 
 var DB *sql.DB
 
-// InitDB menginisialisasi koneksi database PostgreSQL
-func InitDB() {
+// ConnString connection string PostgreSQL, dipakai juga oleh listener LISTEN/NOTIFY
+func ConnString() string {
 	host := "localhost"
 	port := 5432
 	user := "postgres"
 	password := "student"
 	dbname := "mkp_ticketing"
 
-	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		host, port, user, password, dbname)
+}
 
+// InitDB menginisialisasi koneksi database PostgreSQL
+func InitDB() {
 	var err error
-	DB, err = sql.Open("postgres", psqlInfo)
+	DB, err = sql.Open("postgres", ConnString())
 	if err != nil {
 		log.Fatal("Error opening database:", err)
 	}
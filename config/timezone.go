@@ -0,0 +1,35 @@
+package config
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultTimezone timezone cinema jika belum diset (WIB)
+const DefaultTimezone = "Asia/Jakarta"
+
+var locationCache sync.Map
+
+// LoadLocation mengembalikan *time.Location untuk nama timezone IANA.
+// Nama kosong atau tidak dikenal akan fallback ke DefaultTimezone.
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	if loc, ok := locationCache.Load(name); ok {
+		return loc.(*time.Location)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("Unknown timezone %q, falling back to %s", name, DefaultTimezone)
+		if name == DefaultTimezone {
+			return time.UTC
+		}
+		return LoadLocation(DefaultTimezone)
+	}
+
+	locationCache.Store(name, loc)
+	return loc
+}
@@ -1,8 +1,13 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 CREATE TABLE "users" (
   "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
   "fullname" varchar NOT NULL,
   "email" varchar UNIQUE NOT NULL,
   "password_hash" varchar NOT NULL,
+  "role" varchar NOT NULL DEFAULT 'CUSTOMER',
+  "calendar_token" varchar UNIQUE,
+  "locale" varchar NOT NULL DEFAULT 'id',
   "created_at" timestamp DEFAULT (now()),
   "updated_at" timestamp DEFAULT (now())
 );
@@ -13,6 +18,10 @@ CREATE TABLE "movies" (
   "description" text,
   "duration_minutes" integer NOT NULL,
   "release_date" date,
+  "search_vector" tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce("title", '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce("description", '')), 'B')
+  ) STORED,
   "created_at" timestamp DEFAULT (now())
 );
 
@@ -21,6 +30,7 @@ CREATE TABLE "cinemas" (
   "name" varchar NOT NULL,
   "city" varchar NOT NULL,
   "address" text,
+  "timezone" varchar NOT NULL DEFAULT 'Asia/Jakarta',
   "created_at" timestamp
 );
 
@@ -29,6 +39,7 @@ CREATE TABLE "studios" (
   "cinema_id" integer NOT NULL,
   "name" varchar NOT NULL,
   "total_seats" integer NOT NULL,
+  "studio_type" varchar NOT NULL DEFAULT 'REGULAR',
   "created_at" timestamp
 );
 
@@ -36,25 +47,31 @@ CREATE TABLE "seats" (
   "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
   "studio_id" integer NOT NULL,
   "row_code" char(1) NOT NULL,
-  "seat_number" integer NOT NULL
+  "seat_number" integer NOT NULL,
+  "category" varchar NOT NULL DEFAULT 'REGULAR'
 );
 
 CREATE TABLE "schedules" (
   "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
   "movie_id" integer NOT NULL,
   "studio_id" integer NOT NULL,
-  "start_time" timestamp NOT NULL,
-  "end_time" timestamp NOT NULL,
+  "start_time" timestamptz NOT NULL,
+  "end_time" timestamptz NOT NULL,
   "price" decimal(10,2) NOT NULL,
   "status" varchar DEFAULT 'SHOWING',
-  "created_at" timestamp
+  "created_at" timestamp,
+  "deleted_at" timestamptz,
+  "version" integer NOT NULL DEFAULT 1
 );
 
 CREATE TABLE "transactions" (
   "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
   "user_id" integer,
   "schedule_id" integer,
+  "subtotal_amount" decimal(10,2),
+  "discount_amount" decimal(10,2) NOT NULL DEFAULT 0,
   "total_amount" decimal(10,2) NOT NULL,
+  "promotion_id" integer,
   "payment_method" varchar,
   "payment_time" timestamp,
   "status" varchar DEFAULT 'PENDING',
@@ -71,13 +88,198 @@ CREATE TABLE "tickets" (
   "created_at" timestamp
 );
 
+CREATE TABLE "holidays" (
+  "holiday_date" date PRIMARY KEY,
+  "name" varchar NOT NULL
+);
+
+CREATE TABLE "pricing_rules" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "days_of_week" integer[],
+  "time_from" time,
+  "time_to" time,
+  "studio_type" varchar,
+  "seat_category" varchar,
+  "holiday_only" boolean NOT NULL DEFAULT false,
+  "adjustment_type" varchar NOT NULL,
+  "adjustment_value" decimal(10,2) NOT NULL,
+  "priority" integer NOT NULL DEFAULT 0,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "promotions" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "code" varchar UNIQUE NOT NULL,
+  "description" text,
+  "discount_type" varchar NOT NULL,
+  "discount_value" decimal(10,2) NOT NULL,
+  "max_discount" decimal(10,2),
+  "min_spend" decimal(10,2) NOT NULL DEFAULT 0,
+  "valid_from" timestamptz NOT NULL,
+  "valid_until" timestamptz NOT NULL,
+  "usage_limit" integer,
+  "usage_limit_per_user" integer,
+  "used_count" integer NOT NULL DEFAULT 0,
+  "movie_ids" integer[],
+  "cinema_ids" integer[],
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "promotion_usages" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "promotion_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "transaction_id" integer NOT NULL,
+  "discount_amount" decimal(10,2) NOT NULL,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "audit_log" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "actor_id" integer,
+  "action" varchar NOT NULL,
+  "entity_type" varchar NOT NULL,
+  "entity_id" integer,
+  "before" jsonb,
+  "after" jsonb,
+  "changes" jsonb NOT NULL DEFAULT '{}',
+  "request_id" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "idempotency_keys" (
+  "user_id" integer NOT NULL,
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response_status" integer,
+  "response_content_type" varchar,
+  "response_body" bytea,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz,
+  PRIMARY KEY ("user_id", "key")
+);
+
+CREATE TABLE "waitlist_entries" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "schedule_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "seats" integer NOT NULL CHECK ("seats" > 0),
+  "status" varchar NOT NULL DEFAULT 'WAITING',
+  "offered_at" timestamptz,
+  "offer_expires_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "notification_outbox" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "template" varchar NOT NULL,
+  "data" jsonb NOT NULL DEFAULT '{}',
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" text,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz
+);
+
+CREATE TABLE "webhook_subscriptions" (
+  "id" SERIAL PRIMARY KEY,
+  "url" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "event_types" varchar[] NOT NULL,
+  "secret" varchar NOT NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "subscription_id" integer NOT NULL,
+  "event_id" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_status_code" integer,
+  "last_error" text,
+  "last_attempt_at" timestamptz,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "redelivery_of" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz
+);
+
+CREATE TABLE "schema_migrations" (
+  "version" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "applied_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "promotion_usages" ("promotion_id", "user_id");
+
+CREATE INDEX ON "schedules" ("start_time") WHERE "deleted_at" IS NULL;
+
+CREATE INDEX ON "audit_log" ("entity_type", "entity_id");
+CREATE INDEX ON "audit_log" ("actor_id");
+CREATE INDEX ON "audit_log" ("created_at");
+
+CREATE INDEX ON "idempotency_keys" ("created_at");
+
+CREATE UNIQUE INDEX ON "waitlist_entries" ("schedule_id", "user_id") WHERE "status" IN ('WAITING', 'OFFERED');
+CREATE INDEX ON "waitlist_entries" ("schedule_id", "status", "created_at");
+
+CREATE INDEX ON "notification_outbox" ("next_attempt_at") WHERE "status" = 'PENDING';
+
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'PENDING';
+
+CREATE INDEX ON "webhook_deliveries" ("subscription_id", "id");
+
+CREATE INDEX ON "movies" USING gin ("search_vector");
+CREATE INDEX ON "movies" USING gin (lower("title") gin_trgm_ops);
+CREATE INDEX ON "movies" USING gin (regexp_replace(lower("title"), '[^a-z0-9]+', '', 'g') gin_trgm_ops);
+CREATE INDEX ON "movies" USING gin (lower("description") gin_trgm_ops);
+
+COMMENT ON COLUMN "users"."role" IS 'CUSTOMER, ADMIN';
+COMMENT ON COLUMN "users"."locale" IS 'Bahasa notifikasi: id, en';
+COMMENT ON COLUMN "users"."calendar_token" IS 'Token feed iCalendar tiket, NULL = belum pernah dibuat';
 COMMENT ON COLUMN "cinemas"."name" IS 'Cabang Bioskop, misal: MKP XXI';
+COMMENT ON COLUMN "cinemas"."timezone" IS 'Timezone IANA, misal: Asia/Jakarta (WIB), Asia/Makassar (WITA)';
 COMMENT ON COLUMN "studios"."name" IS 'Nama Studio, misal: Studio 1, IMAX';
+COMMENT ON COLUMN "studios"."studio_type" IS 'REGULAR, IMAX, PREMIERE';
 COMMENT ON COLUMN "seats"."row_code" IS 'Baris A, B, C';
 COMMENT ON COLUMN "seats"."seat_number" IS 'Nomor 1, 2, 3';
+COMMENT ON COLUMN "seats"."category" IS 'REGULAR, PREMIUM, COUPLE';
 COMMENT ON COLUMN "schedules"."status" IS 'SHOWING, CANCELLED, ENDED';
+COMMENT ON COLUMN "schedules"."deleted_at" IS 'Waktu soft delete, NULL = aktif';
+COMMENT ON COLUMN "schedules"."version" IS 'Naik setiap update, dipakai sebagai ETag';
 COMMENT ON COLUMN "transactions"."status" IS 'PENDING, PAID, CANCELLED, REFUNDED';
+COMMENT ON COLUMN "transactions"."total_amount" IS 'Total setelah diskon';
 COMMENT ON COLUMN "tickets"."price" IS 'Harga saat beli';
+COMMENT ON COLUMN "pricing_rules"."days_of_week" IS '0 = Minggu ... 6 = Sabtu, NULL = semua hari';
+COMMENT ON COLUMN "pricing_rules"."time_from" IS 'Jam mulai tayang (waktu lokal cinema), misal: 17:00 untuk prime time';
+COMMENT ON COLUMN "pricing_rules"."adjustment_type" IS 'PERCENTAGE, FIXED';
+COMMENT ON COLUMN "audit_log"."action" IS 'CREATE, UPDATE, DELETE, IMPORT';
+COMMENT ON COLUMN "audit_log"."entity_type" IS 'Nama tabel, misal: schedules, pricing_rules, promotions';
+COMMENT ON COLUMN "audit_log"."changes" IS 'Kolom yang berubah: {"kolom": {"from": ..., "to": ...}}';
+COMMENT ON COLUMN "idempotency_keys"."request_hash" IS 'SHA-256 dari method, path dan body request pertama';
+COMMENT ON COLUMN "idempotency_keys"."response_status" IS 'NULL = request pertama masih diproses';
+COMMENT ON COLUMN "waitlist_entries"."status" IS 'WAITING, OFFERED, CLAIMED, EXPIRED, CANCELLED';
+COMMENT ON COLUMN "notification_outbox"."template" IS 'booking_confirmed, booking_cancelled, schedule_changed, schedule_cancelled, waitlist_offer';
+COMMENT ON COLUMN "notification_outbox"."status" IS 'PENDING, SENT, FAILED';
+COMMENT ON COLUMN "waitlist_entries"."offer_expires_at" IS 'Batas klaim kursi yang ditawarkan, setelah itu ditawarkan ke antrian berikutnya';
+COMMENT ON COLUMN "webhook_subscriptions"."event_types" IS 'schedule.created, schedule.updated, schedule.cancelled, ticket.sold';
+COMMENT ON COLUMN "webhook_subscriptions"."secret" IS 'Kunci HMAC-SHA256 untuk header X-MKP-Signature';
+COMMENT ON COLUMN "webhook_deliveries"."event_id" IS 'Sama untuk semua delivery dari satu event, dipakai partner untuk deduplikasi';
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'PENDING, DELIVERED, DEAD';
+COMMENT ON COLUMN "webhook_deliveries"."redelivery_of" IS 'Delivery asal jika dibuat lewat endpoint redeliver';
 
 ALTER TABLE "studios" ADD FOREIGN KEY ("cinema_id") REFERENCES "cinemas" ("id");
 ALTER TABLE "seats" ADD FOREIGN KEY ("studio_id") REFERENCES "studios" ("id");
@@ -88,3 +290,14 @@ ALTER TABLE "transactions" ADD FOREIGN KEY ("schedule_id") REFERENCES "schedules
 ALTER TABLE "tickets" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
 ALTER TABLE "tickets" ADD FOREIGN KEY ("schedule_id") REFERENCES "schedules" ("id");
 ALTER TABLE "tickets" ADD FOREIGN KEY ("seat_id") REFERENCES "seats" ("id");
+ALTER TABLE "transactions" ADD FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id");
+ALTER TABLE "promotion_usages" ADD FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id");
+ALTER TABLE "promotion_usages" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "promotion_usages" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+ALTER TABLE "audit_log" ADD FOREIGN KEY ("actor_id") REFERENCES "users" ("id");
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "waitlist_entries" ADD FOREIGN KEY ("schedule_id") REFERENCES "schedules" ("id");
+ALTER TABLE "waitlist_entries" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "notification_outbox" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE;
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("redelivery_of") REFERENCES "webhook_deliveries" ("id") ON DELETE SET NULL;
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+// All nama khusus untuk subscribe ke semua event
+const All = "*"
+
+// Event peristiwa domain yang sudah terjadi. Event hanya di-publish setelah transaksi
+// database-nya commit, jadi subscriber tidak pernah melihat perubahan yang di-rollback.
+type Event interface {
+	EventName() string
+}
+
+// Handler dipanggil untuk setiap event yang cocok. Handler tidak bisa membatalkan perubahan
+// yang sudah commit; error cukup di-log oleh handler sendiri.
+type Handler func(ctx context.Context, event Event)
+
+type subscriber struct {
+	handler Handler
+	async   bool
+}
+
+// Bus event bus in-process
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+	wg          sync.WaitGroup
+}
+
+// New membuat bus kosong
+func New() *Bus {
+	return &Bus{subscribers: map[string][]subscriber{}}
+}
+
+// Subscribe mendaftarkan handler synchronous: dijalankan berurutan di goroutine publisher
+// sebelum Publish kembali, cocok untuk pekerjaan singkat seperti invalidasi cache.
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.add(name, subscriber{handler: handler})
+}
+
+// SubscribeAsync mendaftarkan handler yang dijalankan di goroutine sendiri, sehingga
+// response HTTP tidak menunggu handler selesai.
+func (b *Bus) SubscribeAsync(name string, handler Handler) {
+	b.add(name, subscriber{handler: handler, async: true})
+}
+
+func (b *Bus) add(name string, s subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[name] = append(b.subscribers[name], s)
+}
+
+// Publish mengirim event ke subscriber sesuai urutan subscribe; subscriber All dipanggil
+// setelah subscriber nama event. Panic di handler di-recover agar publisher tetap jalan.
+// Handler async menerima context tanpa cancel karena request asal bisa sudah selesai.
+func (b *Bus) Publish(ctx context.Context, events ...Event) {
+	for _, event := range events {
+		b.mu.RLock()
+		subscribers := append(append([]subscriber{}, b.subscribers[event.EventName()]...), b.subscribers[All]...)
+		b.mu.RUnlock()
+
+		for _, s := range subscribers {
+			if !s.async {
+				call(ctx, s.handler, event)
+				continue
+			}
+			b.wg.Add(1)
+			go func(handler Handler, event Event) {
+				defer b.wg.Done()
+				call(context.WithoutCancel(ctx), handler, event)
+			}(s.handler, event)
+		}
+	}
+}
+
+// Wait menunggu semua handler async yang sedang berjalan selesai
+func (b *Bus) Wait() {
+	b.wg.Wait()
+}
+
+func call(ctx context.Context, handler Handler, event Event) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Printf("event %s: subscriber panic: %v\n%s", event.EventName(), err, debug.Stack())
+		}
+	}()
+	handler(ctx, event)
+}
+
+// Default bus yang dipakai handler HTTP dan worker
+var Default = New()
+
+// Subscribe mendaftarkan handler synchronous di Default
+func Subscribe(name string, handler Handler) {
+	Default.Subscribe(name, handler)
+}
+
+// SubscribeAsync mendaftarkan handler async di Default
+func SubscribeAsync(name string, handler Handler) {
+	Default.SubscribeAsync(name, handler)
+}
+
+// Publish mengirim event lewat Default; dipanggil setelah transaksi commit
+func Publish(ctx context.Context, events ...Event) {
+	Default.Publish(ctx, events...)
+}
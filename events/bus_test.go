@@ -0,0 +1,69 @@
+package events_test
+
+import (
+	"context"
+	"mkp/events"
+	"mkp/events/eventstest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPublishDefault(t *testing.T) {
+	rec := eventstest.Capture(t)
+
+	events.Publish(context.Background(),
+		events.ScheduleCreated{ScheduleID: 1},
+		events.ScheduleUpdated{ScheduleID: 1, Version: 2},
+		events.ScheduleCancelled{ScheduleID: 1},
+	)
+
+	rec.AssertEmitted(t, events.ScheduleCreated{ScheduleID: 1}, events.ScheduleCancelled{ScheduleID: 1})
+	rec.AssertCount(t, events.NameScheduleUpdated, 1)
+	rec.AssertNotEmitted(t, events.NameScheduleRestored)
+}
+
+func TestPublishOrder(t *testing.T) {
+	bus := events.New()
+	order := []string{}
+	bus.Subscribe(events.All, func(ctx context.Context, event events.Event) {
+		order = append(order, "all")
+	})
+	bus.Subscribe(events.NameScheduleCreated, func(ctx context.Context, event events.Event) {
+		order = append(order, "named")
+	})
+
+	bus.Publish(context.Background(), events.ScheduleCreated{ScheduleID: 1})
+
+	if len(order) != 2 || order[0] != "named" || order[1] != "all" {
+		t.Errorf("subscriber order = %v, want [named all]", order)
+	}
+}
+
+func TestPublishRecoversPanic(t *testing.T) {
+	bus := events.New()
+	bus.Subscribe(events.NameUserRegistered, func(ctx context.Context, event events.Event) {
+		panic("boom")
+	})
+	rec := eventstest.Record(bus)
+
+	bus.Publish(context.Background(), events.UserRegistered{UserID: 7, Email: "a@mkp.id"})
+
+	rec.AssertEmitted(t, events.UserRegistered{UserID: 7, Email: "a@mkp.id"})
+}
+
+func TestPublishAsync(t *testing.T) {
+	bus := events.New()
+	var calls atomic.Int32
+	bus.SubscribeAsync(events.NameTicketPurchased, func(ctx context.Context, event events.Event) {
+		calls.Add(1)
+	})
+	rec := eventstest.Record(bus)
+
+	bus.Publish(context.Background(), events.TicketPurchased{TransactionID: 1}, events.TicketPurchased{TransactionID: 2})
+	rec.Wait()
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("async handler called %d times, want 2", n)
+	}
+	rec.AssertEmitted(t, events.TicketPurchased{TransactionID: 1}, events.TicketPurchased{TransactionID: 2})
+}
@@ -0,0 +1,90 @@
+package events
+
+// Nama event domain
+const (
+	NameScheduleCreated   = "schedule.created"
+	NameScheduleUpdated   = "schedule.updated"
+	NameScheduleCancelled = "schedule.cancelled"
+	NameScheduleRestored  = "schedule.restored"
+	NameBookingCreated    = "booking.created"
+	NameTicketPurchased   = "ticket.purchased"
+	NameBookingCancelled  = "booking.cancelled"
+	NameUserRegistered    = "user.registered"
+)
+
+// ScheduleCreated jadwal tayang baru dibuat (satuan maupun bulk)
+type ScheduleCreated struct {
+	ScheduleID int
+}
+
+// EventName implementasi Event
+func (ScheduleCreated) EventName() string { return NameScheduleCreated }
+
+// ScheduleUpdated isi jadwal diganti lewat PUT atau PATCH
+type ScheduleUpdated struct {
+	ScheduleID int
+	Version    int
+}
+
+// EventName implementasi Event
+func (ScheduleUpdated) EventName() string { return NameScheduleUpdated }
+
+// ScheduleCancelled jadwal dibatalkan (status CANCELLED) atau di-soft delete (Deleted true)
+type ScheduleCancelled struct {
+	ScheduleID int
+	Deleted    bool
+}
+
+// EventName implementasi Event
+func (ScheduleCancelled) EventName() string { return NameScheduleCancelled }
+
+// ScheduleRestored jadwal yang di-soft delete dipulihkan
+type ScheduleRestored struct {
+	ScheduleID int
+}
+
+// EventName implementasi Event
+func (ScheduleRestored) EventName() string { return NameScheduleRestored }
+
+// BookingCreated booking PENDING dibuat dan kursinya ditahan
+type BookingCreated struct {
+	TransactionID int
+	UserID        int
+	ScheduleID    int
+	SeatIDs       []int
+}
+
+// EventName implementasi Event
+func (BookingCreated) EventName() string { return NameBookingCreated }
+
+// TicketPurchased booking dibayar dan tiketnya terjual
+type TicketPurchased struct {
+	TransactionID int
+	UserID        int
+	ScheduleID    int
+	TotalAmount   float64
+}
+
+// EventName implementasi Event
+func (TicketPurchased) EventName() string { return NameTicketPurchased }
+
+// BookingCancelled booking dibatalkan oleh user atau karena hold-nya habis.
+// Status CANCELLED untuk booking PENDING, REFUNDED untuk booking PAID.
+type BookingCancelled struct {
+	TransactionID int
+	UserID        int
+	ScheduleID    int
+	Status        string
+}
+
+// EventName implementasi Event
+func (BookingCancelled) EventName() string { return NameBookingCancelled }
+
+// UserRegistered user baru mendaftar
+type UserRegistered struct {
+	UserID int
+	Email  string
+}
+
+// EventName implementasi Event
+func (UserRegistered) EventName() string { return NameUserRegistered }
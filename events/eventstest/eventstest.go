@@ -0,0 +1,134 @@
+// Package eventstest membantu test mengecek event domain yang di-publish, mirip httptest.
+//
+//	rec := eventstest.Capture(t)
+//	handlers.CreateSchedule(w, req)
+//	rec.AssertEmitted(t, events.ScheduleCreated{ScheduleID: 1})
+package eventstest
+
+import (
+	"context"
+	"fmt"
+	"mkp/events"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Recorder menyimpan semua event yang di-publish ke sebuah bus sesuai urutan
+type Recorder struct {
+	mu     sync.Mutex
+	bus    *events.Bus
+	events []events.Event
+}
+
+// Record subscribe (synchronous) ke semua event di bus
+func Record(bus *events.Bus) *Recorder {
+	rec := &Recorder{bus: bus}
+	bus.Subscribe(events.All, func(ctx context.Context, event events.Event) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.events = append(rec.events, event)
+	})
+	return rec
+}
+
+// Capture mengganti events.Default dengan bus baru selama test dan merekam isinya.
+// Default lama dikembalikan saat test selesai, setelah handler async di bus baru selesai.
+// Test yang memakai Capture tidak boleh berjalan paralel dengan test lain yang publish ke Default.
+func Capture(t testing.TB) *Recorder {
+	t.Helper()
+	previous := events.Default
+	bus := events.New()
+	events.Default = bus
+	t.Cleanup(func() {
+		bus.Wait()
+		events.Default = previous
+	})
+	return Record(bus)
+}
+
+// Events salinan event yang sudah terekam
+func (r *Recorder) Events() []events.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]events.Event{}, r.events...)
+}
+
+// Names nama event yang sudah terekam, sesuai urutan
+func (r *Recorder) Names() []string {
+	names := []string{}
+	for _, event := range r.Events() {
+		names = append(names, event.EventName())
+	}
+	return names
+}
+
+// Reset mengosongkan rekaman, misal setelah menyiapkan data awal test
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// Wait menunggu handler async di bus selesai, untuk test yang juga mengecek efek subscriber async
+func (r *Recorder) Wait() {
+	r.bus.Wait()
+}
+
+// AssertEmitted gagal jika want tidak muncul sesuai urutannya (event lain boleh ada di antaranya).
+// Event dibandingkan dengan reflect.DeepEqual.
+func (r *Recorder) AssertEmitted(t testing.TB, want ...events.Event) {
+	t.Helper()
+	got := r.Events()
+	i := 0
+	for _, event := range got {
+		if i < len(want) && reflect.DeepEqual(event, want[i]) {
+			i++
+		}
+	}
+	if i < len(want) {
+		t.Errorf("event %s not emitted\nwant (in order): %s\ngot: %s", describe(want[i]), describeAll(want), describeAll(got))
+	}
+}
+
+// AssertNotEmitted gagal jika ada event dengan salah satu nama tersebut
+func (r *Recorder) AssertNotEmitted(t testing.TB, names ...string) {
+	t.Helper()
+	for _, event := range r.Events() {
+		for _, name := range names {
+			if event.EventName() == name {
+				t.Errorf("unexpected event %s", describe(event))
+			}
+		}
+	}
+}
+
+// AssertCount gagal jika jumlah event dengan nama tersebut bukan n
+func (r *Recorder) AssertCount(t testing.TB, name string, n int) {
+	t.Helper()
+	count := 0
+	for _, event := range r.Events() {
+		if event.EventName() == name {
+			count++
+		}
+	}
+	if count != n {
+		t.Errorf("event %s emitted %d times, want %d", name, count, n)
+	}
+}
+
+func describe(event events.Event) string {
+	return fmt.Sprintf("%s %+v", event.EventName(), event)
+}
+
+func describeAll(list []events.Event) string {
+	if len(list) == 0 {
+		return "(none)"
+	}
+	parts := []string{}
+	for _, event := range list {
+		parts = append(parts, describe(event))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
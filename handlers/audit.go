@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/validation"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAuditLogLimit batas jumlah entry per halaman audit log
+const maxAuditLogLimit = 200
+
+// withAudit menjalankan mutasi satu baris dalam transaksi dan mencatatnya ke audit log atas nama
+// user yang sedang login, lihat audit.WithTx. Mengembalikan id entity.
+func withAudit(r *http.Request, action, table string, id int, mutate func(tx *sql.Tx) (int, error)) (int, error) {
+	return audit.WithTx(config.DB, audit.FromRequest(r), action, table, id, mutate)
+}
+
+// recordCreated mencatat baris yang baru di-insert di dalam transaksi yang sedang berjalan
+func recordCreated(tx *sql.Tx, r *http.Request, table string, id int) error {
+	after, err := audit.Snapshot(tx, table, id)
+	if err != nil {
+		return err
+	}
+	return audit.Record(tx, audit.FromRequest(r), audit.ActionCreate, table, &id, nil, after)
+}
+
+// GetAuditLog handler untuk melihat audit log (admin), terbaru lebih dulu.
+// Filter: entity_type, entity_id, actor_id, action, request_id, from, to (RFC 3339 atau YYYY-MM-DD).
+// Paginasi dengan limit dan before_id (id entry terakhir halaman sebelumnya).
+func GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	fieldErrors := []apierror.FieldError{}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	for _, filter := range []struct{ param, column string }{
+		{"entity_type", "a.entity_type"},
+		{"request_id", "a.request_id"},
+	} {
+		if value := strings.TrimSpace(params.Get(filter.param)); value != "" {
+			addCondition(filter.column+" = ?", value)
+		}
+	}
+	if action := params.Get("action"); action != "" {
+		addCondition("a.action = ?", strings.ToUpper(action))
+	}
+	for _, filter := range []struct{ param, condition string }{
+		{"entity_id", "a.entity_id = ?"},
+		{"actor_id", "a.actor_id = ?"},
+		{"before_id", "a.id < ?"},
+	} {
+		value := params.Get(filter.param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: filter.param, Code: validation.CodeOutOfRange, Message: filter.param + " must be a positive integer",
+			})
+			continue
+		}
+		addCondition(filter.condition, id)
+	}
+	for _, filter := range []struct{ param, operator string }{
+		{"from", ">="},
+		{"to", "<"},
+	} {
+		value := params.Get(filter.param)
+		if value == "" {
+			continue
+		}
+		t, err := parseAuditTime(value)
+		if err != nil {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: filter.param, Code: validation.CodeInvalidDateTime, Message: filter.param + " must be RFC 3339 or YYYY-MM-DD",
+			})
+			continue
+		}
+		// Tanggal tanpa jam pada "to" berarti sampai akhir hari tersebut
+		if filter.param == "to" && len(value) == len(validation.DateLayout) {
+			t = t.AddDate(0, 0, 1)
+		}
+		addCondition("a.created_at "+filter.operator+" ?", t)
+	}
+
+	limit := 50
+	if value := params.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxAuditLogLimit {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: "limit", Code: validation.CodeOutOfRange, Message: fmt.Sprintf("limit must be between 1 and %d", maxAuditLogLimit),
+			})
+		}
+		limit = n
+	}
+
+	if len(fieldErrors) > 0 {
+		apierror.Write(w, r, validation.Problem(fieldErrors))
+		return
+	}
+
+	args = append(args, limit)
+	query := `
+		SELECT a.id, a.actor_id, u.email, a.action, a.entity_type, a.entity_id,
+			a.before, a.after, a.changes, a.request_id, a.created_at
+		FROM audit_log a
+		LEFT JOIN users u ON a.actor_id = u.id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY a.id DESC
+		LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	entries := []audit.Entry{}
+	for rows.Next() {
+		var entry audit.Entry
+		var before, after, changes []byte
+		err := rows.Scan(&entry.ID, &entry.ActorID, &entry.ActorEmail, &entry.Action, &entry.EntityType, &entry.EntityID,
+			&before, &after, &changes, &entry.RequestID, &entry.CreatedAt)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		entry.Before = nullableJSON(before)
+		entry.After = nullableJSON(after)
+		json.Unmarshal(changes, &entry.Changes)
+		entries = append(entries, entry)
+	}
+
+	respondWithJSON(w, http.StatusOK, entries)
+}
+
+// parseAuditTime menerima waktu RFC 3339 / YYYY-MM-DD HH:MM:SS atau tanggal saja (timezone default)
+func parseAuditTime(value string) (time.Time, error) {
+	loc := config.LoadLocation("")
+	if t, err := time.ParseInLocation(validation.DateLayout, value, loc); err == nil {
+		return t, nil
+	}
+	return validation.ParseDateTimeIn(value, loc)
+}
+
+// nullableJSON kolom jsonb NULL ditampilkan sebagai null
+func nullableJSON(value []byte) json.RawMessage {
+	if len(value) == 0 {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -3,9 +3,13 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"mkp/account"
+	"mkp/apierror"
+	"mkp/audit"
 	"mkp/config"
 	"mkp/middleware"
 	"mkp/models"
+	"mkp/validation"
 	"net/http"
 	"time"
 
@@ -16,78 +20,29 @@ import (
 // Register handler untuk registrasi user baru
 func Register(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	var input struct {
-		Fullname string `json:"fullname"`
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
-
-	err := json.NewDecoder(r.Body).Decode(&input)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	// Validasi input
-	if input.Fullname == "" {
-		respondWithError(w, http.StatusBadRequest, "Fullname is required")
-		return
-	}
-	if input.Email == "" {
-		respondWithError(w, http.StatusBadRequest, "Email is required")
-		return
-	}
-	if input.Password == "" {
-		respondWithError(w, http.StatusBadRequest, "Password is required")
-		return
-	}
-	if len(input.Password) < 6 {
-		respondWithError(w, http.StatusBadRequest, "Password must be at least 6 characters")
-		return
-	}
-
-	// Cek apakah email sudah terdaftar
-	var exists bool
-	checkQuery := "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)"
-	err = config.DB.QueryRow(checkQuery, input.Email).Scan(&exists)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Database error")
-		return
-	}
-	if exists {
-		respondWithError(w, http.StatusConflict, "Email already registered")
+	var input models.RegisterRequest
+	if !decodeAndValidate(w, r, &input) {
 		return
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error hashing password")
+	user, err := account.Create(r.Context(), config.DB, audit.FromRequest(r), input, models.RoleCustomer)
+	if err == account.ErrEmailTaken {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeEmailAlreadyRegistered, "Email already registered")
 		return
 	}
-
-	// Insert user baru ke database
-	var user models.User
-	query := `
-		INSERT INTO users (fullname, email, password_hash, created_at, updated_at) 
-		VALUES ($1, $2, $3, NOW(), NOW()) 
-		RETURNING id, fullname, email, created_at, updated_at
-	`
-	err = config.DB.QueryRow(query, input.Fullname, input.Email, string(hashedPassword)).
-		Scan(&user.ID, &user.Fullname, &user.Email, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error creating user")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error creating user")
 		return
 	}
 
 	// Generate JWT token
-	token, err := generateJWT(user.ID, user.Email)
+	token, err := generateJWT(user.ID, user.Email, user.Role)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeInternalError, "Failed to generate token")
 		return
 	}
 
@@ -102,50 +57,46 @@ func Register(w http.ResponseWriter, r *http.Request) {
 // Login handler untuk autentikasi user
 func Login(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req models.LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if req.Email == "" || req.Password == "" {
-		respondWithError(w, http.StatusBadRequest, "Email and password are required")
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	var user models.User
-	query := "SELECT id, fullname, email, password_hash, created_at, updated_at FROM users WHERE email = $1"
+	query := "SELECT id, fullname, email, password_hash, role, locale, created_at, updated_at FROM users WHERE email = $1"
 	err := config.DB.QueryRow(query, req.Email).Scan(
 		&user.ID,
 		&user.Fullname,
 		&user.Email,
 		&user.PasswordHash,
+		&user.Role,
+		&user.Locale,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
-		respondWithError(w, http.StatusUnauthorized, "Invalid email or password")
+		respondWithError(w, r, http.StatusUnauthorized, apierror.CodeInvalidCredentials, "Invalid email or password")
 		return
 	}
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Database error")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
 		return
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Invalid email or password")
+		respondWithError(w, r, http.StatusUnauthorized, apierror.CodeInvalidCredentials, "Invalid email or password")
 		return
 	}
 
-	token, err := generateJWT(user.ID, user.Email)
+	token, err := generateJWT(user.ID, user.Email, user.Role)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeInternalError, "Failed to generate token")
 		return
 	}
 
@@ -158,7 +109,7 @@ func Login(w http.ResponseWriter, r *http.Request) {
 }
 
 // generateJWT membuat JWT token untuk user
-func generateJWT(userID int, email string) (string, error) {
+func generateJWT(userID int, email, role string) (string, error) {
 	// Set expiration time (24 jam)
 	expirationTime := time.Now().Add(24 * time.Hour)
 
@@ -166,6 +117,7 @@ func generateJWT(userID int, email string) (string, error) {
 	claims := &middleware.Claims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -187,8 +139,7 @@ func generateJWT(userID int, email string) (string, error) {
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	response, err := json.Marshal(payload)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(`{"error": "Internal server error"}`))
+		apierror.Respond(w, nil, http.StatusInternalServerError, apierror.CodeInternalError, "Internal server error")
 		return
 	}
 
@@ -197,7 +148,23 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Write(response)
 }
 
-// Helper function untuk mengirim error response
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	respondWithJSON(w, code, map[string]string{"error": message})
+// Helper function untuk mengirim error response dalam format problem+json
+func respondWithError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	apierror.Respond(w, r, status, code, message)
+}
+
+// Helper function untuk decode JSON body lalu menjalankan validasi model.
+// Mengembalikan false jika response error sudah dikirim.
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidRequestBody, "Invalid request body")
+		return false
+	}
+
+	if errs := validation.Struct(dst); len(errs) > 0 {
+		apierror.Write(w, r, validation.Problem(errs))
+		return false
+	}
+
+	return true
 }
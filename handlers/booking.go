@@ -0,0 +1,538 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/events"
+	"mkp/middleware"
+	"mkp/models"
+	"mkp/notify"
+	"mkp/pricing"
+	"mkp/promo"
+	"mkp/seatstream"
+	"mkp/waitlist"
+	"mkp/webhook"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// transactionSelectQuery query dasar transaksi, dipakai bersama scanTransaction
+const transactionSelectQuery = `
+	SELECT tr.id, tr.user_id, tr.schedule_id, COALESCE(tr.subtotal_amount, tr.total_amount), tr.discount_amount,
+		tr.total_amount, tr.promotion_id, p.code, tr.payment_method, tr.payment_time, tr.status,
+		tr.created_at, tr.updated_at
+	FROM transactions tr
+	LEFT JOIN promotions p ON tr.promotion_id = p.id
+`
+
+// CreateBooking handler untuk memesan kursi pada sebuah jadwal.
+// Harga setiap tiket dihitung oleh pricing engine dan disimpan per tiket.
+func CreateBooking(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	var req models.BookingRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer tx.Rollback()
+
+	// Kunci baris jadwal agar booking paralel untuk jadwal yang sama diproses berurutan
+	var status string
+	var startTime time.Time
+	err = tx.QueryRow("SELECT status, start_time FROM schedules WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", req.ScheduleID).
+		Scan(&status, &startTime)
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	if status != models.ScheduleStatusShowing || !startTime.After(time.Now()) {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeScheduleNotBookable, "Schedule is not open for booking")
+		return
+	}
+
+	// Pastikan kursi belum dipesan transaksi lain yang masih aktif
+	var takenCount int
+	takenQuery := `
+		SELECT COUNT(*)
+		FROM tickets t
+		JOIN transactions tr ON t.transaction_id = tr.id
+		WHERE t.schedule_id = $1 AND t.seat_id = ANY($2) AND tr.status IN ($3, $4)
+	`
+	err = tx.QueryRow(takenQuery, req.ScheduleID, pq.Array(req.SeatIDs),
+		models.TransactionStatusPending, models.TransactionStatusPaid).Scan(&takenCount)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	if takenCount > 0 {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeSeatUnavailable, "One or more seats are already booked")
+		return
+	}
+
+	// Kursi yang sedang ditawarkan ke user waitlist lain tidak boleh diambil
+	available, err := waitlist.Available(tx, req.ScheduleID, userID)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	if available < len(req.SeatIDs) {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeSeatUnavailable,
+			"Not enough seats available, the remaining seats are held for waitlisted customers")
+		return
+	}
+
+	quote, err := pricing.Quote(tx, req.ScheduleID, req.SeatIDs)
+	if err == pricing.ErrSeatNotFound {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeSeatNotFound, "One or more seats do not exist in this studio")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	transaction := models.Transaction{
+		UserID:         userID,
+		ScheduleID:     req.ScheduleID,
+		SubtotalAmount: quote.Total,
+		TotalAmount:    quote.Total,
+		Status:         models.TransactionStatusPending,
+		Tickets:        []models.Ticket{},
+	}
+
+	// Terapkan promo code; hitungan pemakaian ikut di-rollback jika booking gagal
+	var applied *models.AppliedPromotion
+	if req.PromoCode != nil {
+		redeemed, err := promo.Redeem(tx, *req.PromoCode, userID, req.ScheduleID, quote.Total, time.Now())
+		if err != nil {
+			respondWithPromoError(w, r, err)
+			return
+		}
+		applied = &redeemed
+		transaction.DiscountAmount = redeemed.DiscountAmount
+		transaction.TotalAmount = quote.Total - redeemed.DiscountAmount
+		transaction.PromotionID = &redeemed.PromotionID
+		transaction.PromoCode = &redeemed.Code
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO transactions (user_id, schedule_id, subtotal_amount, discount_amount, total_amount,
+			promotion_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, userID, req.ScheduleID, transaction.SubtotalAmount, transaction.DiscountAmount, transaction.TotalAmount,
+		transaction.PromotionID, transaction.Status).
+		Scan(&transaction.ID, &transaction.CreatedAt, &transaction.UpdatedAt)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create booking")
+		return
+	}
+
+	if applied != nil {
+		if err := promo.RecordUsage(tx, *applied, userID, transaction.ID); err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create booking")
+			return
+		}
+	}
+
+	for _, seat := range quote.Seats {
+		ticket := models.Ticket{
+			TransactionID: transaction.ID,
+			ScheduleID:    req.ScheduleID,
+			SeatID:        seat.SeatID,
+			SeatLabel:     seat.SeatLabel,
+			Price:         seat.Price,
+		}
+		err = tx.QueryRow(`
+			INSERT INTO tickets (transaction_id, schedule_id, seat_id, price, created_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			RETURNING id, created_at
+		`, ticket.TransactionID, ticket.ScheduleID, ticket.SeatID, ticket.Price).Scan(&ticket.ID, &ticket.CreatedAt)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create booking")
+			return
+		}
+		transaction.Tickets = append(transaction.Tickets, ticket)
+	}
+
+	if err := waitlist.Claim(tx, req.ScheduleID, userID); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create booking")
+		return
+	}
+
+	if err := recordCreated(tx, r, "transactions", transaction.ID); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create booking")
+		return
+	}
+
+	if err := seatstream.Notify(tx, transaction.ID, seatstream.StateHeld); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create booking")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create booking")
+		return
+	}
+	events.Publish(r.Context(), events.BookingCreated{
+		TransactionID: transaction.ID,
+		UserID:        userID,
+		ScheduleID:    req.ScheduleID,
+		SeatIDs:       req.SeatIDs,
+	})
+
+	respondWithJSON(w, http.StatusCreated, transaction)
+}
+
+// GetMyBookings handler untuk mendapatkan semua booking milik user yang sedang login
+func GetMyBookings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	rows, err := config.DB.Query(transactionSelectQuery+" WHERE tr.user_id = $1 ORDER BY tr.created_at DESC", userID)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	transactions := []models.Transaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		transactions = append(transactions, transaction)
+	}
+	rows.Close()
+
+	for i := range transactions {
+		transactions[i].Tickets, err = loadTickets(config.DB, transactions[i].ID)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+			return
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, transactions)
+}
+
+// GetBookingByID handler untuk mendapatkan detail booking milik user yang sedang login
+func GetBookingByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/bookings/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid booking ID")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	transaction, err := scanTransaction(config.DB.QueryRow(transactionSelectQuery+" WHERE tr.id = $1 AND tr.user_id = $2", id, userID))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeBookingNotFound, "Booking not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	transaction.Tickets, err = loadTickets(config.DB, transaction.ID)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transaction)
+}
+
+// PayBooking handler untuk membayar booking PENDING milik user sebelum hold-nya habis.
+// Path: /api/bookings/{id}/pay. Belum ada integrasi payment gateway, pembayaran langsung
+// dicatat berhasil dan status menjadi PAID.
+func PayBooking(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(strings.TrimSuffix(r.URL.Path, "/pay"), "/api/bookings/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid booking ID")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	var req models.PaymentRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	purchased := events.TicketPurchased{TransactionID: id, UserID: userID}
+	_, err := withAudit(r, audit.ActionUpdate, "transactions", id, func(tx *sql.Tx) (int, error) {
+		var ownerID int
+		var status, scheduleStatus string
+		var startTime time.Time
+		var deleted, holdExpired bool
+		err := tx.QueryRow(`
+			SELECT tr.user_id, tr.status, tr.schedule_id, tr.total_amount, COALESCE(s.status, ''), s.start_time,
+				s.deleted_at IS NOT NULL, tr.created_at < NOW() - make_interval(secs => $2)
+			FROM transactions tr
+			JOIN schedules s ON tr.schedule_id = s.id
+			WHERE tr.id = $1
+		`, id, waitlist.HoldDuration.Seconds()).Scan(&ownerID, &status, &purchased.ScheduleID, &purchased.TotalAmount,
+			&scheduleStatus, &startTime, &deleted, &holdExpired)
+		if err != nil {
+			return id, err
+		}
+		if ownerID != userID {
+			return id, sql.ErrNoRows
+		}
+		if status != models.TransactionStatusPending || scheduleStatus != models.ScheduleStatusShowing ||
+			deleted || holdExpired || !startTime.After(time.Now()) {
+			return id, errBookingNotPayable
+		}
+
+		// Status tetap dijaga di UPDATE agar pembayaran ganda yang berbarengan hanya satu yang
+		// berhasil dan notifikasi serta webhook ticket.sold tidak terkirim dua kali
+		err = execOne(tx, `
+			UPDATE transactions SET status = $1, payment_method = $2, payment_time = NOW(), updated_at = NOW()
+			WHERE id = $3 AND status = $4
+		`, models.TransactionStatusPaid, req.PaymentMethod, id, models.TransactionStatusPending)
+		if err == sql.ErrNoRows {
+			return id, errBookingNotPayable
+		}
+		if err != nil {
+			return id, err
+		}
+		if err := notify.EnqueueBooking(tx, id, notify.TemplateBookingConfirmed); err != nil {
+			return id, err
+		}
+		if err := seatstream.Notify(tx, id, seatstream.StateSold); err != nil {
+			return id, err
+		}
+		return id, webhook.PublishTicketSold(tx, id)
+	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeBookingNotFound, "Booking not found")
+		return
+	}
+	if err == errBookingNotPayable {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeBookingNotPayable,
+			"Only pending bookings within their hold time for upcoming showings can be paid")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to pay booking")
+		return
+	}
+	events.Publish(r.Context(), purchased)
+
+	transaction, err := scanTransaction(config.DB.QueryRow(transactionSelectQuery+" WHERE tr.id = $1", id))
+	if err == nil {
+		transaction.Tickets, err = loadTickets(config.DB, id)
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transaction)
+}
+
+// CancelBooking handler untuk membatalkan booking milik user sebelum jadwal dimulai.
+// Path: /api/bookings/{id}/cancel. Booking PENDING menjadi CANCELLED, booking PAID menjadi
+// REFUNDED; kursinya langsung ditawarkan ke waitlist.
+func CancelBooking(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(strings.TrimSuffix(r.URL.Path, "/cancel"), "/api/bookings/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid booking ID")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer tx.Rollback()
+
+	// Jadwal dikunci lebih dulu, urutannya sama dengan booking
+	var scheduleID int
+	var status string
+	var startTime time.Time
+	err = tx.QueryRow(`
+		SELECT s.id, tr.status, s.start_time
+		FROM transactions tr
+		JOIN schedules s ON tr.schedule_id = s.id
+		WHERE tr.id = $1 AND tr.user_id = $2
+		FOR UPDATE
+	`, id, userID).Scan(&scheduleID, &status, &startTime)
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeBookingNotFound, "Booking not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	newStatus := models.TransactionStatusCancelled
+	if status == models.TransactionStatusPaid {
+		newStatus = models.TransactionStatusRefunded
+	}
+	if (status != models.TransactionStatusPending && status != models.TransactionStatusPaid) || !startTime.After(time.Now()) {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeBookingNotCancellable,
+			"Only pending or paid bookings for upcoming showings can be cancelled")
+		return
+	}
+
+	if err := waitlist.CancelBooking(tx, audit.FromRequest(r), id, newStatus); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to cancel booking")
+		return
+	}
+	if _, err := waitlist.Offer(tx, scheduleID); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to cancel booking")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to cancel booking")
+		return
+	}
+	events.Publish(r.Context(), events.BookingCancelled{
+		TransactionID: id,
+		UserID:        userID,
+		ScheduleID:    scheduleID,
+		Status:        newStatus,
+	})
+
+	transaction, err := scanTransaction(config.DB.QueryRow(transactionSelectQuery+" WHERE tr.id = $1", id))
+	if err == nil {
+		transaction.Tickets, err = loadTickets(config.DB, id)
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transaction)
+}
+
+// errBookingNotPayable booking sudah dibayar/dibatalkan atau jadwalnya tidak lagi bisa ditonton
+var errBookingNotPayable = errors.New("booking is not payable")
+
+// scanTransaction membaca satu baris hasil transactionSelectQuery
+func scanTransaction(row rowScanner) (models.Transaction, error) {
+	var transaction models.Transaction
+	err := row.Scan(
+		&transaction.ID,
+		&transaction.UserID,
+		&transaction.ScheduleID,
+		&transaction.SubtotalAmount,
+		&transaction.DiscountAmount,
+		&transaction.TotalAmount,
+		&transaction.PromotionID,
+		&transaction.PromoCode,
+		&transaction.PaymentMethod,
+		&transaction.PaymentTime,
+		&transaction.Status,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+	)
+	transaction.Tickets = []models.Ticket{}
+	return transaction, err
+}
+
+// loadTickets mengambil tiket sebuah transaksi beserta label kursinya
+func loadTickets(q pricing.Querier, transactionID int) ([]models.Ticket, error) {
+	query := `
+		SELECT t.id, t.transaction_id, t.schedule_id, t.seat_id, se.row_code || se.seat_number, t.price, t.created_at
+		FROM tickets t
+		JOIN seats se ON t.seat_id = se.id
+		WHERE t.transaction_id = $1
+		ORDER BY se.row_code, se.seat_number
+	`
+	rows, err := q.Query(query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tickets := []models.Ticket{}
+	for rows.Next() {
+		var ticket models.Ticket
+		err := rows.Scan(
+			&ticket.ID,
+			&ticket.TransactionID,
+			&ticket.ScheduleID,
+			&ticket.SeatID,
+			&ticket.SeatLabel,
+			&ticket.Price,
+			&ticket.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, rows.Err()
+}
+
+// respondWithPromoError memetakan error dari package promo ke response API
+func respondWithPromoError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case promo.ErrNotFound:
+		respondWithError(w, r, http.StatusUnprocessableEntity, apierror.CodePromoNotFound, "Promo code not found")
+	case promo.ErrNotActive:
+		respondWithError(w, r, http.StatusUnprocessableEntity, apierror.CodePromoNotActive, "Promo code is not active")
+	case promo.ErrUsageLimitReached:
+		respondWithError(w, r, http.StatusUnprocessableEntity, apierror.CodePromoUsageLimit, "Promo code usage limit reached")
+	case promo.ErrUserLimitReached:
+		respondWithError(w, r, http.StatusUnprocessableEntity, apierror.CodePromoUserLimit, "You have already used this promo code")
+	case promo.ErrMinSpendNotMet:
+		respondWithError(w, r, http.StatusUnprocessableEntity, apierror.CodePromoMinSpend, "Minimum spend for this promo code not met")
+	case promo.ErrNotApplicable:
+		respondWithError(w, r, http.StatusUnprocessableEntity, apierror.CodePromoNotApplicable, "Promo code is not valid for this movie or cinema")
+	default:
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/ical"
+	"mkp/middleware"
+	"mkp/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// calendarFeedDays rentang jadwal mendatang yang dipublikasikan di feed cinema
+const calendarFeedDays = 60
+
+// calendarHistoryDays tiket yang jadwalnya sudah lewat tetap ada di feed selama rentang ini
+const calendarHistoryDays = 90
+
+// GetCinemaScheduleICS handler publik feed iCalendar jadwal SHOWING mendatang di sebuah cinema.
+// Path: /api/cinemas/{id}/schedule.ics
+func GetCinemaScheduleICS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(strings.TrimSuffix(r.URL.Path, "/schedule.ics"), "/api/cinemas/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid cinema ID")
+		return
+	}
+
+	cinema, err := scanCinema(config.DB.QueryRow(cinemaSelectQuery+" WHERE c.id = $1", id))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeCinemaNotFound, "Cinema not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	query := scheduleSelectQuery + `
+		WHERE c.id = $1 AND s.deleted_at IS NULL AND s.status = $2 AND s.end_time > NOW() AND s.start_time < NOW() + make_interval(days => $3)
+		ORDER BY s.start_time
+	`
+	rows, err := config.DB.Query(query, id, models.ScheduleStatusShowing, calendarFeedDays)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	calendar := ical.Calendar{Name: cinema.Name, Timezone: cinema.Timezone, Events: []ical.Event{}}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("schedule-%d@mkp-cinema", schedule.ID),
+			Summary:     fmt.Sprintf("%s (%s)", schedule.MovieTitle, schedule.StudioName),
+			Description: fmt.Sprintf("Studio: %s\nPrice: %.0f", schedule.StudioName, schedule.Price),
+			Location:    cinemaLocation(cinema.Name, cinema.Address),
+			Status:      ical.StatusConfirmed,
+			Start:       schedule.StartTime,
+			End:         schedule.EndTime,
+		})
+	}
+
+	writeCalendar(w, calendar, fmt.Sprintf("cinema-%d.ics", id))
+}
+
+// GetTicketCalendarICS handler feed iCalendar privat berisi tiket milik user.
+// Path: /api/calendar/{token}.ics; token menggantikan header Authorization karena
+// aplikasi kalender tidak bisa mengirim bearer token.
+func GetTicketCalendarICS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/calendar/"), ".ics")
+	if token == "" || strings.Contains(token, "/") {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeNotFound, "Calendar feed not found")
+		return
+	}
+
+	var userID int
+	var fullname string
+	err := config.DB.QueryRow("SELECT id, fullname FROM users WHERE calendar_token = $1", token).Scan(&userID, &fullname)
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeNotFound, "Calendar feed not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	// Satu event per transaksi; kursi yang dibeli digabung menjadi satu label
+	query := `
+		SELECT tr.id, tr.status, s.status, s.start_time, s.end_time,
+			COALESCE(m.title, ''), COALESCE(st.name, ''), COALESCE(c.name, ''), COALESCE(c.address, ''),
+			string_agg(se.row_code || se.seat_number, ', ' ORDER BY se.row_code, se.seat_number),
+			COALESCE(tr.updated_at, tr.created_at)
+		FROM transactions tr
+		JOIN schedules s ON tr.schedule_id = s.id
+		LEFT JOIN movies m ON s.movie_id = m.id
+		LEFT JOIN studios st ON s.studio_id = st.id
+		LEFT JOIN cinemas c ON st.cinema_id = c.id
+		JOIN tickets t ON t.transaction_id = tr.id
+		JOIN seats se ON t.seat_id = se.id
+		WHERE tr.user_id = $1 AND tr.status IN ($2, $3) AND s.end_time > NOW() - make_interval(days => $4)
+		GROUP BY tr.id, s.id, m.id, st.id, c.id
+		ORDER BY s.start_time
+	`
+	rows, err := config.DB.Query(query, userID, models.TransactionStatusPending, models.TransactionStatusPaid, calendarHistoryDays)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	calendar := ical.Calendar{Name: "MKP Cinema - " + fullname, Events: []ical.Event{}}
+	for rows.Next() {
+		var transactionID int
+		var transactionStatus, scheduleStatus, movieTitle, studioName, cinemaName, address, seats string
+		var start, end, updated time.Time
+		err := rows.Scan(&transactionID, &transactionStatus, &scheduleStatus, &start, &end,
+			&movieTitle, &studioName, &cinemaName, &address, &seats, &updated)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+
+		status := ical.StatusConfirmed
+		if scheduleStatus == models.ScheduleStatusCancelled {
+			status = ical.StatusCancelled
+		} else if transactionStatus == models.TransactionStatusPending {
+			status = ical.StatusTentative
+		}
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("booking-%d@mkp-cinema", transactionID),
+			Summary:     fmt.Sprintf("%s - %s", movieTitle, studioName),
+			Description: fmt.Sprintf("Booking #%d\nStudio: %s\nSeats: %s\nStatus: %s", transactionID, studioName, seats, transactionStatus),
+			Location:    cinemaLocation(cinemaName, address),
+			Status:      status,
+			Start:       start,
+			End:         end,
+			Updated:     updated,
+		})
+	}
+
+	writeCalendar(w, calendar, "my-tickets.ics")
+}
+
+// GetCalendarFeed handler untuk mendapatkan URL feed iCalendar tiket milik user yang sedang login.
+// GET membuat token jika belum ada, POST mengganti token sehingga URL lama tidak berlaku lagi.
+func GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	newToken, err := calendarToken()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeInternalError, "Failed to generate calendar token")
+		return
+	}
+
+	// Rotasi token dicatat di audit log (nilai token sendiri tidak ikut tersimpan di snapshot)
+	var token string
+	if r.Method == http.MethodPost {
+		_, err = withAudit(r, audit.ActionUpdate, "users", userID, func(tx *sql.Tx) (int, error) {
+			return userID, tx.QueryRow("UPDATE users SET calendar_token = $1, updated_at = NOW() WHERE id = $2 RETURNING calendar_token",
+				newToken, userID).Scan(&token)
+		})
+	} else {
+		err = config.DB.QueryRow("UPDATE users SET calendar_token = COALESCE(calendar_token, $1) WHERE id = $2 RETURNING calendar_token",
+			newToken, userID).Scan(&token)
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	feedURL := requestBaseURL(r) + "/api/calendar/" + token + ".ics"
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"url":        feedURL,
+		"webcal_url": "webcal://" + strings.SplitN(feedURL, "://", 2)[1],
+	})
+}
+
+// writeCalendar mengirim kalender sebagai file .ics
+func writeCalendar(w http.ResponseWriter, calendar ical.Calendar, filename string) {
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	calendar.Write(w, time.Now())
+}
+
+// cinemaLocation menggabungkan nama dan alamat cinema untuk field LOCATION
+func cinemaLocation(name, address string) string {
+	if address == "" {
+		return name
+	}
+	return name + ", " + address
+}
+
+// calendarToken membuat token acak 32 karakter hex
+func calendarToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// requestBaseURL scheme dan host request, menghormati X-Forwarded-Proto dari reverse proxy
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"database/sql"
+	"mkp/apierror"
+	"mkp/config"
+	"mkp/models"
+	"mkp/validation"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// movieSelectQuery query dasar film, dipakai bersama scanMovie
+const movieSelectQuery = `
+	SELECT m.id, m.title, COALESCE(m.description, ''), m.duration_minutes, m.release_date, m.created_at
+	FROM movies m
+`
+
+// cinemaSelectQuery query dasar cinema, dipakai bersama scanCinema
+const cinemaSelectQuery = `
+	SELECT c.id, c.name, c.city, COALESCE(c.address, ''), c.timezone, c.created_at
+	FROM cinemas c
+`
+
+// GetMovies handler publik untuk mendapatkan daftar film.
+// Query param city (opsional) membatasi ke film yang punya jadwal tayang mendatang di kota tersebut.
+func GetMovies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := movieSelectQuery
+	args := []interface{}{}
+	if city := strings.TrimSpace(r.URL.Query().Get("city")); city != "" {
+		query += " WHERE " + upcomingInCityCondition("$1")
+		args = append(args, city)
+	}
+	query += " ORDER BY m.release_date DESC NULLS LAST, m.title"
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	movies := []models.Movie{}
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		movies = append(movies, movie)
+	}
+
+	respondWithJSON(w, http.StatusOK, movies)
+}
+
+// SearchMovies handler publik untuk mencari film berdasarkan judul dan deskripsi.
+// Menggabungkan full-text search PostgreSQL dengan trigram similarity sehingga
+// judul yang salah ketik ("avatr") atau ditulis tanpa tanda baca ("spiderman") tetap ditemukan.
+// Query param: q (wajib), city (opsional, hanya film dengan jadwal mendatang di kota tersebut), limit.
+func SearchMovies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	fieldErrors := []apierror.FieldError{}
+
+	q := strings.TrimSpace(params.Get("q"))
+	if len([]rune(q)) < 2 {
+		fieldErrors = append(fieldErrors, apierror.FieldError{
+			Field: "q", Code: validation.CodeTooShort, Message: "q must be at least 2 characters",
+		})
+	}
+
+	limit := 20
+	if value := params.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 50 {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: "limit", Code: validation.CodeOutOfRange, Message: "limit must be between 1 and 50",
+			})
+		}
+		limit = n
+	}
+
+	if len(fieldErrors) > 0 {
+		apierror.Write(w, r, validation.Problem(fieldErrors))
+		return
+	}
+
+	// compact = judul tanpa spasi dan tanda baca, agar "spiderman" cocok dengan "Spider-Man"
+	query := `
+		WITH input AS (
+			SELECT websearch_to_tsquery('simple', $1) AS tsq,
+				lower($1) AS q,
+				regexp_replace(lower($1), '[^a-z0-9]+', '', 'g') AS compact
+		)
+		SELECT m.id, m.title, COALESCE(m.description, ''), m.duration_minutes, m.release_date, m.created_at,
+			ts_rank(m.search_vector, i.tsq) * 2
+				+ GREATEST(
+					word_similarity(i.q, lower(m.title)),
+					word_similarity(i.compact, regexp_replace(lower(m.title), '[^a-z0-9]+', '', 'g'))
+				)
+				+ word_similarity(i.q, lower(COALESCE(m.description, ''))) * 0.5 AS relevance
+		FROM movies m, input i
+		WHERE (
+			m.search_vector @@ i.tsq
+			OR i.q <% lower(m.title)
+			OR i.compact <% regexp_replace(lower(m.title), '[^a-z0-9]+', '', 'g')
+			OR i.q <% lower(m.description)
+		)
+	`
+	args := []interface{}{q}
+	if city := strings.TrimSpace(params.Get("city")); city != "" {
+		args = append(args, city)
+		query += " AND " + upcomingInCityCondition("$2")
+	}
+	args = append(args, limit)
+	query += " ORDER BY relevance DESC, m.title LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	results := []models.MovieSearchResult{}
+	for rows.Next() {
+		var result models.MovieSearchResult
+		var releaseDate sql.NullTime
+		err := rows.Scan(
+			&result.ID,
+			&result.Title,
+			&result.Description,
+			&result.DurationMinutes,
+			&releaseDate,
+			&result.CreatedAt,
+			&result.Relevance,
+		)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		if releaseDate.Valid {
+			date := releaseDate.Time.Format("2006-01-02")
+			result.ReleaseDate = &date
+		}
+		results = append(results, result)
+	}
+
+	respondWithJSON(w, http.StatusOK, results)
+}
+
+// GetMovieByID handler publik untuk mendapatkan detail film
+func GetMovieByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/movies/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid movie ID")
+		return
+	}
+
+	movie, err := scanMovie(config.DB.QueryRow(movieSelectQuery+" WHERE m.id = $1", id))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeMovieNotFound, "Movie not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, movie)
+}
+
+// GetCinemas handler publik untuk mendapatkan daftar cinema, bisa difilter dengan ?city=
+func GetCinemas(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := cinemaSelectQuery
+	args := []interface{}{}
+	if city := strings.TrimSpace(r.URL.Query().Get("city")); city != "" {
+		query += " WHERE LOWER(c.city) = LOWER($1)"
+		args = append(args, city)
+	}
+	query += " ORDER BY c.city, c.name"
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	cinemas := []models.Cinema{}
+	for rows.Next() {
+		cinema, err := scanCinema(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		cinemas = append(cinemas, cinema)
+	}
+
+	respondWithJSON(w, http.StatusOK, cinemas)
+}
+
+// GetCinemaByID handler publik untuk mendapatkan detail cinema beserta studionya
+func GetCinemaByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/cinemas/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid cinema ID")
+		return
+	}
+
+	cinema, err := scanCinema(config.DB.QueryRow(cinemaSelectQuery+" WHERE c.id = $1", id))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeCinemaNotFound, "Cinema not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, cinema_id, name, total_seats, studio_type
+		FROM studios
+		WHERE cinema_id = $1
+		ORDER BY name
+	`, id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	cinema.Studios = []models.Studio{}
+	for rows.Next() {
+		var studio models.Studio
+		if err := rows.Scan(&studio.ID, &studio.CinemaID, &studio.Name, &studio.TotalSeats, &studio.StudioType); err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		cinema.Studios = append(cinema.Studios, studio)
+	}
+
+	respondWithJSON(w, http.StatusOK, cinema)
+}
+
+// GetShowtimes handler publik untuk mendapatkan jadwal tayang mendatang yang masih SHOWING.
+// Filter opsional: date (YYYY-MM-DD, tanggal lokal cinema), cinema_id, movie_id, city.
+func GetShowtimes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	conditions := []string{"s.deleted_at IS NULL", "s.status = 'SHOWING'", "s.start_time > NOW()"}
+	args := []interface{}{}
+	fieldErrors := []apierror.FieldError{}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if date := params.Get("date"); date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: "date", Code: validation.CodeInvalidDateTime, Message: "date must use format YYYY-MM-DD",
+			})
+		} else {
+			addCondition("(s.start_time AT TIME ZONE c.timezone)::date = ?", date)
+		}
+	}
+	for _, filter := range []struct{ param, column string }{
+		{"cinema_id", "c.id"},
+		{"movie_id", "s.movie_id"},
+	} {
+		value := params.Get(filter.param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: filter.param, Code: validation.CodeOutOfRange, Message: filter.param + " must be a positive integer",
+			})
+			continue
+		}
+		addCondition(filter.column+" = ?", id)
+	}
+	if city := strings.TrimSpace(params.Get("city")); city != "" {
+		addCondition("LOWER(c.city) = LOWER(?)", city)
+	}
+
+	if len(fieldErrors) > 0 {
+		apierror.Write(w, r, validation.Problem(fieldErrors))
+		return
+	}
+
+	query := scheduleSelectQuery + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY s.start_time, c.name, st.name"
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	schedules := []models.Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	respondWithJSON(w, http.StatusOK, schedules)
+}
+
+// upcomingInCityCondition kondisi SQL untuk film (alias m) yang punya jadwal SHOWING
+// mendatang di sebuah kota; placeholder adalah parameter nama kota, misal "$1"
+func upcomingInCityCondition(placeholder string) string {
+	return `EXISTS (
+		SELECT 1
+		FROM schedules s
+		JOIN studios st ON s.studio_id = st.id
+		JOIN cinemas c ON st.cinema_id = c.id
+		WHERE s.movie_id = m.id AND s.deleted_at IS NULL AND s.status = 'SHOWING' AND s.start_time > NOW()
+			AND LOWER(c.city) = LOWER(` + placeholder + `)
+	)`
+}
+
+// scanMovie membaca satu baris hasil movieSelectQuery
+func scanMovie(row rowScanner) (models.Movie, error) {
+	var movie models.Movie
+	var releaseDate sql.NullTime
+	err := row.Scan(
+		&movie.ID,
+		&movie.Title,
+		&movie.Description,
+		&movie.DurationMinutes,
+		&releaseDate,
+		&movie.CreatedAt,
+	)
+	if releaseDate.Valid {
+		date := releaseDate.Time.Format("2006-01-02")
+		movie.ReleaseDate = &date
+	}
+	return movie, err
+}
+
+// scanCinema membaca satu baris hasil cinemaSelectQuery
+func scanCinema(row rowScanner) (models.Cinema, error) {
+	var cinema models.Cinema
+	err := row.Scan(
+		&cinema.ID,
+		&cinema.Name,
+		&cinema.City,
+		&cinema.Address,
+		&cinema.Timezone,
+		&cinema.CreatedAt,
+	)
+	return cinema, err
+}
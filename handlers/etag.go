@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"mkp/apierror"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errPreconditionFailed versi baris sudah berubah sejak dibaca client
+var errPreconditionFailed = errors.New("precondition failed")
+
+// versionETag ETag dari kolom version, misal "3"
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ifMatch membaca header If-Match. Client yang tidak mengirim header mendapat 428 agar
+// perubahan tidak menimpa data yang belum pernah dibaca; false berarti response sudah dikirim.
+func ifMatch(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		respondWithError(w, r, http.StatusPreconditionRequired, apierror.CodePreconditionRequired,
+			"If-Match header is required, use the ETag from GET")
+		return nil, false
+	}
+
+	tags := []string{}
+	for _, tag := range strings.Split(header, ",") {
+		// If-Match memakai perbandingan strong, tapi ETag weak dari proxy tetap diterima
+		tags = append(tags, strings.TrimPrefix(strings.TrimSpace(tag), "W/"))
+	}
+	return tags, true
+}
+
+// matchesVersion true jika salah satu tag If-Match sama dengan version, atau berupa "*"
+func matchesVersion(tags []string, version int) bool {
+	etag := versionETag(version)
+	for _, tag := range tags {
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondPreconditionFailed response 412 beserta ETag terbaru agar client bisa membaca ulang
+func respondPreconditionFailed(w http.ResponseWriter, r *http.Request, version int) {
+	if version > 0 {
+		w.Header().Set("ETag", versionETag(version))
+	}
+	respondWithError(w, r, http.StatusPreconditionFailed, apierror.CodePreconditionFailed,
+		"Resource has been modified since it was read, fetch it again and retry")
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mkp/models"
+	"mkp/report"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wantsCSV mengecek apakah client meminta response CSV lewat ?format=csv atau header Accept
+func wantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.EqualFold(format, "csv")
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
+}
+
+// writeSchedulesCSV menulis jadwal sebagai CSV. Kolom id, movie_id, studio_id, start_time,
+// end_time, price dan status sama dengan format import sehingga file bisa diedit lalu di-import ulang.
+func writeSchedulesCSV(w http.ResponseWriter, schedules []models.Schedule) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="schedules-%s.csv"`, time.Now().Format("20060102-150405")))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"id", "movie_id", "movie_title", "studio_id", "studio_name", "cinema_name", "timezone",
+		"start_time", "end_time", "start_time_local", "end_time_local", "price", "status",
+	})
+	for _, s := range schedules {
+		writer.Write([]string{
+			strconv.Itoa(s.ID),
+			strconv.Itoa(s.MovieID),
+			report.Cell(s.MovieTitle),
+			strconv.Itoa(s.StudioID),
+			report.Cell(s.StudioName),
+			report.Cell(s.CinemaName),
+			s.Timezone,
+			s.StartTime.Format(time.RFC3339),
+			s.EndTime.Format(time.RFC3339),
+			s.StartTimeLocal,
+			s.EndTimeLocal,
+			strconv.FormatFloat(s.Price, 'f', 2, 64),
+			s.Status,
+		})
+	}
+	writer.Flush()
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/importer"
+	"net/http"
+	"strings"
+)
+
+// maxImportBytes batas ukuran body file import
+const maxImportBytes = 10 << 20
+
+// ImportData handler untuk import CSV/JSON movies, cinemas, studios atau schedules (admin).
+// Entity diambil dari path /api/admin/import/{entity}, format dari query ?format= atau Content-Type.
+// Semua baris di-upsert dalam satu transaksi beserta audit log per baris; jika ada baris tidak
+// valid tidak ada yang ditulis.
+// Dengan dry_run=true hanya laporan yang dikembalikan.
+func ImportData(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	entity := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/import/"), "/")
+	format := importFormat(r)
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	result, err := importer.Import(config.DB, audit.FromRequest(r), entity, format, http.MaxBytesReader(w, r.Body, maxImportBytes), dryRun)
+	if errors.Is(err, importer.ErrUnknownEntity) {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeNotFound,
+			fmt.Sprintf("Unknown import entity, use one of: %s", strings.Join(importer.Entities(), ", ")))
+		return
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondWithError(w, r, http.StatusRequestEntityTooLarge, apierror.CodeInvalidRequestBody, "Import file is too large")
+		return
+	}
+	if errors.Is(err, importer.ErrUnknownFormat) || errors.Is(err, importer.ErrTooManyRows) || errors.Is(err, importer.ErrInvalidFile) {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidRequestBody, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to import data")
+		return
+	}
+
+	if dryRun {
+		respondWithJSON(w, http.StatusOK, result)
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		problem := apierror.New(http.StatusUnprocessableEntity, apierror.CodeImportInvalid,
+			fmt.Sprintf("%d errors found in %d rows, nothing was imported", len(result.Errors), result.Total))
+		for _, rowErr := range result.Errors {
+			problem.WithErrors(apierror.FieldError{
+				Field:   fmt.Sprintf("rows[%d].%s", rowErr.Row, rowErr.Field),
+				Code:    rowErr.Code,
+				Message: rowErr.Message,
+			})
+		}
+		apierror.Write(w, r, problem)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// importFormat menentukan format file dari query ?format= lalu Content-Type, default JSON
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.ToLower(format)
+	}
+	if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+		return importer.FormatCSV
+	}
+	return importer.FormatJSON
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mkp/apierror"
+	"mkp/openapi"
+	"net/http"
+	"sync"
+)
+
+// openAPIDocument dokumen OpenAPI yang sudah di-encode; spesifikasi tidak berubah selama proses berjalan
+var (
+	openAPIOnce     sync.Once
+	openAPIDocument []byte
+	openAPIErr      error
+)
+
+// GetOpenAPISpec handler publik dokumen OpenAPI 3.1 seluruh API
+func GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	openAPIOnce.Do(func() {
+		openAPIDocument, openAPIErr = json.Marshal(openapi.Spec())
+	})
+	if openAPIErr != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeInternalError, "Failed to build OpenAPI document")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPIDocument)
+}
+
+// GetAPIDocs handler publik halaman referensi API yang dirender dari /openapi.json
+func GetAPIDocs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openapi.DocsHTML)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+)
+
+// mergePatchContentType media type JSON Merge Patch (RFC 7386)
+const mergePatchContentType = "application/merge-patch+json"
+
+// isMergePatch true jika body PATCH dikirim sebagai merge patch. application/json juga
+// diterima karena merge patch adalah dokumen JSON biasa.
+func isMergePatch(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && (mediaType == mergePatchContentType || mediaType == "application/json")
+}
+
+// mergePatch menerapkan patch ke target sesuai RFC 7386: object digabung rekursif,
+// nilai null menghapus key, nilai lain menggantikan target.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Contoh dari RFC 7386 Appendix A ditambah kasus yang dipakai endpoint PATCH
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// Menghapus key yang tidak ada bukan error
+		{`{"title":"x"}`, `{"rating":null}`, `{"title":"x"}`},
+		// Patch kosong tidak mengubah apa pun
+		{`{"title":"x","genres":["a"]}`, `{}`, `{"title":"x","genres":["a"]}`},
+	}
+	for _, tt := range tests {
+		var target, patch, want interface{}
+		mustUnmarshal(t, tt.target, &target)
+		mustUnmarshal(t, tt.patch, &patch)
+		mustUnmarshal(t, tt.want, &want)
+
+		if got := mergePatch(target, patch); !reflect.DeepEqual(got, want) {
+			gotJSON, _ := json.Marshal(got)
+			t.Errorf("mergePatch(%s, %s) = %s, want %s", tt.target, tt.patch, gotJSON, tt.want)
+		}
+	}
+}
+
+func TestIsMergePatch(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"application/merge-patch+json", true},
+		{"application/merge-patch+json; charset=utf-8", true},
+		{"application/json", true},
+		{"application/json-patch+json", false},
+		{"text/plain", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("PATCH", "/", nil)
+		r.Header.Set("Content-Type", tt.contentType)
+		if got := isMergePatch(r); got != tt.want {
+			t.Errorf("isMergePatch(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func mustUnmarshal(t *testing.T, data string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+}
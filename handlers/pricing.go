@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"database/sql"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/models"
+	"mkp/pricing"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GetPricingRules handler untuk mendapatkan semua pricing rule (admin)
+func GetPricingRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	rows, err := config.DB.Query(pricing.RuleSelectQuery + " ORDER BY priority, id")
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	rules := []models.PricingRule{}
+	for rows.Next() {
+		rule, err := pricing.ScanRule(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	respondWithJSON(w, http.StatusOK, rules)
+}
+
+// GetPricingRuleByID handler untuk mendapatkan pricing rule berdasarkan ID (admin)
+func GetPricingRuleByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/pricing-rules/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid pricing rule ID")
+		return
+	}
+
+	rule, err := pricing.ScanRule(config.DB.QueryRow(pricing.RuleSelectQuery+" WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodePricingRuleNotFound, "Pricing rule not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rule)
+}
+
+// CreatePricingRule handler untuk membuat pricing rule baru (admin)
+func CreatePricingRule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req models.PricingRuleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	query := `
+		INSERT INTO pricing_rules (name, days_of_week, time_from, time_to, studio_type, seat_category,
+			holiday_only, adjustment_type, adjustment_value, priority, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+		RETURNING id
+	`
+
+	ruleID, err := withAudit(r, audit.ActionCreate, "pricing_rules", 0, func(tx *sql.Tx) (int, error) {
+		var id int
+		err := tx.QueryRow(
+			query,
+			req.Name,
+			pricing.DaysArray(req.DaysOfWeek),
+			req.TimeFrom,
+			req.TimeTo,
+			req.StudioType,
+			req.SeatCategory,
+			req.HolidayOnly,
+			req.AdjustmentType,
+			req.AdjustmentValue,
+			req.Priority,
+			active,
+		).Scan(&id)
+		return id, err
+	})
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create pricing rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Pricing rule created successfully",
+		"id":      ruleID,
+	})
+}
+
+// UpdatePricingRule handler untuk mengganti seluruh isi pricing rule (admin)
+func UpdatePricingRule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/pricing-rules/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid pricing rule ID")
+		return
+	}
+
+	var req models.PricingRuleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	query := `
+		UPDATE pricing_rules
+		SET name = $1, days_of_week = $2, time_from = $3, time_to = $4, studio_type = $5,
+			seat_category = $6, holiday_only = $7, adjustment_type = $8, adjustment_value = $9,
+			priority = $10, active = $11, updated_at = NOW()
+		WHERE id = $12
+	`
+	_, err := withAudit(r, audit.ActionUpdate, "pricing_rules", id, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(
+			query,
+			req.Name,
+			pricing.DaysArray(req.DaysOfWeek),
+			req.TimeFrom,
+			req.TimeTo,
+			req.StudioType,
+			req.SeatCategory,
+			req.HolidayOnly,
+			req.AdjustmentType,
+			req.AdjustmentValue,
+			req.Priority,
+			active,
+			id,
+		)
+		return id, err
+	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodePricingRuleNotFound, "Pricing rule not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to update pricing rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Pricing rule updated successfully",
+	})
+}
+
+// DeletePricingRule handler untuk menghapus pricing rule (admin).
+// Harga tiket yang sudah terjual tidak berubah karena disimpan per tiket.
+func DeletePricingRule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/pricing-rules/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid pricing rule ID")
+		return
+	}
+
+	_, err := withAudit(r, audit.ActionDelete, "pricing_rules", id, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec("DELETE FROM pricing_rules WHERE id = $1", id)
+		return id, err
+	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodePricingRuleNotFound, "Pricing rule not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to delete pricing rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Pricing rule deleted successfully",
+	})
+}
+
+// PreviewSchedulePrice handler untuk melihat harga tiket sebuah jadwal untuk kursi tertentu.
+// Contoh: GET /api/schedules/1/price-preview?seat_ids=10,11
+func PreviewSchedulePrice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(strings.TrimSuffix(r.URL.Path, "/price-preview"), "/api/schedules/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid schedule ID")
+		return
+	}
+
+	seatIDs, ok := parseIDList(r.URL.Query().Get("seat_ids"))
+	if !ok || len(seatIDs) == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeValidationFailed, "seat_ids must be a comma separated list of seat IDs")
+		return
+	}
+
+	quote, err := pricing.Quote(config.DB, id, seatIDs)
+	if err == pricing.ErrScheduleNotFound {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
+		return
+	}
+	if err == pricing.ErrSeatNotFound {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeSeatNotFound, "One or more seats do not exist in this studio")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, quote)
+}
+
+// Helper function untuk parse daftar ID yang dipisah koma, misal "1,2,3"
+func parseIDList(value string) ([]int, bool) {
+	ids := []int{}
+	seen := map[int]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil || id <= 0 {
+			return nil, false
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, true
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/models"
+	"mkp/promo"
+	"mkp/validation"
+	"net/http"
+
+	"github.com/lib/pq"
+)
+
+// GetPromotions handler untuk mendapatkan semua promo (admin)
+func GetPromotions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	rows, err := config.DB.Query(promo.SelectQuery + " ORDER BY created_at DESC")
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	promotions := []models.Promotion{}
+	for rows.Next() {
+		promotion, err := promo.Scan(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	respondWithJSON(w, http.StatusOK, promotions)
+}
+
+// GetPromotionByID handler untuk mendapatkan promo berdasarkan ID (admin)
+func GetPromotionByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/promotions/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid promotion ID")
+		return
+	}
+
+	promotion, err := promo.Scan(config.DB.QueryRow(promo.SelectQuery+" WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodePromotionNotFound, "Promotion not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, promotion)
+}
+
+// CreatePromotion handler untuk membuat promo baru (admin)
+func CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req models.PromotionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	validFrom, _ := validation.ParseDateTimeIn(req.ValidFrom, config.LoadLocation(""))
+	validUntil, _ := validation.ParseDateTimeIn(req.ValidUntil, config.LoadLocation(""))
+	active := req.Active == nil || *req.Active
+
+	query := `
+		INSERT INTO promotions (code, description, discount_type, discount_value, max_discount, min_spend,
+			valid_from, valid_until, usage_limit, usage_limit_per_user, movie_ids, cinema_ids, active,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
+		RETURNING id
+	`
+
+	promotionID, err := withAudit(r, audit.ActionCreate, "promotions", 0, func(tx *sql.Tx) (int, error) {
+		var id int
+		err := tx.QueryRow(
+			query,
+			promo.NormalizeCode(req.Code),
+			req.Description,
+			req.DiscountType,
+			req.DiscountValue,
+			req.MaxDiscount,
+			req.MinSpend,
+			validFrom,
+			validUntil,
+			req.UsageLimit,
+			req.UsageLimitPerUser,
+			promo.IDArray(req.MovieIDs),
+			promo.IDArray(req.CinemaIDs),
+			active,
+		).Scan(&id)
+		return id, err
+	})
+	if isUniqueViolation(err) {
+		respondWithError(w, r, http.StatusConflict, apierror.CodePromoCodeExists, "Promo code already exists")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create promotion")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Promotion created successfully",
+		"id":      promotionID,
+	})
+}
+
+// UpdatePromotion handler untuk mengganti seluruh isi promo (admin).
+// used_count tidak ikut diubah.
+func UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/promotions/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid promotion ID")
+		return
+	}
+
+	var req models.PromotionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	validFrom, _ := validation.ParseDateTimeIn(req.ValidFrom, config.LoadLocation(""))
+	validUntil, _ := validation.ParseDateTimeIn(req.ValidUntil, config.LoadLocation(""))
+	active := req.Active == nil || *req.Active
+
+	query := `
+		UPDATE promotions
+		SET code = $1, description = $2, discount_type = $3, discount_value = $4, max_discount = $5,
+			min_spend = $6, valid_from = $7, valid_until = $8, usage_limit = $9, usage_limit_per_user = $10,
+			movie_ids = $11, cinema_ids = $12, active = $13, updated_at = NOW()
+		WHERE id = $14
+	`
+	_, err := withAudit(r, audit.ActionUpdate, "promotions", id, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(
+			query,
+			promo.NormalizeCode(req.Code),
+			req.Description,
+			req.DiscountType,
+			req.DiscountValue,
+			req.MaxDiscount,
+			req.MinSpend,
+			validFrom,
+			validUntil,
+			req.UsageLimit,
+			req.UsageLimitPerUser,
+			promo.IDArray(req.MovieIDs),
+			promo.IDArray(req.CinemaIDs),
+			active,
+			id,
+		)
+		return id, err
+	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodePromotionNotFound, "Promotion not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		respondWithError(w, r, http.StatusConflict, apierror.CodePromoCodeExists, "Promo code already exists")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to update promotion")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Promotion updated successfully",
+	})
+}
+
+// DeletePromotion handler untuk menonaktifkan promo (admin).
+// Promo tidak dihapus permanen karena sudah direferensikan oleh transaksi.
+func DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/promotions/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid promotion ID")
+		return
+	}
+
+	_, err := withAudit(r, audit.ActionUpdate, "promotions", id, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec("UPDATE promotions SET active = false, updated_at = NOW() WHERE id = $1", id)
+		return id, err
+	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodePromotionNotFound, "Promotion not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to delete promotion")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Promotion deactivated successfully",
+	})
+}
+
+// Helper function untuk mengecek error unique constraint PostgreSQL
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
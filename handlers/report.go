@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mkp/apierror"
+	"mkp/config"
+	"mkp/report"
+	"mkp/validation"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultReportDays rentang default laporan jika from/to tidak diisi
+const defaultReportDays = 30
+
+// maxReportDays rentang maksimum satu laporan
+const maxReportDays = 366
+
+// maxTopMovies batas parameter limit laporan top movies
+const maxTopMovies = 100
+
+// reportResponse bungkus response JSON laporan
+type reportResponse struct {
+	Report  string       `json:"report"`
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	GroupBy string       `json:"group_by,omitempty"`
+	Rows    report.Table `json:"rows"`
+}
+
+// GetReport handler laporan penjualan untuk admin. Path: /api/admin/reports/{revenue|occupancy|top-movies|refunds}.
+// Filter: from, to (YYYY-MM-DD, tanggal tayang lokal cinema), cinema_id, movie_id, group_by (day, cinema, movie)
+// untuk revenue dan refunds, sort (revenue, tickets) dan limit untuk top-movies.
+// Dengan ?format=csv atau Accept: text/csv hasilnya dikirim sebagai file CSV.
+func GetReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/reports/"), "/")
+	params := r.URL.Query()
+
+	filter, fieldErrors := reportFilter(params)
+	groupBy := params.Get("group_by")
+	if groupBy == "" {
+		groupBy = report.GroupByDay
+	}
+	if !contains([]string{report.GroupByDay, report.GroupByCinema, report.GroupByMovie}, groupBy) {
+		fieldErrors = append(fieldErrors, apierror.FieldError{
+			Field: "group_by", Code: validation.CodeInvalidEnum, Message: "group_by must be one of: day, cinema, movie",
+		})
+	}
+	sortBy := params.Get("sort")
+	if sortBy == "" {
+		sortBy = report.SortByRevenue
+	}
+	if sortBy != report.SortByRevenue && sortBy != report.SortByTickets {
+		fieldErrors = append(fieldErrors, apierror.FieldError{
+			Field: "sort", Code: validation.CodeInvalidEnum, Message: "sort must be one of: revenue, tickets",
+		})
+	}
+	limit := 10
+	if value := params.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxTopMovies {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: "limit", Code: validation.CodeOutOfRange, Message: fmt.Sprintf("limit must be between 1 and %d", maxTopMovies),
+			})
+		}
+		limit = n
+	}
+
+	var table report.Table
+	var err error
+	response := reportResponse{Report: name}
+	switch name {
+	case "revenue":
+		response.GroupBy = groupBy
+		if len(fieldErrors) == 0 {
+			table, err = report.Revenue(config.DB, filter, groupBy)
+		}
+	case "refunds":
+		response.GroupBy = groupBy
+		if len(fieldErrors) == 0 {
+			table, err = report.Refunds(config.DB, filter, groupBy)
+		}
+	case "occupancy":
+		if len(fieldErrors) == 0 {
+			table, err = report.Occupancy(config.DB, filter)
+		}
+	case "top-movies":
+		if len(fieldErrors) == 0 {
+			table, err = report.TopMovies(config.DB, filter, sortBy, limit)
+		}
+	default:
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeNotFound,
+			"Unknown report, use one of: revenue, occupancy, top-movies, refunds")
+		return
+	}
+
+	if len(fieldErrors) > 0 {
+		apierror.Write(w, r, validation.Problem(fieldErrors))
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	response.From = filter.From.Format(report.DateLayout)
+	response.To = filter.To.Format(report.DateLayout)
+	response.Rows = table
+
+	if wantsCSV(r) {
+		writeReportCSV(w, fmt.Sprintf("%s-%s-%s.csv", name, response.From, response.To), table)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// reportFilter membaca filter laporan dari query string. Tanpa from/to, laporan mencakup
+// defaultReportDays hari terakhir sampai hari ini (timezone default).
+func reportFilter(params url.Values) (report.Filter, []apierror.FieldError) {
+	fieldErrors := []apierror.FieldError{}
+	now := time.Now().In(config.LoadLocation(""))
+	filter := report.Filter{
+		To: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	parseDate := func(field string, dst *time.Time) {
+		value := params.Get(field)
+		if value == "" {
+			return
+		}
+		date, err := time.Parse(report.DateLayout, value)
+		if err != nil {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: field, Code: validation.CodeInvalidDate, Message: field + " must use format YYYY-MM-DD",
+			})
+			return
+		}
+		*dst = date
+	}
+	parseDate("to", &filter.To)
+	filter.From = filter.To.AddDate(0, 0, -(defaultReportDays - 1))
+	parseDate("from", &filter.From)
+
+	if filter.To.Before(filter.From) {
+		fieldErrors = append(fieldErrors, apierror.FieldError{
+			Field: "to", Code: validation.CodeInvalidRange, Message: "to must not be before from",
+		})
+	} else if filter.To.Sub(filter.From).Hours()/24 >= maxReportDays {
+		fieldErrors = append(fieldErrors, apierror.FieldError{
+			Field: "to", Code: validation.CodeInvalidRange, Message: fmt.Sprintf("report range must be at most %d days", maxReportDays),
+		})
+	}
+
+	for _, f := range []struct {
+		param string
+		dst   **int
+	}{
+		{"cinema_id", &filter.CinemaID},
+		{"movie_id", &filter.MovieID},
+	} {
+		value := params.Get(f.param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: f.param, Code: validation.CodeOutOfRange, Message: f.param + " must be a positive integer",
+			})
+			continue
+		}
+		*f.dst = &id
+	}
+
+	return filter, fieldErrors
+}
+
+// writeReportCSV menulis tabel laporan sebagai file CSV
+func writeReportCSV(w http.ResponseWriter, filename string, table report.Table) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write(table.Header())
+	writer.WriteAll(table.Records())
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -3,117 +3,138 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mkp/apierror"
+	"mkp/audit"
 	"mkp/config"
+	"mkp/events"
+	"mkp/middleware"
 	"mkp/models"
+	"mkp/notify"
+	"mkp/validation"
+	"mkp/waitlist"
+	"mkp/webhook"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// GetSchedules handler untuk mendapatkan semua jadwal tayang
+// scheduleSelectQuery query dasar jadwal beserta nama film, studio, cinema dan timezone cinema
+const scheduleSelectQuery = `
+	SELECT 
+		s.id, s.movie_id, s.studio_id, s.start_time, s.end_time, 
+		s.price, s.status, s.created_at, s.deleted_at, s.version,
+		COALESCE(m.title, '') as movie_title,
+		COALESCE(st.name, '') as studio_name,
+		COALESCE(c.name, '') as cinema_name,
+		COALESCE(c.timezone, '') as timezone
+	FROM schedules s
+	LEFT JOIN movies m ON s.movie_id = m.id
+	LEFT JOIN studios st ON s.studio_id = st.id
+	LEFT JOIN cinemas c ON st.cinema_id = c.id
+`
+
+// rowScanner interface yang dipenuhi oleh *sql.Row dan *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSchedule membaca satu baris hasil scheduleSelectQuery
+func scanSchedule(row rowScanner) (models.Schedule, error) {
+	var schedule models.Schedule
+	var timezone string
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.MovieID,
+		&schedule.StudioID,
+		&schedule.StartTime,
+		&schedule.EndTime,
+		&schedule.Price,
+		&schedule.Status,
+		&schedule.CreatedAt,
+		&schedule.DeletedAt,
+		&schedule.Version,
+		&schedule.MovieTitle,
+		&schedule.StudioName,
+		&schedule.CinemaName,
+		&timezone,
+	)
+	if err != nil {
+		return schedule, err
+	}
+
+	schedule.SetTimezone(config.LoadLocation(timezone))
+	return schedule, nil
+}
+
+// GetSchedules handler untuk mendapatkan semua jadwal tayang yang belum dihapus.
+// Admin dapat menyertakan jadwal yang sudah di-soft delete dengan ?include_deleted=true.
+// Dengan ?format=csv atau header Accept: text/csv hasilnya dikirim sebagai file CSV.
 func GetSchedules(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	query := `
-		SELECT 
-			s.id, s.movie_id, s.studio_id, s.start_time, s.end_time, 
-			s.price, s.status, s.created_at,
-			m.title as movie_title,
-			st.name as studio_name,
-			c.name as cinema_name
-		FROM schedules s
-		LEFT JOIN movies m ON s.movie_id = m.id
-		LEFT JOIN studios st ON s.studio_id = st.id
-		LEFT JOIN cinemas c ON st.cinema_id = c.id
-		ORDER BY s.start_time DESC
-	`
+	query := scheduleSelectQuery + " WHERE " + scheduleVisibleCondition(r) + " ORDER BY s.start_time DESC"
 
 	rows, err := config.DB.Query(query)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Database error")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
 		return
 	}
 	defer rows.Close()
 
 	schedules := []models.Schedule{}
 	for rows.Next() {
-		var schedule models.Schedule
-		err := rows.Scan(
-			&schedule.ID,
-			&schedule.MovieID,
-			&schedule.StudioID,
-			&schedule.StartTime,
-			&schedule.EndTime,
-			&schedule.Price,
-			&schedule.Status,
-			&schedule.CreatedAt,
-			&schedule.MovieTitle,
-			&schedule.StudioName,
-			&schedule.CinemaName,
-		)
+		schedule, err := scanSchedule(rows)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Error scanning data")
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
 			return
 		}
 		schedules = append(schedules, schedule)
 	}
 
+	if wantsCSV(r) {
+		writeSchedulesCSV(w, schedules)
+		return
+	}
+
 	respondWithJSON(w, http.StatusOK, schedules)
 }
 
-// GetScheduleByID handler untuk mendapatkan jadwal tayang berdasarkan ID
+// GetScheduleByID handler untuk mendapatkan jadwal tayang berdasarkan ID.
+// Versi jadwal dikirim sebagai ETag untuk dipakai pada If-Match saat update/delete.
 func GetScheduleByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Ambil ID dari URL path
 	id := extractIDFromPath(r.URL.Path, "/api/schedules/")
 	if id == 0 {
-		respondWithError(w, http.StatusBadRequest, "Invalid schedule ID")
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid schedule ID")
 		return
 	}
 
-	query := `
-		SELECT 
-			s.id, s.movie_id, s.studio_id, s.start_time, s.end_time, 
-			s.price, s.status, s.created_at,
-			m.title as movie_title,
-			st.name as studio_name,
-			c.name as cinema_name
-		FROM schedules s
-		LEFT JOIN movies m ON s.movie_id = m.id
-		LEFT JOIN studios st ON s.studio_id = st.id
-		LEFT JOIN cinemas c ON st.cinema_id = c.id
-		WHERE s.id = $1
-	`
-
-	var schedule models.Schedule
-	err := config.DB.QueryRow(query, id).Scan(
-		&schedule.ID,
-		&schedule.MovieID,
-		&schedule.StudioID,
-		&schedule.StartTime,
-		&schedule.EndTime,
-		&schedule.Price,
-		&schedule.Status,
-		&schedule.CreatedAt,
-		&schedule.MovieTitle,
-		&schedule.StudioName,
-		&schedule.CinemaName,
-	)
-
+	query := scheduleSelectQuery + " WHERE s.id = $1 AND " + scheduleVisibleCondition(r)
+	schedule, err := scanSchedule(config.DB.QueryRow(query, id))
 	if err == sql.ErrNoRows {
-		respondWithError(w, http.StatusNotFound, "Schedule not found")
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
 		return
 	}
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Database error")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	etag := versionETag(schedule.Version)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -123,38 +144,37 @@ func GetScheduleByID(w http.ResponseWriter, r *http.Request) {
 // CreateSchedule handler untuk membuat jadwal tayang baru
 func CreateSchedule(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req models.ScheduleCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	// Validasi input
-	if req.MovieID == 0 || req.StudioID == 0 || req.StartTime == "" || req.EndTime == "" || req.Price <= 0 {
-		respondWithError(w, http.StatusBadRequest, "All fields are required and price must be positive")
+	// Waktu lokal diinterpretasikan sesuai timezone cinema dari studio
+	loc, err := studioLocation(req.StudioID)
+	if err == sql.ErrNoRows {
+		apierror.Write(w, r, validation.Problem([]apierror.FieldError{unknownStudioError()}))
 		return
 	}
-
-	// Parse waktu
-	startTime, err := time.Parse("2006-01-02 15:04:05", req.StartTime)
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid start_time format. Use: YYYY-MM-DD HH:MM:SS")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
 		return
 	}
 
-	endTime, err := time.Parse("2006-01-02 15:04:05", req.EndTime)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid end_time format. Use: YYYY-MM-DD HH:MM:SS")
+	// Parse waktu, format sudah dicek oleh validasi
+	startTime, _ := parseShowtime(req.StartTime, loc)
+	endTime, _ := parseShowtime(req.EndTime, loc)
+	if !endTime.After(startTime) {
+		apierror.Write(w, r, validation.Problem([]apierror.FieldError{invalidTimeRangeError()}))
 		return
 	}
 
 	// Set default status jika tidak ada
 	if req.Status == "" {
-		req.Status = "SHOWING"
+		req.Status = models.ScheduleStatusShowing
 	}
 
 	// Insert ke database
@@ -164,22 +184,28 @@ func CreateSchedule(w http.ResponseWriter, r *http.Request) {
 		RETURNING id
 	`
 
-	var scheduleID int
-	err = config.DB.QueryRow(
-		query,
-		req.MovieID,
-		req.StudioID,
-		startTime,
-		endTime,
-		req.Price,
-		req.Status,
-		time.Now(),
-	).Scan(&scheduleID)
-
+	scheduleID, err := withAudit(r, audit.ActionCreate, "schedules", 0, func(tx *sql.Tx) (int, error) {
+		var id int
+		err := tx.QueryRow(
+			query,
+			req.MovieID,
+			req.StudioID,
+			startTime,
+			endTime,
+			req.Price,
+			req.Status,
+			time.Now(),
+		).Scan(&id)
+		if err != nil {
+			return id, err
+		}
+		return id, webhook.PublishSchedule(tx, models.WebhookEventScheduleCreated, id)
+	})
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to create schedule")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create schedule")
 		return
 	}
+	events.Publish(r.Context(), events.ScheduleCreated{ScheduleID: scheduleID})
 
 	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
 		"message": "Schedule created successfully",
@@ -187,131 +213,380 @@ func CreateSchedule(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// UpdateSchedule handler untuk mengupdate jadwal tayang
+// UpdateSchedule handler PUT untuk mengganti seluruh data jadwal tayang. Body sama dengan
+// create dan divalidasi lengkap; status kosong berarti SHOWING. Header If-Match wajib berisi
+// ETag dari GET (412 jika jadwal sudah diubah orang lain). Mengembalikan jadwal terbaru.
 func UpdateSchedule(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Ambil ID dari URL path
 	id := extractIDFromPath(r.URL.Path, "/api/schedules/")
 	if id == 0 {
-		respondWithError(w, http.StatusBadRequest, "Invalid schedule ID")
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid schedule ID")
+		return
+	}
+
+	tags, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	var req models.ScheduleUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+	var req models.ScheduleCreateRequest
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	// Build dynamic update query
-	updates := []string{}
-	args := []interface{}{}
-	argID := 1
+	saveSchedule(w, r, id, tags, req)
+}
 
-	if req.MovieID != nil {
-		updates = append(updates, "movie_id = $"+strconv.Itoa(argID))
-		args = append(args, *req.MovieID)
-		argID++
+// PatchSchedule handler PATCH untuk mengubah sebagian jadwal dengan JSON Merge Patch (RFC 7386).
+// Field yang tidak dikirim tetap, field bernilai null dihapus sehingga gagal validasi jika wajib.
+// Hasil penggabungan divalidasi sebagai jadwal lengkap seperti pada PUT.
+func PatchSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
 	}
-	if req.StudioID != nil {
-		updates = append(updates, "studio_id = $"+strconv.Itoa(argID))
-		args = append(args, *req.StudioID)
-		argID++
+
+	// Ambil ID dari URL path
+	id := extractIDFromPath(r.URL.Path, "/api/schedules/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid schedule ID")
+		return
 	}
-	if req.StartTime != nil {
-		startTime, err := time.Parse("2006-01-02 15:04:05", *req.StartTime)
-		if err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid start_time format")
-			return
-		}
-		updates = append(updates, "start_time = $"+strconv.Itoa(argID))
-		args = append(args, startTime)
-		argID++
+
+	if !isMergePatch(r) {
+		respondWithError(w, r, http.StatusUnsupportedMediaType, apierror.CodeUnsupportedMediaType,
+			"Content-Type must be "+mergePatchContentType)
+		return
 	}
-	if req.EndTime != nil {
-		endTime, err := time.Parse("2006-01-02 15:04:05", *req.EndTime)
-		if err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid end_time format")
-			return
-		}
-		updates = append(updates, "end_time = $"+strconv.Itoa(argID))
-		args = append(args, endTime)
-		argID++
+
+	tags, ok := ifMatch(w, r)
+	if !ok {
+		return
 	}
-	if req.Price != nil {
-		updates = append(updates, "price = $"+strconv.Itoa(argID))
-		args = append(args, *req.Price)
-		argID++
+
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidRequestBody, "Request body must be a JSON object")
+		return
+	}
+
+	current, err := scanSchedule(config.DB.QueryRow(scheduleSelectQuery+" WHERE s.id = $1 AND s.deleted_at IS NULL", id))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
+		return
 	}
-	if req.Status != nil {
-		updates = append(updates, "status = $"+strconv.Itoa(argID))
-		args = append(args, *req.Status)
-		argID++
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	if !matchesVersion(tags, current.Version) {
+		respondPreconditionFailed(w, r, current.Version)
+		return
 	}
 
-	if len(updates) == 0 {
-		respondWithError(w, http.StatusBadRequest, "No fields to update")
+	// Dokumen jadwal saat ini dalam bentuk body PUT; waktu memakai offset cinema
+	document := map[string]interface{}{
+		"movie_id":   current.MovieID,
+		"studio_id":  current.StudioID,
+		"start_time": current.StartTimeLocal,
+		"end_time":   current.EndTimeLocal,
+		"price":      current.Price,
+		"status":     current.Status,
+	}
+	merged, err := json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeInternalError, "Failed to apply patch")
 		return
 	}
 
-	// Add ID to args
-	args = append(args, id)
+	var req models.ScheduleCreateRequest
+	if err := json.Unmarshal(merged, &req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidRequestBody, "Invalid request body")
+		return
+	}
+	if errs := validation.Struct(&req); len(errs) > 0 {
+		apierror.Write(w, r, validation.Problem(errs))
+		return
+	}
 
-	query := "UPDATE schedules SET " + strings.Join(updates, ", ") + " WHERE id = $" + strconv.Itoa(argID)
+	saveSchedule(w, r, id, tags, req)
+}
 
-	result, err := config.DB.Exec(query, args...)
+// saveSchedule menyimpan seluruh field jadwal hasil PUT atau PATCH lalu mengirim jadwal terbaru.
+// Waktu lokal diinterpretasikan sesuai timezone cinema dari studio tujuan.
+func saveSchedule(w http.ResponseWriter, r *http.Request, id int, tags []string, req models.ScheduleCreateRequest) {
+	loc, err := studioLocation(req.StudioID)
+	if err == sql.ErrNoRows {
+		apierror.Write(w, r, validation.Problem([]apierror.FieldError{unknownStudioError()}))
+		return
+	}
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to update schedule")
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil || rowsAffected == 0 {
-		respondWithError(w, http.StatusNotFound, "Schedule not found")
+	// Parse waktu, format sudah dicek oleh validasi
+	startTime, _ := parseShowtime(req.StartTime, loc)
+	endTime, _ := parseShowtime(req.EndTime, loc)
+	if !endTime.After(startTime) {
+		apierror.Write(w, r, validation.Problem([]apierror.FieldError{invalidTimeRangeError()}))
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, map[string]string{
-		"message": "Schedule updated successfully",
+	if req.Status == "" {
+		req.Status = models.ScheduleStatusShowing
+	}
+
+	query := `
+		UPDATE schedules
+		SET movie_id = $1, studio_id = $2, start_time = $3, end_time = $4, price = $5, status = $6,
+			version = version + 1
+		WHERE id = $7
+		RETURNING version
+	`
+
+	var version int
+	var cancelled bool
+	_, err = withAudit(r, audit.ActionUpdate, "schedules", id, func(tx *sql.Tx) (int, error) {
+		if err := checkScheduleVersion(tx, id, tags, &version); err != nil {
+			return id, err
+		}
+
+		var movieID, studioID int
+		var status string
+		var oldStart, oldEnd time.Time
+		err := tx.QueryRow("SELECT movie_id, studio_id, start_time, end_time, COALESCE(status, '') FROM schedules WHERE id = $1", id).
+			Scan(&movieID, &studioID, &oldStart, &oldEnd, &status)
+		if err != nil {
+			return id, err
+		}
+
+		err = tx.QueryRow(query, req.MovieID, req.StudioID, startTime, endTime, req.Price, req.Status, id).Scan(&version)
+		if err != nil {
+			return id, err
+		}
+
+		// Pembatalan ditangani rutin yang sama dengan CLI schedule cancel: pemilik tiket diberi tahu,
+		// booking PENDING dan waitlist ditutup. Perubahan lain cukup memberi tahu pemilik tiket jika
+		// film, studio atau jamnya berubah.
+		cancelled = req.Status == models.ScheduleStatusCancelled && status != models.ScheduleStatusCancelled
+		if cancelled {
+			return id, waitlist.CancelShowing(tx, audit.FromRequest(r), id)
+		}
+		if req.MovieID != movieID || req.StudioID != studioID || !startTime.Equal(oldStart) || !endTime.Equal(oldEnd) {
+			if err := notify.EnqueueScheduleHolders(tx, id, notify.TemplateScheduleChanged); err != nil {
+				return id, err
+			}
+		}
+		return id, webhook.PublishSchedule(tx, models.WebhookEventScheduleUpdated, id)
 	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
+		return
+	}
+	if err == errPreconditionFailed {
+		respondPreconditionFailed(w, r, version)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to update schedule")
+		return
+	}
+	if cancelled {
+		events.Publish(r.Context(), events.ScheduleCancelled{ScheduleID: id})
+	} else {
+		events.Publish(r.Context(), events.ScheduleUpdated{ScheduleID: id, Version: version})
+	}
+
+	schedule, err := scanSchedule(config.DB.QueryRow(scheduleSelectQuery+" WHERE s.id = $1", id))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	w.Header().Set("ETag", versionETag(schedule.Version))
+	respondWithJSON(w, http.StatusOK, schedule)
 }
 
-// DeleteSchedule handler untuk menghapus jadwal tayang
+// DeleteSchedule handler untuk menghapus (soft delete) jadwal tayang.
+// Jadwal yang masih punya tiket PAID ditolak sampai booking-nya dibatalkan dan di-refund;
+// booking PENDING ikut dibatalkan dan kuota promonya dikembalikan. If-Match wajib seperti pada update.
 func DeleteSchedule(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Ambil ID dari URL path
 	id := extractIDFromPath(r.URL.Path, "/api/schedules/")
 	if id == 0 {
-		respondWithError(w, http.StatusBadRequest, "Invalid schedule ID")
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid schedule ID")
 		return
 	}
 
-	query := "DELETE FROM schedules WHERE id = $1"
-	result, err := config.DB.Exec(query, id)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to delete schedule")
+	tags, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil || rowsAffected == 0 {
-		respondWithError(w, http.StatusNotFound, "Schedule not found")
+	var version int
+	_, err := withAudit(r, audit.ActionDelete, "schedules", id, func(tx *sql.Tx) (int, error) {
+		if err := checkScheduleVersion(tx, id, tags, &version); err != nil {
+			return id, err
+		}
+
+		var paid int
+		err := tx.QueryRow("SELECT COUNT(*) FROM transactions WHERE schedule_id = $1 AND status = $2",
+			id, models.TransactionStatusPaid).Scan(&paid)
+		if err != nil {
+			return id, err
+		}
+		if paid > 0 {
+			return id, errScheduleHasPaidTickets
+		}
+
+		if err := execOne(tx, "UPDATE schedules SET deleted_at = NOW(), version = version + 1 WHERE id = $1", id); err != nil {
+			return id, err
+		}
+		if err := waitlist.CancelSchedule(tx, audit.FromRequest(r), id); err != nil {
+			return id, err
+		}
+		return id, webhook.PublishSchedule(tx, models.WebhookEventScheduleCancelled, id)
+	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
+		return
+	}
+	if err == errPreconditionFailed {
+		respondPreconditionFailed(w, r, version)
 		return
 	}
+	if err == errScheduleHasPaidTickets {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeScheduleHasPaidTickets,
+			"Schedule has paid tickets; cancel and refund the bookings before deleting it")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to delete schedule")
+		return
+	}
+	events.Publish(r.Context(), events.ScheduleCancelled{ScheduleID: id, Deleted: true})
 
 	respondWithJSON(w, http.StatusOK, map[string]string{
 		"message": "Schedule deleted successfully",
 	})
 }
 
+// RestoreSchedule handler untuk mengembalikan jadwal yang sudah di-soft delete (admin).
+// Path: /api/schedules/{id}/restore. Ditolak jika studio sudah dipakai jadwal lain pada jam yang sama.
+func RestoreSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(strings.TrimSuffix(r.URL.Path, "/restore"), "/api/schedules/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid schedule ID")
+		return
+	}
+
+	var conflictID int
+	_, err := withAudit(r, audit.ActionUpdate, "schedules", id, func(tx *sql.Tx) (int, error) {
+		var deleted bool
+		err := tx.QueryRow("SELECT deleted_at IS NOT NULL FROM schedules WHERE id = $1", id).Scan(&deleted)
+		if err != nil {
+			return id, err
+		}
+		if !deleted {
+			return id, errScheduleNotDeleted
+		}
+
+		err = tx.QueryRow(`
+			SELECT o.id
+			FROM schedules s
+			JOIN schedules o ON o.studio_id = s.studio_id AND o.id <> s.id
+			WHERE s.id = $1 AND o.deleted_at IS NULL AND o.status <> $2
+				AND o.start_time < s.end_time AND o.end_time > s.start_time
+			ORDER BY o.start_time
+			LIMIT 1
+		`, id, models.ScheduleStatusCancelled).Scan(&conflictID)
+		if err == nil {
+			return id, errScheduleOverlap
+		}
+		if err != sql.ErrNoRows {
+			return id, err
+		}
+
+		_, err = tx.Exec("UPDATE schedules SET deleted_at = NULL, version = version + 1 WHERE id = $1", id)
+		if err != nil {
+			return id, err
+		}
+		return id, webhook.PublishSchedule(tx, models.WebhookEventScheduleUpdated, id)
+	})
+	switch {
+	case err == sql.ErrNoRows:
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
+	case err == errScheduleNotDeleted:
+		respondWithError(w, r, http.StatusConflict, apierror.CodeScheduleNotDeleted, "Schedule is not deleted")
+	case err == errScheduleOverlap:
+		respondWithError(w, r, http.StatusConflict, apierror.CodeScheduleOverlap,
+			fmt.Sprintf("Schedule overlaps with schedule %d in the same studio", conflictID))
+	case err != nil:
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to restore schedule")
+	default:
+		events.Publish(r.Context(), events.ScheduleRestored{ScheduleID: id})
+		respondWithJSON(w, http.StatusOK, map[string]string{
+			"message": "Schedule restored successfully",
+		})
+	}
+}
+
+var (
+	errScheduleHasPaidTickets = errors.New("schedule has paid tickets")
+	errScheduleNotDeleted     = errors.New("schedule is not deleted")
+	errScheduleOverlap        = errors.New("schedule overlaps another schedule")
+)
+
+// checkScheduleVersion membandingkan version jadwal yang belum dihapus dengan If-Match.
+// Version terbaru ditulis ke version agar bisa dikirim kembali sebagai ETag pada 412.
+func checkScheduleVersion(tx *sql.Tx, id int, tags []string, version *int) error {
+	err := tx.QueryRow("SELECT version FROM schedules WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(version)
+	if err != nil {
+		return err
+	}
+	if !matchesVersion(tags, *version) {
+		return errPreconditionFailed
+	}
+	return nil
+}
+
+// execOne menjalankan statement yang harus mengubah tepat satu baris; sql.ErrNoRows jika tidak ada
+func execOne(tx *sql.Tx, query string, args ...interface{}) error {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// scheduleVisibleCondition kondisi SQL jadwal yang boleh tampil: jadwal terhapus hanya
+// untuk admin yang meminta ?include_deleted=true
+func scheduleVisibleCondition(r *http.Request) string {
+	if r.URL.Query().Get("include_deleted") == "true" && middleware.IsAdmin(r.Context()) {
+		return "TRUE"
+	}
+	return "s.deleted_at IS NULL"
+}
+
 // Helper function untuk extract ID dari URL path
 func extractIDFromPath(path string, prefix string) int {
 	idStr := strings.TrimPrefix(path, prefix)
@@ -321,3 +596,39 @@ func extractIDFromPath(path string, prefix string) int {
 	}
 	return id
 }
+
+// studioLocation mengambil timezone cinema tempat studio berada
+func studioLocation(studioID int) (*time.Location, error) {
+	var timezone string
+	query := `
+		SELECT c.timezone
+		FROM studios st
+		JOIN cinemas c ON st.cinema_id = c.id
+		WHERE st.id = $1
+	`
+	if err := config.DB.QueryRow(query, studioID).Scan(&timezone); err != nil {
+		return nil, err
+	}
+	return config.LoadLocation(timezone), nil
+}
+
+// parseShowtime menerima RFC 3339 (dengan offset) atau waktu lokal cinema tanpa offset
+func parseShowtime(value string, loc *time.Location) (time.Time, error) {
+	return validation.ParseDateTimeIn(value, loc)
+}
+
+func unknownStudioError() apierror.FieldError {
+	return apierror.FieldError{
+		Field:   "studio_id",
+		Code:    validation.CodeUnknownReference,
+		Message: "studio_id does not exist",
+	}
+}
+
+func invalidTimeRangeError() apierror.FieldError {
+	return apierror.FieldError{
+		Field:   "end_time",
+		Code:    validation.CodeInvalidRange,
+		Message: "end_time must be after start_time",
+	}
+}
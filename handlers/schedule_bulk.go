@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"mkp/apierror"
+	"mkp/config"
+	"mkp/events"
+	"mkp/models"
+	"mkp/overlap"
+	"mkp/recurrence"
+	"mkp/validation"
+	"mkp/webhook"
+	"net/http"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CreateSchedulesBulk handler untuk membuat banyak jadwal tayang sekaligus.
+// Semua jadwal divalidasi (termasuk bentrok dengan jadwal lain di studio yang sama)
+// lalu di-insert dalam satu transaksi. Dengan dry_run=true hanya laporan per item yang dikembalikan.
+func CreateSchedulesBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req models.ScheduleBulkRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	if r.URL.Query().Get("dry_run") == "true" {
+		req.DryRun = true
+	}
+	if req.Status == "" {
+		req.Status = models.ScheduleStatusShowing
+	}
+
+	// Durasi default mengikuti durasi film
+	var duration int
+	err := config.DB.QueryRow("SELECT duration_minutes FROM movies WHERE id = $1", req.MovieID).Scan(&duration)
+	if err == sql.ErrNoRows {
+		apierror.Write(w, r, validation.Problem([]apierror.FieldError{{
+			Field: "movie_id", Code: validation.CodeUnknownReference, Message: "movie_id does not exist",
+		}}))
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	if req.DurationMinutes != nil {
+		duration = *req.DurationMinutes
+	}
+
+	locations := map[int]*time.Location{}
+	for i, studioID := range req.StudioIDs {
+		loc, err := studioLocation(studioID)
+		if err == sql.ErrNoRows {
+			apierror.Write(w, r, validation.Problem([]apierror.FieldError{{
+				Field:   fmt.Sprintf("studio_ids[%d]", i),
+				Code:    validation.CodeUnknownReference,
+				Message: fmt.Sprintf("studio %d does not exist", studioID),
+			}}))
+			return
+		}
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+			return
+		}
+		locations[studioID] = loc
+	}
+
+	items := expandBulkSchedules(req, locations, time.Duration(duration)*time.Minute)
+	if len(items) == 0 {
+		apierror.Write(w, r, validation.Problem([]apierror.FieldError{{
+			Field: "recurrence", Code: validation.CodeInvalidRange, Message: "recurrence produces no dates in the given range",
+		}}))
+		return
+	}
+	if len(items) > models.MaxBulkSchedules {
+		apierror.Write(w, r, validation.Problem([]apierror.FieldError{{
+			Field:   "time_slots",
+			Code:    validation.CodeOutOfRange,
+			Message: fmt.Sprintf("request expands to %d schedules, maximum is %d", len(items), models.MaxBulkSchedules),
+		}}))
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer tx.Rollback()
+
+	// Kunci studio agar bulk create paralel untuk studio yang sama tidak saling lolos cek bentrok
+	_, err = tx.Exec("SELECT id FROM studios WHERE id = ANY($1) ORDER BY id FOR UPDATE", pq.Array(req.StudioIDs))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	if err := checkBulkSchedules(tx, items, time.Now()); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	result := models.ScheduleBulkResult{DryRun: req.DryRun, Total: len(items), Items: items}
+	for _, item := range items {
+		if len(item.Errors) > 0 {
+			result.Invalid++
+		}
+	}
+	result.Valid = result.Invalid == 0
+
+	if req.DryRun {
+		respondWithJSON(w, http.StatusOK, result)
+		return
+	}
+
+	if !result.Valid {
+		problem := apierror.New(http.StatusUnprocessableEntity, apierror.CodeBulkScheduleInvalid,
+			fmt.Sprintf("%d of %d schedules are invalid, nothing was created", result.Invalid, result.Total))
+		for _, item := range items {
+			for _, fieldErr := range item.Errors {
+				fieldErr.Field = fmt.Sprintf("items[%d].%s", item.Index, fieldErr.Field)
+				problem.WithErrors(fieldErr)
+			}
+		}
+		apierror.Write(w, r, problem)
+		return
+	}
+
+	query := `
+		INSERT INTO schedules (movie_id, studio_id, start_time, end_time, price, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id
+	`
+	for i := range items {
+		err := tx.QueryRow(query, req.MovieID, items[i].StudioID, items[i].StartTime, items[i].EndTime, req.Price, req.Status).
+			Scan(&items[i].ID)
+		if err == nil {
+			err = recordCreated(tx, r, "schedules", items[i].ID)
+		}
+		if err == nil {
+			err = webhook.PublishSchedule(tx, models.WebhookEventScheduleCreated, items[i].ID)
+		}
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create schedules")
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create schedules")
+		return
+	}
+	for _, item := range items {
+		events.Publish(r.Context(), events.ScheduleCreated{ScheduleID: item.ID})
+	}
+
+	respondWithJSON(w, http.StatusCreated, result)
+}
+
+// expandBulkSchedules mengubah bulk request menjadi daftar jadwal konkret per studio, tanggal dan time slot
+func expandBulkSchedules(req models.ScheduleBulkRequest, locations map[int]*time.Location, duration time.Duration) []models.ScheduleBulkItem {
+	rule := recurrence.Daily()
+	if req.Recurrence != "" {
+		rule, _ = recurrence.Parse(req.Recurrence)
+	}
+
+	startDate, _ := time.Parse(validation.DateLayout, req.StartDate)
+	endDate, _ := time.Parse(validation.DateLayout, req.EndDate)
+
+	items := []models.ScheduleBulkItem{}
+	for _, date := range rule.Dates(startDate, endDate) {
+		for _, studioID := range req.StudioIDs {
+			loc := locations[studioID]
+			for _, slot := range req.TimeSlots {
+				clock, _ := time.Parse(validation.ClockLayout, slot)
+				start := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+				end := start.Add(duration)
+
+				items = append(items, models.ScheduleBulkItem{
+					Index:          len(items),
+					StudioID:       studioID,
+					StartTime:      start.UTC(),
+					EndTime:        end.UTC(),
+					StartTimeLocal: start.Format(time.RFC3339),
+					EndTimeLocal:   end.Format(time.RFC3339),
+					Errors:         []apierror.FieldError{},
+				})
+			}
+		}
+	}
+	return items
+}
+
+// checkBulkSchedules mengisi Errors setiap item: waktu di masa lalu, bentrok dengan
+// jadwal lain di batch yang sama, dan bentrok dengan jadwal tersimpan yang tidak CANCELLED
+func checkBulkSchedules(tx *sql.Tx, items []models.ScheduleBulkItem, now time.Time) error {
+	slots := make([]overlap.Slot, len(items))
+	for i := range items {
+		if !items[i].StartTime.After(now) {
+			items[i].Errors = append(items[i].Errors, apierror.FieldError{
+				Field: "start_time", Code: validation.CodeInPast, Message: "start_time is in the past",
+			})
+		}
+		slots[i] = overlap.Slot{StudioID: items[i].StudioID, Start: items[i].StartTime, End: items[i].EndTime}
+	}
+
+	conflicts, err := overlap.Find(tx, slots)
+	if err != nil {
+		return err
+	}
+	for _, conflict := range conflicts {
+		message := fmt.Sprintf("overlaps with existing schedule %d", conflict.ScheduleID)
+		if conflict.WithSlot >= 0 {
+			message = fmt.Sprintf("overlaps with item %d in this request", items[conflict.WithSlot].Index)
+		}
+		items[conflict.Slot].Errors = append(items[conflict.Slot].Errors, apierror.FieldError{
+			Field: "start_time", Code: validation.CodeScheduleOverlap, Message: message,
+		})
+	}
+	return nil
+}
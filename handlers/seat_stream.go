@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mkp/apierror"
+	"mkp/config"
+	"mkp/models"
+	"mkp/seatstream"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// seatStreamHeartbeat jeda komentar keep-alive agar proxy tidak menutup koneksi yang diam
+const seatStreamHeartbeat = 15 * time.Second
+
+// seatSnapshot status awal kursi saat client tersambung; kursi yang tidak disebut berarti tersedia
+type seatSnapshot struct {
+	ScheduleID int   `json:"schedule_id"`
+	Held       []int `json:"held"`
+	Sold       []int `json:"sold"`
+}
+
+// StreamScheduleSeats handler Server-Sent Events perubahan status kursi sebuah jadwal.
+// Path: /api/schedules/{id}/seats/stream. Event pertama "snapshot" berisi kursi held dan sold,
+// setelah itu event "held", "released" atau "sold" dengan data {schedule_id, state, seat_ids}.
+// Stream ditutup server jika client tertinggal; EventSource akan menyambung ulang sendiri.
+// Tidak perlu login karena EventSource tidak bisa mengirim header Authorization; status kursi
+// sama publiknya dengan showtimes.
+func StreamScheduleSeats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(strings.TrimSuffix(r.URL.Path, "/seats/stream"), "/api/schedules/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid schedule ID")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeInternalError, "Streaming is not supported")
+		return
+	}
+
+	var exists bool
+	err := config.DB.QueryRow("SELECT TRUE FROM schedules s WHERE s.id = $1 AND "+scheduleVisibleCondition(r), id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	// Subscribe sebelum snapshot agar perubahan di antaranya tidak terlewat
+	changes, cancel := seatstream.DefaultHub.Subscribe(id)
+	defer cancel()
+
+	snapshot, err := loadSeatSnapshot(id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	writeSSE(w, "snapshot", snapshot)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(seatStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			writeSSE(w, change.State, change)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// loadSeatSnapshot mengambil kursi yang sedang ditahan booking PENDING dan yang sudah terjual
+func loadSeatSnapshot(scheduleID int) (seatSnapshot, error) {
+	snapshot := seatSnapshot{ScheduleID: scheduleID}
+	var held, sold pq.Int64Array
+	err := config.DB.QueryRow(`
+		SELECT
+			COALESCE(array_agg(t.seat_id ORDER BY t.seat_id) FILTER (WHERE tr.status = $2), '{}'),
+			COALESCE(array_agg(t.seat_id ORDER BY t.seat_id) FILTER (WHERE tr.status = $3), '{}')
+		FROM tickets t
+		JOIN transactions tr ON t.transaction_id = tr.id
+		WHERE t.schedule_id = $1
+	`, scheduleID, models.TransactionStatusPending, models.TransactionStatusPaid).Scan(&held, &sold)
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.Held = toInts(held)
+	snapshot.Sold = toInts(sold)
+	return snapshot, nil
+}
+
+// writeSSE menulis satu event SSE dengan data JSON
+func writeSSE(w http.ResponseWriter, event string, data interface{}) {
+	payload, _ := json.Marshal(data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
+
+func toInts(values pq.Int64Array) []int {
+	ints := make([]int, len(values))
+	for i, v := range values {
+		ints[i] = int(v)
+	}
+	return ints
+}
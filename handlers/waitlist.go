@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/middleware"
+	"mkp/models"
+	"mkp/waitlist"
+	"net/http"
+	"time"
+)
+
+// JoinWaitlist handler untuk masuk waitlist jadwal yang kursinya tidak cukup.
+// Saat kursi terlepas user mendapat tawaran (status OFFERED) dan harus booking sebelum batas waktunya.
+func JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	var req models.WaitlistRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer tx.Rollback()
+
+	// Kunci jadwal seperti pada booking agar jumlah kursi kosong tidak berubah saat dicek
+	var status string
+	var startTime time.Time
+	err = tx.QueryRow("SELECT status, start_time FROM schedules WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", req.ScheduleID).
+		Scan(&status, &startTime)
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeScheduleNotFound, "Schedule not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	if status != models.ScheduleStatusShowing || !startTime.After(time.Now()) {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeScheduleNotBookable, "Schedule is not open for booking")
+		return
+	}
+
+	available, err := waitlist.Available(tx, req.ScheduleID, userID)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	if available >= req.Seats {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeWaitlistNotNeeded, "Enough seats are available, book them directly")
+		return
+	}
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO waitlist_entries (schedule_id, user_id, seats, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id
+	`, req.ScheduleID, userID, req.Seats, models.WaitlistStatusWaiting).Scan(&id)
+	if isUniqueViolation(err) {
+		respondWithError(w, r, http.StatusConflict, apierror.CodeWaitlistAlreadyJoined, "You are already on the waitlist for this schedule")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to join waitlist")
+		return
+	}
+
+	if err := recordCreated(tx, r, "waitlist_entries", id); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to join waitlist")
+		return
+	}
+
+	entry, err := waitlist.Scan(tx.QueryRow(waitlist.SelectQuery+" WHERE w.id = $1", id))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to join waitlist")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to join waitlist")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, entry)
+}
+
+// GetMyWaitlist handler untuk melihat entry waitlist aktif milik user yang sedang login
+func GetMyWaitlist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	rows, err := config.DB.Query(waitlist.SelectQuery+" WHERE w.user_id = $1 AND w.status IN ($2, $3) ORDER BY w.created_at",
+		userID, models.WaitlistStatusWaiting, models.WaitlistStatusOffered)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	entries := []models.WaitlistEntry{}
+	for rows.Next() {
+		entry, err := waitlist.Scan(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	respondWithJSON(w, http.StatusOK, entries)
+}
+
+// LeaveWaitlist handler untuk keluar dari waitlist. Kursi yang sedang ditawarkan ke user
+// langsung ditawarkan ke antrian berikutnya.
+func LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/waitlist/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid waitlist entry ID")
+		return
+	}
+
+	userID, _ := middleware.UserID(r.Context())
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer tx.Rollback()
+
+	// Jadwal dikunci lebih dulu, urutannya sama dengan booking
+	var scheduleID int
+	err = tx.QueryRow(`
+		SELECT s.id FROM waitlist_entries w JOIN schedules s ON w.schedule_id = s.id
+		WHERE w.id = $1 AND w.user_id = $2 AND w.status IN ($3, $4)
+		FOR UPDATE OF s
+	`, id, userID, models.WaitlistStatusWaiting, models.WaitlistStatusOffered).Scan(&scheduleID)
+	var before, after json.RawMessage
+	if err == nil {
+		before, err = audit.Snapshot(tx, "waitlist_entries", id)
+	}
+	if err == nil {
+		_, err = tx.Exec("UPDATE waitlist_entries SET status = $1, updated_at = NOW() WHERE id = $2",
+			models.WaitlistStatusCancelled, id)
+	}
+	if err == nil {
+		after, err = audit.Snapshot(tx, "waitlist_entries", id)
+	}
+	if err == nil {
+		err = audit.Record(tx, audit.FromRequest(r), audit.ActionUpdate, "waitlist_entries", &id, before, after)
+	}
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeWaitlistNotFound, "Waitlist entry not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to leave waitlist")
+		return
+	}
+
+	if _, err := waitlist.Offer(tx, scheduleID); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to leave waitlist")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to leave waitlist")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Left waitlist successfully",
+	})
+}
@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/models"
+	"mkp/validation"
+	"mkp/webhook"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// maxWebhookDeliveryLimit batas jumlah delivery per halaman
+const maxWebhookDeliveryLimit = 200
+
+const webhookSelectQuery = `
+	SELECT id, url, description, event_types, active, created_at, updated_at
+	FROM webhook_subscriptions
+`
+
+const webhookDeliverySelectQuery = `
+	SELECT id, subscription_id, event_id, event_type, payload, status, attempts, last_status_code, last_error,
+		last_attempt_at, CASE WHEN status = 'PENDING' THEN next_attempt_at END, redelivery_of, created_at, delivered_at
+	FROM webhook_deliveries
+`
+
+// scanWebhook membaca satu baris hasil webhookSelectQuery (tanpa secret)
+func scanWebhook(row rowScanner) (models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	var eventTypes pq.StringArray
+	err := row.Scan(&subscription.ID, &subscription.URL, &subscription.Description, &eventTypes,
+		&subscription.Active, &subscription.CreatedAt, &subscription.UpdatedAt)
+	subscription.EventTypes = []string(eventTypes)
+	return subscription, err
+}
+
+// scanWebhookDelivery membaca satu baris hasil webhookDeliverySelectQuery
+func scanWebhookDelivery(row rowScanner) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload []byte
+	err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &payload,
+		&delivery.Status, &delivery.Attempts, &delivery.LastStatusCode, &delivery.LastError,
+		&delivery.LastAttemptAt, &delivery.NextAttemptAt, &delivery.RedeliveryOf, &delivery.CreatedAt, &delivery.DeliveredAt)
+	delivery.Payload = payload
+	return delivery, err
+}
+
+// GetWebhooks handler untuk mendapatkan semua webhook subscription (admin)
+func GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	rows, err := config.DB.Query(webhookSelectQuery + " ORDER BY id")
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhook(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	respondWithJSON(w, http.StatusOK, subscriptions)
+}
+
+// GetWebhookByID handler untuk mendapatkan webhook subscription berdasarkan ID (admin)
+func GetWebhookByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/webhooks/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid webhook ID")
+		return
+	}
+
+	subscription, err := scanWebhook(config.DB.QueryRow(webhookSelectQuery+" WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeWebhookNotFound, "Webhook not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, subscription)
+}
+
+// CreateWebhook handler untuk mendaftarkan endpoint partner (admin). Secret dibuatkan jika
+// tidak dikirim dan hanya ditampilkan sekali di response ini.
+func CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := webhook.NewSecret()
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeInternalError, "Failed to generate secret")
+			return
+		}
+		secret = "whsec_" + generated
+	}
+	active := req.Active == nil || *req.Active
+
+	query := `
+		INSERT INTO webhook_subscriptions (url, description, event_types, secret, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id
+	`
+	id, err := withAudit(r, audit.ActionCreate, "webhook_subscriptions", 0, func(tx *sql.Tx) (int, error) {
+		var id int
+		err := tx.QueryRow(query, req.URL, req.Description, pq.StringArray(req.EventTypes), secret, active).Scan(&id)
+		return id, err
+	})
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to create webhook")
+		return
+	}
+
+	subscription, err := scanWebhook(config.DB.QueryRow(webhookSelectQuery+" WHERE id = $1", id))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	subscription.Secret = secret
+
+	respondWithJSON(w, http.StatusCreated, subscription)
+}
+
+// UpdateWebhook handler untuk mengganti seluruh isi webhook subscription (admin).
+// Secret kosong berarti secret lama tetap dipakai.
+func UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/webhooks/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid webhook ID")
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	active := req.Active == nil || *req.Active
+
+	query := `
+		UPDATE webhook_subscriptions
+		SET url = $1, description = $2, event_types = $3, secret = COALESCE(NULLIF($4, ''), secret),
+			active = $5, updated_at = NOW()
+		WHERE id = $6
+	`
+	_, err := withAudit(r, audit.ActionUpdate, "webhook_subscriptions", id, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, req.URL, req.Description, pq.StringArray(req.EventTypes), req.Secret, active, id)
+		return id, err
+	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeWebhookNotFound, "Webhook not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to update webhook")
+		return
+	}
+
+	subscription, err := scanWebhook(config.DB.QueryRow(webhookSelectQuery+" WHERE id = $1", id))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, subscription)
+}
+
+// DeleteWebhook handler untuk menghapus webhook subscription beserta riwayat delivery-nya (admin).
+// Untuk menghentikan pengiriman sementara cukup set active false.
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/webhooks/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid webhook ID")
+		return
+	}
+
+	_, err := withAudit(r, audit.ActionDelete, "webhook_subscriptions", id, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec("DELETE FROM webhook_subscriptions WHERE id = $1", id)
+		return id, err
+	})
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeWebhookNotFound, "Webhook not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to delete webhook")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// GetWebhookDeliveries handler untuk melihat riwayat delivery webhook (admin), terbaru lebih dulu.
+// Filter: subscription_id, status, event_type, event_id. Paginasi dengan limit dan before_id.
+func GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	fieldErrors := []apierror.FieldError{}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	for _, filter := range []struct{ param, column string }{
+		{"event_type", "event_type"},
+		{"event_id", "event_id"},
+	} {
+		if value := strings.TrimSpace(params.Get(filter.param)); value != "" {
+			addCondition(filter.column+" = ?", value)
+		}
+	}
+	if status := params.Get("status"); status != "" {
+		addCondition("status = ?", strings.ToUpper(status))
+	}
+	for _, filter := range []struct{ param, condition string }{
+		{"subscription_id", "subscription_id = ?"},
+		{"before_id", "id < ?"},
+	} {
+		value := params.Get(filter.param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: filter.param, Code: validation.CodeOutOfRange, Message: filter.param + " must be a positive integer",
+			})
+			continue
+		}
+		addCondition(filter.condition, id)
+	}
+
+	limit := 50
+	if value := params.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxWebhookDeliveryLimit {
+			fieldErrors = append(fieldErrors, apierror.FieldError{
+				Field: "limit", Code: validation.CodeOutOfRange, Message: fmt.Sprintf("limit must be between 1 and %d", maxWebhookDeliveryLimit),
+			})
+		}
+		limit = n
+	}
+
+	if len(fieldErrors) > 0 {
+		apierror.Write(w, r, validation.Problem(fieldErrors))
+		return
+	}
+
+	args = append(args, limit)
+	query := webhookDeliverySelectQuery + " WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY id DESC LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Error scanning data")
+			return
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	respondWithJSON(w, http.StatusOK, deliveries)
+}
+
+// GetWebhookDeliveryByID handler untuk melihat satu delivery beserta payload-nya (admin)
+func GetWebhookDeliveryByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(r.URL.Path, "/api/admin/webhook-deliveries/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := scanWebhookDelivery(config.DB.QueryRow(webhookDeliverySelectQuery+" WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeWebhookDeliveryNotFound, "Webhook delivery not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, delivery)
+}
+
+// RedeliverWebhook handler untuk mengirim ulang delivery lama (admin).
+// Path: /api/admin/webhook-deliveries/{id}/redeliver. Payload dan event ID yang sama
+// dikirim sebagai delivery baru, sehingga riwayat delivery asal tetap utuh.
+func RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := extractIDFromPath(strings.TrimSuffix(r.URL.Path, "/redeliver"), "/api/admin/webhook-deliveries/")
+	if id == 0 {
+		respondWithError(w, r, http.StatusBadRequest, apierror.CodeInvalidID, "Invalid delivery ID")
+		return
+	}
+
+	var newID int64
+	err := config.DB.QueryRow(`
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts,
+			next_attempt_at, redelivery_of, created_at)
+		SELECT subscription_id, event_id, event_type, payload, $1, 0, NOW(), id, NOW()
+		FROM webhook_deliveries
+		WHERE id = $2
+		RETURNING id
+	`, models.WebhookDeliveryPending, id).Scan(&newID)
+	if err == sql.ErrNoRows {
+		respondWithError(w, r, http.StatusNotFound, apierror.CodeWebhookDeliveryNotFound, "Webhook delivery not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Failed to redeliver webhook")
+		return
+	}
+
+	delivery, err := scanWebhookDelivery(config.DB.QueryRow(webhookDeliverySelectQuery+" WHERE id = $1", newID))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, delivery)
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// ContentType MIME type untuk file iCalendar
+const ContentType = "text/calendar; charset=utf-8"
+
+// Status event (RFC 5545 section 3.8.1.11)
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// maxLineOctets panjang maksimum satu baris sebelum di-fold
+const maxLineOctets = 75
+
+// timeLayout format DATE-TIME UTC, misal 20241205T070000Z
+const timeLayout = "20060102T150405Z"
+
+// Calendar satu file iCalendar (VCALENDAR)
+type Calendar struct {
+	// Name nama kalender yang ditampilkan aplikasi kalender (X-WR-CALNAME)
+	Name string
+	// Timezone timezone default kalender (X-WR-TIMEZONE), hanya informasi; waktu event selalu UTC
+	Timezone string
+	Events   []Event
+}
+
+// Event satu VEVENT. Start dan End ditulis dalam UTC.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	Start       time.Time
+	End         time.Time
+	Updated     time.Time
+}
+
+// Write menulis kalender sesuai RFC 5545: CRLF, escape teks dan line folding 75 octet
+func (c Calendar) Write(w io.Writer, now time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:-//MKP Cinema//Showtimes//ID")
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	writeLine(bw, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(bw, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	if c.Timezone != "" {
+		writeLine(bw, "X-WR-TIMEZONE:"+c.Timezone)
+	}
+
+	for _, e := range c.Events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+e.UID)
+		writeLine(bw, "DTSTAMP:"+now.UTC().Format(timeLayout))
+		writeLine(bw, "DTSTART:"+e.Start.UTC().Format(timeLayout))
+		writeLine(bw, "DTEND:"+e.End.UTC().Format(timeLayout))
+		if !e.Updated.IsZero() {
+			writeLine(bw, "LAST-MODIFIED:"+e.Updated.UTC().Format(timeLayout))
+		}
+		writeLine(bw, "SUMMARY:"+escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		if e.Location != "" {
+			writeLine(bw, "LOCATION:"+escapeText(e.Location))
+		}
+		if e.Status != "" {
+			writeLine(bw, "STATUS:"+e.Status)
+		}
+		writeLine(bw, "END:VEVENT")
+	}
+
+	writeLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escape karakter khusus pada nilai TEXT (RFC 5545 section 3.3.11)
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// writeLine menulis satu content line; baris lebih dari 75 octet dipotong dan dilanjutkan
+// dengan CRLF + spasi tanpa memotong karakter UTF-8 di tengah
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Spasi di awal baris lanjutan ikut dihitung
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
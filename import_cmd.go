@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mkp/config"
+	"mkp/importer"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runImport menjalankan subcommand import dan mengembalikan exit code.
+// Format file ditentukan dari ekstensi (.csv atau .json) kecuali diisi lewat -format.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	entity := fs.String("entity", "", "entity: "+strings.Join(importer.Entities(), ", "))
+	file := fs.String("file", "", "path file CSV atau JSON")
+	format := fs.String("format", "", "csv atau json (default dari ekstensi file)")
+	dryRun := fs.Bool("dry-run", false, "validasi saja tanpa menyimpan")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *entity == "" || *file == "" {
+		fmt.Fprintln(os.Stderr, "usage: import -entity <entity> -file <path> [-format csv|json] [-dry-run]")
+		return 2
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer f.Close()
+
+	config.InitDB()
+	defer config.CloseDB()
+
+	result, err := importer.Import(config.DB, cliActor, *entity, *format, f, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "import failed:", err)
+		return 1
+	}
+
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+	if len(result.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,460 @@
+package importer
+
+import (
+	"database/sql"
+	"fmt"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/events"
+	"mkp/models"
+	"mkp/notify"
+	"mkp/overlap"
+	"mkp/validation"
+	"mkp/webhook"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// movieRow satu baris import film. Tanpa id, film dicocokkan berdasarkan judul (case-insensitive).
+type movieRow struct {
+	ID              *int    `json:"id" validate:"gt=0"`
+	Title           string  `json:"title" validate:"required,max=255"`
+	Description     *string `json:"description"`
+	DurationMinutes int     `json:"duration_minutes" validate:"required,gt=0"`
+	ReleaseDate     *string `json:"release_date" validate:"date"`
+}
+
+type movieImporter struct{}
+
+func (movieImporter) table() string { return "movies" }
+
+func (movieImporter) parse(rec record) (interface{}, []apierror.FieldError) {
+	errs := []apierror.FieldError{}
+	row := &movieRow{
+		ID:              rec.optionalInt("id", &errs),
+		Title:           rec["title"],
+		Description:     rec.optionalString("description"),
+		DurationMinutes: rec.int("duration_minutes", &errs),
+		ReleaseDate:     rec.optionalString("release_date"),
+	}
+	return row, errs
+}
+
+func (movieImporter) check(tx *sql.Tx, row interface{}) ([]apierror.FieldError, error) {
+	return nil, nil
+}
+
+func (movieImporter) upsert(tx *sql.Tx, actor audit.Actor, row interface{}) (bool, error) {
+	m := row.(*movieRow)
+	return upsert(tx, actor, "movies", m.ID,
+		"SELECT id FROM movies WHERE LOWER(title) = LOWER($1) ORDER BY id LIMIT 1", []interface{}{m.Title},
+		"UPDATE movies SET title = $2, description = $3, duration_minutes = $4, release_date = $5 WHERE id = $1",
+		`INSERT INTO movies (id, title, description, duration_minutes, release_date, created_at)
+		VALUES (COALESCE($1, nextval(pg_get_serial_sequence('movies', 'id'))), $2, $3, $4, $5, NOW())`,
+		m.Title, m.Description, m.DurationMinutes, m.ReleaseDate,
+	)
+}
+
+// cinemaRow satu baris import cinema. Tanpa id, cinema dicocokkan berdasarkan nama.
+type cinemaRow struct {
+	ID       *int    `json:"id" validate:"gt=0"`
+	Name     string  `json:"name" validate:"required,max=255"`
+	City     string  `json:"city" validate:"required,max=255"`
+	Address  *string `json:"address"`
+	Timezone string  `json:"timezone" validate:"required"`
+}
+
+// Validate memastikan timezone adalah nama IANA yang dikenal
+func (c *cinemaRow) Validate() []apierror.FieldError {
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return []apierror.FieldError{{
+			Field: "timezone", Code: validation.CodeInvalidEnum, Message: "timezone must be a valid IANA time zone, e.g. Asia/Jakarta",
+		}}
+	}
+	return nil
+}
+
+type cinemaImporter struct{}
+
+func (cinemaImporter) table() string { return "cinemas" }
+
+func (cinemaImporter) parse(rec record) (interface{}, []apierror.FieldError) {
+	errs := []apierror.FieldError{}
+	row := &cinemaRow{
+		ID:       rec.optionalInt("id", &errs),
+		Name:     rec["name"],
+		City:     rec["city"],
+		Address:  rec.optionalString("address"),
+		Timezone: rec["timezone"],
+	}
+	if row.Timezone == "" {
+		row.Timezone = config.DefaultTimezone
+	}
+	return row, errs
+}
+
+func (cinemaImporter) check(tx *sql.Tx, row interface{}) ([]apierror.FieldError, error) {
+	return nil, nil
+}
+
+func (cinemaImporter) upsert(tx *sql.Tx, actor audit.Actor, row interface{}) (bool, error) {
+	c := row.(*cinemaRow)
+	return upsert(tx, actor, "cinemas", c.ID,
+		"SELECT id FROM cinemas WHERE LOWER(name) = LOWER($1) ORDER BY id LIMIT 1", []interface{}{c.Name},
+		"UPDATE cinemas SET name = $2, city = $3, address = $4, timezone = $5 WHERE id = $1",
+		`INSERT INTO cinemas (id, name, city, address, timezone, created_at)
+		VALUES (COALESCE($1, nextval(pg_get_serial_sequence('cinemas', 'id'))), $2, $3, $4, $5, NOW())`,
+		c.Name, c.City, c.Address, c.Timezone,
+	)
+}
+
+// studioRow satu baris import studio. Tanpa id, studio dicocokkan berdasarkan cinema_id dan nama.
+// total_seats maksimal 260 (26 baris A..Z x 10 kursi) dan tidak bisa diubah untuk studio yang
+// sudah ada karena kursinya sudah dibuat.
+type studioRow struct {
+	ID         *int   `json:"id" validate:"gt=0"`
+	CinemaID   int    `json:"cinema_id" validate:"required,gt=0"`
+	Name       string `json:"name" validate:"required,max=255"`
+	TotalSeats int    `json:"total_seats" validate:"required,gt=0,max=260"`
+	StudioType string `json:"studio_type" validate:"required,oneof=REGULAR IMAX PREMIERE"`
+}
+
+type studioImporter struct{}
+
+func (studioImporter) table() string { return "studios" }
+
+func (studioImporter) parse(rec record) (interface{}, []apierror.FieldError) {
+	errs := []apierror.FieldError{}
+	row := &studioRow{
+		ID:         rec.optionalInt("id", &errs),
+		CinemaID:   rec.int("cinema_id", &errs),
+		Name:       rec["name"],
+		TotalSeats: rec.int("total_seats", &errs),
+		StudioType: rec["studio_type"],
+	}
+	if row.StudioType == "" {
+		row.StudioType = models.StudioTypeRegular
+	}
+	return row, errs
+}
+
+func (studioImporter) check(tx *sql.Tx, row interface{}) ([]apierror.FieldError, error) {
+	s := row.(*studioRow)
+	found, err := exists(tx, "cinemas", s.CinemaID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return []apierror.FieldError{{
+			Field: "cinema_id", Code: validation.CodeUnknownReference, Message: "cinema_id does not exist",
+		}}, nil
+	}
+
+	// Kursi hanya dibuat saat insert, jadi total_seats studio yang sudah ada harus tetap sama
+	// dengan jumlah baris di tabel seats yang dipakai cek ketersediaan dan laporan okupansi
+	var totalSeats int
+	if s.ID != nil {
+		err = tx.QueryRow("SELECT total_seats FROM studios WHERE id = $1", *s.ID).Scan(&totalSeats)
+	} else {
+		err = tx.QueryRow("SELECT total_seats FROM studios WHERE cinema_id = $1 AND LOWER(name) = LOWER($2) ORDER BY id LIMIT 1",
+			s.CinemaID, s.Name).Scan(&totalSeats)
+	}
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if totalSeats != s.TotalSeats {
+		return []apierror.FieldError{{
+			Field: "total_seats", Code: validation.CodeImmutable,
+			Message: fmt.Sprintf("total_seats of an existing studio cannot be changed (currently %d)", totalSeats),
+		}}, nil
+	}
+	return nil, nil
+}
+
+// Studio baru langsung dibuatkan tepat total_seats kursi, 10 per baris (A1..A10, B1..) dengan
+// baris terakhir boleh kurang dari 10: baris terakhir COUPLE, baris sebelumnya PREMIUM, sisanya
+// REGULAR. Update studio tidak mengubah kursi yang sudah ada karena bisa sudah direferensikan tiket.
+func (studioImporter) upsert(tx *sql.Tx, actor audit.Actor, row interface{}) (bool, error) {
+	s := row.(*studioRow)
+	return upsert(tx, actor, "studios", s.ID,
+		"SELECT id FROM studios WHERE cinema_id = $1 AND LOWER(name) = LOWER($2) ORDER BY id LIMIT 1", []interface{}{s.CinemaID, s.Name},
+		"UPDATE studios SET cinema_id = $2, name = $3, studio_type = $5 WHERE id = $1",
+		`WITH studio AS (
+			INSERT INTO studios (id, cinema_id, name, total_seats, studio_type, created_at)
+			VALUES (COALESCE($1, nextval(pg_get_serial_sequence('studios', 'id'))), $2, $3, $4, $5, NOW())
+			RETURNING id, total_seats
+		)
+		INSERT INTO seats (studio_id, row_code, seat_number, category)
+		SELECT studio.id, chr(64 + r), n,
+			CASE
+				WHEN r = layout.row_count THEN 'COUPLE'
+				WHEN r = layout.row_count - 1 THEN 'PREMIUM'
+				ELSE 'REGULAR'
+			END
+		FROM studio
+		CROSS JOIN LATERAL (SELECT (studio.total_seats + 9) / 10 AS row_count) AS layout
+		CROSS JOIN LATERAL generate_series(1, layout.row_count) AS r
+		CROSS JOIN LATERAL generate_series(1, LEAST(10, studio.total_seats - (r - 1) * 10)) AS n`,
+		s.CinemaID, s.Name, s.TotalSeats, s.StudioType,
+	)
+}
+
+// scheduleRow satu baris import jadwal tayang. Waktu tanpa offset diinterpretasikan
+// di timezone cinema pemilik studio. Tanpa id, jadwal dicocokkan berdasarkan studio_id dan start_time.
+// Jadwal yang sudah ada hanya boleh diubah film dan harganya; studio, waktu dan status diubah lewat
+// API jadwal agar pemilik tiket, booking PENDING dan waitlist ikut ditangani.
+type scheduleRow struct {
+	ID        *int    `json:"id" validate:"gt=0"`
+	MovieID   int     `json:"movie_id" validate:"required,gt=0"`
+	StudioID  int     `json:"studio_id" validate:"required,gt=0"`
+	StartTime string  `json:"start_time" validate:"required,datetime"`
+	EndTime   string  `json:"end_time" validate:"required,datetime"`
+	Price     float64 `json:"price" validate:"required,gt=0"`
+	Status    string  `json:"status" validate:"required,oneof=SHOWING CANCELLED ENDED"`
+
+	start, end time.Time
+	existing   *existingSchedule
+	event      events.Event
+}
+
+// existingSchedule jadwal yang sudah ada di database dan cocok dengan baris import
+type existingSchedule struct {
+	id, movieID, studioID int
+	start, end            time.Time
+	status                string
+	price                 float64
+	deleted               bool
+}
+
+// emitted event domain yang dipublish setelah import di-commit
+func (s *scheduleRow) emitted() []events.Event {
+	if s.event == nil {
+		return nil
+	}
+	return []events.Event{s.event}
+}
+
+type scheduleImporter struct{}
+
+func (scheduleImporter) table() string { return "schedules" }
+
+func (scheduleImporter) parse(rec record) (interface{}, []apierror.FieldError) {
+	errs := []apierror.FieldError{}
+	row := &scheduleRow{
+		ID:        rec.optionalInt("id", &errs),
+		MovieID:   rec.int("movie_id", &errs),
+		StudioID:  rec.int("studio_id", &errs),
+		StartTime: rec["start_time"],
+		EndTime:   rec["end_time"],
+		Price:     rec.float("price", &errs),
+		Status:    rec["status"],
+	}
+	if row.Status == "" {
+		row.Status = models.ScheduleStatusShowing
+	}
+	return row, errs
+}
+
+func (scheduleImporter) check(tx *sql.Tx, row interface{}) ([]apierror.FieldError, error) {
+	s := row.(*scheduleRow)
+	errs := []apierror.FieldError{}
+
+	found, err := exists(tx, "movies", s.MovieID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		errs = append(errs, apierror.FieldError{
+			Field: "movie_id", Code: validation.CodeUnknownReference, Message: "movie_id does not exist",
+		})
+	}
+
+	var timezone string
+	err = tx.QueryRow(`
+		SELECT c.timezone FROM studios st JOIN cinemas c ON c.id = st.cinema_id WHERE st.id = $1
+	`, s.StudioID).Scan(&timezone)
+	if err == sql.ErrNoRows {
+		return append(errs, apierror.FieldError{
+			Field: "studio_id", Code: validation.CodeUnknownReference, Message: "studio_id does not exist",
+		}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loc := config.LoadLocation(timezone)
+	s.start, _ = validation.ParseDateTimeIn(s.StartTime, loc)
+	s.end, _ = validation.ParseDateTimeIn(s.EndTime, loc)
+	if !s.end.After(s.start) {
+		errs = append(errs, apierror.FieldError{
+			Field: "end_time", Code: validation.CodeInvalidRange, Message: "end_time must be after start_time",
+		})
+	}
+
+	if s.existing, err = findSchedule(tx, s); err != nil {
+		return nil, err
+	}
+	return append(errs, s.existing.check(s)...), nil
+}
+
+// checkBatch menolak jadwal baru yang bentrok dengan jadwal lain di studio yang sama, baik
+// di database maupun di file yang sama, dengan aturan yang sama seperti bulk create. Jadwal baru
+// berstatus CANCELLED tidak dicek. Studio dikunci agar import dan bulk create paralel tidak saling lolos.
+func (scheduleImporter) checkBatch(tx *sql.Tx, rows []interface{}) ([][]apierror.FieldError, error) {
+	slots := []overlap.Slot{}
+	slotRows := []int{}
+	studioIDs := []int{}
+	for i, row := range rows {
+		s := row.(*scheduleRow)
+		if s.existing != nil || s.Status == models.ScheduleStatusCancelled {
+			continue
+		}
+		slots = append(slots, overlap.Slot{StudioID: s.StudioID, Start: s.start, End: s.end})
+		slotRows = append(slotRows, i)
+		studioIDs = append(studioIDs, s.StudioID)
+	}
+	errs := make([][]apierror.FieldError, len(rows))
+	if len(slots) == 0 {
+		return errs, nil
+	}
+
+	if _, err := tx.Exec("SELECT id FROM studios WHERE id = ANY($1) ORDER BY id FOR UPDATE", pq.Array(studioIDs)); err != nil {
+		return nil, err
+	}
+	conflicts, err := overlap.Find(tx, slots)
+	if err != nil {
+		return nil, err
+	}
+	for _, conflict := range conflicts {
+		message := fmt.Sprintf("overlaps with existing schedule %d", conflict.ScheduleID)
+		if conflict.WithSlot >= 0 {
+			message = fmt.Sprintf("overlaps with row %d in this file", slotRows[conflict.WithSlot]+1)
+		}
+		row := slotRows[conflict.Slot]
+		errs[row] = append(errs[row], apierror.FieldError{
+			Field: "start_time", Code: validation.CodeScheduleOverlap, Message: message,
+		})
+	}
+	return errs, nil
+}
+
+// findSchedule mengunci jadwal yang cocok dengan baris (berdasarkan id, atau studio_id dan
+// start_time untuk jadwal yang belum dihapus); nil jika baris adalah jadwal baru
+func findSchedule(tx *sql.Tx, s *scheduleRow) (*existingSchedule, error) {
+	query := `SELECT id, movie_id, studio_id, start_time, end_time, COALESCE(status, ''), price, deleted_at IS NOT NULL FROM schedules `
+	var row *sql.Row
+	if s.ID != nil {
+		row = tx.QueryRow(query+"WHERE id = $1 FOR UPDATE", *s.ID)
+	} else {
+		row = tx.QueryRow(query+"WHERE studio_id = $1 AND start_time = $2 AND deleted_at IS NULL ORDER BY id LIMIT 1 FOR UPDATE",
+			s.StudioID, s.start)
+	}
+	e := &existingSchedule{}
+	err := row.Scan(&e.id, &e.movieID, &e.studioID, &e.start, &e.end, &e.status, &e.price, &e.deleted)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// check menolak jadwal yang sudah dihapus dan perubahan yang harus lewat API jadwal
+func (e *existingSchedule) check(s *scheduleRow) []apierror.FieldError {
+	if e == nil {
+		return nil
+	}
+	if e.deleted {
+		return []apierror.FieldError{{
+			Field: "id", Code: validation.CodeDeleted, Message: "schedule has been deleted; restore it through the API before importing",
+		}}
+	}
+	// Jadwal lama tanpa status dianggap SHOWING
+	status := e.status
+	if status == "" {
+		status = models.ScheduleStatusShowing
+	}
+	errs := []apierror.FieldError{}
+	for _, field := range []struct {
+		name    string
+		changed bool
+	}{
+		{"studio_id", s.StudioID != e.studioID},
+		{"start_time", !s.start.Equal(e.start)},
+		{"end_time", !s.end.Equal(e.end)},
+		{"status", s.Status != status},
+	} {
+		if field.changed {
+			errs = append(errs, apierror.FieldError{
+				Field: field.name, Code: validation.CodeImmutable,
+				Message: field.name + " of an existing schedule can only be changed through the schedules API",
+			})
+		}
+	}
+	return errs
+}
+
+// Jadwal baru di-insert beserta webhook schedule.created dan event ScheduleCreated setelah
+// commit, sama seperti create lewat API. Jadwal yang sudah ada hanya diubah jika film atau
+// harganya berbeda, dengan efek yang sama seperti update lewat API: version naik, pemilik tiket
+// diberi tahu jika filmnya berganti, webhook schedule.updated dan event ScheduleUpdated setelah commit.
+func (scheduleImporter) upsert(tx *sql.Tx, actor audit.Actor, row interface{}) (bool, error) {
+	s := row.(*scheduleRow)
+	e := s.existing
+	if e == nil {
+		var id int
+		err := tx.QueryRow(`INSERT INTO schedules (id, movie_id, studio_id, start_time, end_time, price, status, created_at)
+			VALUES (COALESCE($1, nextval(pg_get_serial_sequence('schedules', 'id'))), $2, $3, $4, $5, $6, $7, NOW())
+			RETURNING id`,
+			s.ID, s.MovieID, s.StudioID, s.start, s.end, s.Price, s.Status).Scan(&id)
+		if err != nil {
+			return true, err
+		}
+		after, err := audit.Snapshot(tx, "schedules", id)
+		if err != nil {
+			return true, err
+		}
+		if err := audit.Record(tx, actor, audit.ActionCreate, "schedules", &id, nil, after); err != nil {
+			return true, err
+		}
+		s.event = events.ScheduleCreated{ScheduleID: id}
+		return true, webhook.PublishSchedule(tx, models.WebhookEventScheduleCreated, id)
+	}
+	if s.MovieID == e.movieID && s.Price == e.price {
+		return false, nil
+	}
+
+	before, err := audit.Snapshot(tx, "schedules", e.id)
+	if err != nil {
+		return false, err
+	}
+	var version int
+	err = tx.QueryRow("UPDATE schedules SET movie_id = $2, price = $3, version = version + 1 WHERE id = $1 RETURNING version",
+		e.id, s.MovieID, s.Price).Scan(&version)
+	if err != nil {
+		return false, err
+	}
+	after, err := audit.Snapshot(tx, "schedules", e.id)
+	if err != nil {
+		return false, err
+	}
+	if err := audit.Record(tx, actor, audit.ActionUpdate, "schedules", &e.id, before, after); err != nil {
+		return false, err
+	}
+	if s.MovieID != e.movieID {
+		if err := notify.EnqueueScheduleHolders(tx, e.id, notify.TemplateScheduleChanged); err != nil {
+			return false, err
+		}
+	}
+	if err := webhook.PublishSchedule(tx, models.WebhookEventScheduleUpdated, e.id); err != nil {
+		return false, err
+	}
+	s.event = events.ScheduleUpdated{ScheduleID: e.id, Version: version}
+	return false, nil
+}
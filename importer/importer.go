@@ -0,0 +1,383 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/events"
+	"mkp/validation"
+	"strconv"
+	"strings"
+)
+
+// Format file import yang didukung
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Entity yang bisa di-import. Urutan import yang disarankan: cinemas, studios, movies, schedules.
+const (
+	EntityMovies    = "movies"
+	EntityCinemas   = "cinemas"
+	EntityStudios   = "studios"
+	EntitySchedules = "schedules"
+)
+
+// MaxRows batas jumlah baris dalam satu import
+const MaxRows = 5000
+
+var (
+	// ErrUnknownEntity entity tidak dikenal
+	ErrUnknownEntity = errors.New("unknown import entity")
+	// ErrUnknownFormat format file tidak dikenal
+	ErrUnknownFormat = errors.New("unknown import format, use csv or json")
+	// ErrInvalidFile isi file tidak bisa dibaca sebagai CSV/JSON
+	ErrInvalidFile = errors.New("invalid import file")
+	// ErrTooManyRows file melebihi MaxRows
+	ErrTooManyRows = fmt.Errorf("import file exceeds %d rows", MaxRows)
+)
+
+// RowError error validasi pada satu baris file. Row dimulai dari 1 (baris data pertama,
+// tanpa menghitung header CSV).
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Result ringkasan hasil import
+type Result struct {
+	Entity   string     `json:"entity"`
+	DryRun   bool       `json:"dry_run"`
+	Total    int        `json:"total"`
+	Inserted int        `json:"inserted"`
+	Updated  int        `json:"updated"`
+	Errors   []RowError `json:"errors"`
+}
+
+// record satu baris data mentah, key adalah nama kolom
+type record map[string]string
+
+// entityImporter parse+validasi satu baris dan menulisnya ke database
+type entityImporter interface {
+	// parse mengubah record menjadi row; error per field dikembalikan tanpa menghentikan import
+	parse(rec record) (row interface{}, errs []apierror.FieldError)
+	// check validasi yang membutuhkan database (misal foreign key)
+	check(tx *sql.Tx, row interface{}) ([]apierror.FieldError, error)
+	// upsert menulis row beserta audit log-nya, mengembalikan true jika row baru di-insert
+	upsert(tx *sql.Tx, actor audit.Actor, row interface{}) (bool, error)
+	// table nama tabel untuk sinkronisasi identity sequence
+	table() string
+}
+
+// batchChecker diimplementasikan importer yang butuh validasi antar baris (misal jadwal yang
+// bentrok di studio yang sama). Dipanggil setelah check semua baris lolos; hasilnya error per
+// baris dengan index yang sama dengan rows.
+type batchChecker interface {
+	checkBatch(tx *sql.Tx, rows []interface{}) ([][]apierror.FieldError, error)
+}
+
+var importers = map[string]entityImporter{
+	EntityMovies:    movieImporter{},
+	EntityCinemas:   cinemaImporter{},
+	EntityStudios:   studioImporter{},
+	EntitySchedules: scheduleImporter{},
+}
+
+// Entities daftar entity yang didukung
+func Entities() []string {
+	return []string{EntityCinemas, EntityStudios, EntityMovies, EntitySchedules}
+}
+
+// Import membaca file CSV/JSON lalu melakukan upsert semua baris dalam satu transaksi.
+// Jika ada baris yang tidak valid, tidak ada data yang ditulis dan Result.Errors berisi
+// semua error per baris. Setiap baris dan ringkasan import dicatat di audit log atas nama
+// actor dalam transaksi yang sama; gagal mencatat audit membatalkan import.
+// Dengan dryRun=true transaksi selalu di-rollback.
+func Import(db *sql.DB, actor audit.Actor, entity, format string, input io.Reader, dryRun bool) (Result, error) {
+	result := Result{Entity: entity, DryRun: dryRun, Errors: []RowError{}}
+
+	imp, ok := importers[entity]
+	if !ok {
+		return result, ErrUnknownEntity
+	}
+
+	records, err := readRecords(format, input)
+	if err != nil {
+		return result, err
+	}
+	result.Total = len(records)
+
+	rows := make([]interface{}, len(records))
+	for i, rec := range records {
+		row, errs := imp.parse(rec)
+		errs = mergeFieldErrors(errs, validation.Struct(row))
+		result.Errors = append(result.Errors, rowErrors(i+1, errs)...)
+		rows[i] = row
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	for i, row := range rows {
+		errs, err := imp.check(tx, row)
+		if err != nil {
+			return result, err
+		}
+		result.Errors = append(result.Errors, rowErrors(i+1, errs)...)
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+	if checker, ok := imp.(batchChecker); ok {
+		batchErrs, err := checker.checkBatch(tx, rows)
+		if err != nil {
+			return result, err
+		}
+		for i, errs := range batchErrs {
+			result.Errors = append(result.Errors, rowErrors(i+1, errs)...)
+		}
+		if len(result.Errors) > 0 {
+			return result, nil
+		}
+	}
+
+	for i, row := range rows {
+		inserted, err := imp.upsert(tx, actor, row)
+		if err != nil {
+			return result, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+
+	// ID eksplisit dari file tidak memajukan identity sequence, sinkronkan agar insert berikutnya tidak bentrok
+	syncQuery := fmt.Sprintf(
+		"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), GREATEST((SELECT COALESCE(MAX(id), 0) FROM %[1]s), 1))",
+		imp.table(),
+	)
+	if _, err := tx.Exec(syncQuery); err != nil {
+		return result, err
+	}
+
+	summary, _ := json.Marshal(result)
+	if err := audit.Record(tx, actor, audit.ActionImport, entity, nil, nil, summary); err != nil {
+		return result, err
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+	for _, row := range rows {
+		if emitter, ok := row.(interface{ emitted() []events.Event }); ok {
+			events.Publish(context.Background(), emitter.emitted()...)
+		}
+	}
+	return result, nil
+}
+
+// readRecords membaca seluruh baris dari CSV (dengan header) atau JSON array of objects
+func readRecords(format string, input io.Reader) ([]record, error) {
+	records := []record{}
+
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(input)
+		reader.TrimLeadingSpace = true
+		header, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: CSV header: %w", ErrInvalidFile, err)
+		}
+		for i := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+		}
+
+		for {
+			values, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: CSV: %w", ErrInvalidFile, err)
+			}
+			rec := record{}
+			for i, value := range values {
+				if i < len(header) {
+					rec[header[i]] = strings.TrimSpace(value)
+				}
+			}
+			records = append(records, rec)
+			if len(records) > MaxRows {
+				return nil, ErrTooManyRows
+			}
+		}
+
+	case FormatJSON:
+		decoder := json.NewDecoder(input)
+		decoder.UseNumber()
+		var items []map[string]interface{}
+		if err := decoder.Decode(&items); err != nil {
+			return nil, fmt.Errorf("%w: expected a JSON array of objects: %w", ErrInvalidFile, err)
+		}
+		if len(items) > MaxRows {
+			return nil, ErrTooManyRows
+		}
+		for _, item := range items {
+			rec := record{}
+			for key, value := range item {
+				if value != nil {
+					rec[strings.ToLower(key)] = strings.TrimSpace(fmt.Sprint(value))
+				}
+			}
+			records = append(records, rec)
+		}
+
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	return records, nil
+}
+
+// mergeFieldErrors menggabungkan error parse dan validasi, satu error per field (error parse diutamakan)
+func mergeFieldErrors(parseErrs, validateErrs []apierror.FieldError) []apierror.FieldError {
+	seen := map[string]bool{}
+	for _, err := range parseErrs {
+		seen[err.Field] = true
+	}
+	for _, err := range validateErrs {
+		if !seen[err.Field] {
+			parseErrs = append(parseErrs, err)
+		}
+	}
+	return parseErrs
+}
+
+func rowErrors(row int, errs []apierror.FieldError) []RowError {
+	rowErrs := []RowError{}
+	for _, err := range errs {
+		rowErrs = append(rowErrs, RowError{Row: row, Field: err.Field, Code: err.Code, Message: err.Message})
+	}
+	return rowErrs
+}
+
+// optionalInt membaca kolom integer opsional; kosong berarti nil
+func (rec record) optionalInt(field string, errs *[]apierror.FieldError) *int {
+	value := rec[field]
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		*errs = append(*errs, apierror.FieldError{Field: field, Code: validation.CodeOutOfRange, Message: field + " must be an integer"})
+		return nil
+	}
+	return &n
+}
+
+// int membaca kolom integer; kosong berarti 0 sehingga ditangkap rule required
+func (rec record) int(field string, errs *[]apierror.FieldError) int {
+	if n := rec.optionalInt(field, errs); n != nil {
+		return *n
+	}
+	return 0
+}
+
+// float membaca kolom angka desimal; kosong berarti 0
+func (rec record) float(field string, errs *[]apierror.FieldError) float64 {
+	value := rec[field]
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		*errs = append(*errs, apierror.FieldError{Field: field, Code: validation.CodeOutOfRange, Message: field + " must be a number"})
+		return 0
+	}
+	return f
+}
+
+// optionalString membaca kolom teks opsional; kosong berarti nil
+func (rec record) optionalString(field string) *string {
+	if value := rec[field]; value != "" {
+		return &value
+	}
+	return nil
+}
+
+// exists helper untuk cek foreign key
+func exists(tx *sql.Tx, table string, id int) (bool, error) {
+	var found bool
+	err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM "+table+" WHERE id = $1)", id).Scan(&found)
+	return found, err
+}
+
+// upsert update baris yang cocok (berdasarkan id, atau natural key via findQuery jika id kosong)
+// atau insert baris baru, lalu mencatat snapshot sebelum dan sesudahnya di audit log dalam
+// transaksi yang sama. updateQuery dan insertQuery memakai $1 sebagai id; baris baru tanpa id
+// diberi id dari sequence lebih dulu agar bisa dicatat.
+func upsert(tx *sql.Tx, actor audit.Actor, table string, id *int, findQuery string, findArgs []interface{}, updateQuery, insertQuery string, args ...interface{}) (bool, error) {
+	var existingID int
+	var err error
+	if id != nil {
+		err = tx.QueryRow("SELECT id FROM "+table+" WHERE id = $1", *id).Scan(&existingID)
+	} else {
+		err = tx.QueryRow(findQuery, findArgs...).Scan(&existingID)
+	}
+
+	if err == sql.ErrNoRows {
+		newID := 0
+		if id != nil {
+			newID = *id
+		} else if err := tx.QueryRow("SELECT nextval(pg_get_serial_sequence($1, 'id'))", table).Scan(&newID); err != nil {
+			return true, err
+		}
+		if _, err := tx.Exec(insertQuery, append([]interface{}{newID}, args...)...); err != nil {
+			return true, err
+		}
+		after, err := audit.Snapshot(tx, table, newID)
+		if err != nil {
+			return true, err
+		}
+		return true, audit.Record(tx, actor, audit.ActionCreate, table, &newID, nil, after)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	before, err := audit.Snapshot(tx, table, existingID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(updateQuery, append([]interface{}{existingID}, args...)...); err != nil {
+		return false, err
+	}
+	after, err := audit.Snapshot(tx, table, existingID)
+	if err != nil {
+		return false, err
+	}
+	return false, audit.Record(tx, actor, audit.ActionUpdate, table, &existingID, before, after)
+}
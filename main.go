@@ -1,64 +1,345 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"mkp/apierror"
+	"mkp/audit"
 	"mkp/config"
 	"mkp/handlers"
 	"mkp/middleware"
+	"mkp/notify"
+	"mkp/openapi"
+	"mkp/seatstream"
+	"mkp/waitlist"
+	"mkp/webhook"
 	"net/http"
+	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // timezone cinema tetap bisa di-load meskipun OS tidak punya tzdata
 )
 
+// usage daftar subcommand binary
+const usage = `Usage: mkp <command> [flags]
+
+Commands:
+  serve                     jalankan HTTP server (default jika tanpa command)
+  migrate                   jalankan migration database yang belum jalan
+  seed                      isi data contoh, -generate untuk dataset besar ber-seed
+  import                    import CSV/JSON movies, cinemas, studios, schedules
+  user create               buat user (misal admin pertama)
+  user promote              ubah role user
+  user reset-password       ganti password user
+  schedule cancel           batalkan semua jadwal sebuah studio dalam rentang tanggal
+  report sales              laporan penjualan
+  openapi                   tulis spesifikasi OpenAPI
+
+Jalankan "mkp <command> -h" untuk flag tiap command.
+`
+
+// cliActor actor audit untuk perubahan lewat subcommand
+var cliActor = audit.Actor{RequestID: "cli"}
+
 func main() {
+	// Subcommand CLI, misal: go run . import -entity schedules -file jadwal.csv.
+	// Semua subcommand memakai config dan package yang sama dengan HTTP server.
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		os.Exit(runServe(args))
+	case "migrate":
+		os.Exit(runMigrate(args))
+	case "seed":
+		os.Exit(runSeed(args))
+	case "import":
+		os.Exit(runImport(args))
+	case "user":
+		os.Exit(runUser(args))
+	case "schedule":
+		os.Exit(runSchedule(args))
+	case "report":
+		os.Exit(runReport(args))
+	case "openapi":
+		os.Exit(runOpenAPI(args))
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+// runServe menjalankan HTTP server beserta worker background
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "alamat listen HTTP")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
 	// Inisialisasi database
 	config.InitDB()
 	defer config.CloseDB()
 
 	// Setup routes
 	setupRoutes()
+	for _, problem := range openapi.Check(routePatterns) {
+		log.Println("openapi:", problem)
+	}
+
+	// Worker pelepasan hold booking dan penawaran waitlist
+	go waitlist.Run(config.DB, time.Minute)
+
+	// Pengiriman notifikasi dari outbox (SMTP jika SMTP_ADDR diisi, selain itu file)
+	go notify.Dispatcher{DB: config.DB, Notifier: notify.FromEnv()}.Run(10 * time.Second)
+
+	// Pengiriman webhook ke partner
+	go webhook.Dispatcher{DB: config.DB}.Run(10 * time.Second)
+
+	// LISTEN perubahan kursi untuk stream SSE, termasuk dari instance API lain
+	go seatstream.Listen(config.ConnString())
 
 	// Start server
-	port := ":8080"
-	log.Printf("Server running on http://localhost%s", port)
-	log.Fatal(http.ListenAndServe(port, nil))
+	log.Printf("Server running on http://localhost%s", *addr)
+	log.Println(http.ListenAndServe(*addr, middleware.RequestID(http.DefaultServeMux)))
+	return 1
+}
+
+// routePatterns pattern yang didaftarkan setupRoutes, dicocokkan dengan spesifikasi OpenAPI
+var routePatterns []string
+
+// handleFunc mendaftarkan route ke DefaultServeMux dan mencatat pattern-nya
+func handleFunc(pattern string, handler http.HandlerFunc) {
+	routePatterns = append(routePatterns, pattern)
+	http.HandleFunc(pattern, handler)
 }
 
 func setupRoutes() {
 	// Public routes (tidak perlu authentication)
-	http.HandleFunc("/api/register", handlers.Register)
-	http.HandleFunc("/api/login", handlers.Login)
+	handleFunc("/api/register", handlers.Register)
+	handleFunc("/api/login", handlers.Login)
+
+	// Public catalogue routes (authentication opsional)
+	handleFunc("/api/movies", middleware.OptionalAuthMiddleware(handlers.GetMovies))
+	handleFunc("/api/movies/", middleware.OptionalAuthMiddleware(handlers.GetMovieByID))
+	handleFunc("/api/movies/search", middleware.OptionalAuthMiddleware(handlers.SearchMovies))
+	handleFunc("/api/cinemas", middleware.OptionalAuthMiddleware(handlers.GetCinemas))
+	handleFunc("/api/cinemas/", func(w http.ResponseWriter, r *http.Request) {
+		// GET feed iCalendar jadwal tayang cinema
+		if strings.HasSuffix(r.URL.Path, "/schedule.ics") {
+			handlers.GetCinemaScheduleICS(w, r)
+			return
+		}
+		middleware.OptionalAuthMiddleware(handlers.GetCinemaByID)(w, r)
+	})
+	handleFunc("/api/showtimes", middleware.OptionalAuthMiddleware(handlers.GetShowtimes))
 
 	// Protected routes (perlu authentication)
 	// GET semua jadwal
-	http.HandleFunc("/api/schedules", middleware.AuthMiddleware(handlers.GetSchedules))
+	handleFunc("/api/schedules", middleware.AuthMiddleware(handlers.GetSchedules))
 
-	// POST create jadwal baru
-	http.HandleFunc("/api/schedules/create", middleware.AuthMiddleware(handlers.CreateSchedule))
+	// POST create jadwal baru (admin, mendukung header Idempotency-Key)
+	handleFunc("/api/schedules/create", middleware.AdminMiddleware(middleware.Idempotent(handlers.CreateSchedule)))
 
-	// GET, PUT, DELETE jadwal by ID - menggunakan pattern yang sama
-	http.HandleFunc("/api/schedules/", func(w http.ResponseWriter, r *http.Request) {
+	// POST bulk create jadwal (admin, mendukung dry run)
+	handleFunc("/api/schedules/bulk", middleware.AdminMiddleware(handlers.CreateSchedulesBulk))
+
+	// GET, PUT, PATCH, DELETE jadwal by ID - menggunakan pattern yang sama; perubahan hanya untuk admin
+	handleFunc("/api/schedules/", func(w http.ResponseWriter, r *http.Request) {
 		// Pastikan ada ID di path
 		if r.URL.Path == "/api/schedules/" || r.URL.Path == "/api/schedules" {
 			middleware.AuthMiddleware(handlers.GetSchedules)(w, r)
 			return
 		}
 
+		// GET preview harga tiket untuk kursi tertentu
+		if strings.HasSuffix(r.URL.Path, "/price-preview") {
+			middleware.AuthMiddleware(handlers.PreviewSchedulePrice)(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/restore") {
+			middleware.AdminMiddleware(handlers.RestoreSchedule)(w, r)
+			return
+		}
+		// GET stream SSE perubahan status kursi; publik seperti showtimes karena EventSource
+		// di browser tidak bisa mengirim header Authorization
+		if strings.HasSuffix(r.URL.Path, "/seats/stream") {
+			middleware.OptionalAuthMiddleware(handlers.StreamScheduleSeats)(w, r)
+			return
+		}
+
 		// Route berdasarkan HTTP method
 		switch r.Method {
 		case http.MethodGet:
 			middleware.AuthMiddleware(handlers.GetScheduleByID)(w, r)
 		case http.MethodPut:
-			middleware.AuthMiddleware(handlers.UpdateSchedule)(w, r)
+			middleware.AdminMiddleware(handlers.UpdateSchedule)(w, r)
+		case http.MethodPatch:
+			middleware.AdminMiddleware(handlers.PatchSchedule)(w, r)
 		case http.MethodDelete:
-			middleware.AuthMiddleware(handlers.DeleteSchedule)(w, r)
+			middleware.AdminMiddleware(handlers.DeleteSchedule)(w, r)
+		default:
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// Booking tiket (perlu authentication); POST mendukung header Idempotency-Key
+	handleFunc("/api/bookings", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			middleware.AuthMiddleware(handlers.GetMyBookings)(w, r)
+		case http.MethodPost:
+			middleware.AuthMiddleware(middleware.Idempotent(handlers.CreateBooking))(w, r)
+		default:
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		}
+	})
+	// GET detail booking, POST /api/bookings/{id}/pay untuk membayar, POST /api/bookings/{id}/cancel untuk membatalkan
+	handleFunc("/api/bookings/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/pay") {
+			middleware.AuthMiddleware(middleware.Idempotent(handlers.PayBooking))(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/cancel") {
+			middleware.AuthMiddleware(handlers.CancelBooking)(w, r)
+			return
+		}
+		middleware.AuthMiddleware(handlers.GetBookingByID)(w, r)
+	})
+
+	// Waitlist jadwal penuh: GET entry milik user, POST masuk waitlist, DELETE /api/waitlist/{id} keluar
+	handleFunc("/api/waitlist", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			middleware.AuthMiddleware(handlers.GetMyWaitlist)(w, r)
+		case http.MethodPost:
+			middleware.AuthMiddleware(handlers.JoinWaitlist)(w, r)
 		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
 		}
 	})
+	handleFunc("/api/waitlist/", middleware.AuthMiddleware(handlers.LeaveWaitlist))
+
+	// GET URL feed iCalendar tiket (token dibuat jika belum ada), POST ganti token
+	handleFunc("/api/calendar/feed", middleware.AuthMiddleware(handlers.GetCalendarFeed))
+
+	// GET feed iCalendar tiket privat; autentikasi lewat token di URL
+	handleFunc("/api/calendar/", handlers.GetTicketCalendarICS)
+
+	// Admin routes (perlu role ADMIN)
+	// GET semua pricing rule, POST pricing rule baru
+	handleFunc("/api/admin/pricing-rules", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			middleware.AdminMiddleware(handlers.GetPricingRules)(w, r)
+		case http.MethodPost:
+			middleware.AdminMiddleware(handlers.CreatePricingRule)(w, r)
+		default:
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// GET, PUT, DELETE pricing rule by ID
+	handleFunc("/api/admin/pricing-rules/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			middleware.AdminMiddleware(handlers.GetPricingRuleByID)(w, r)
+		case http.MethodPut:
+			middleware.AdminMiddleware(handlers.UpdatePricingRule)(w, r)
+		case http.MethodDelete:
+			middleware.AdminMiddleware(handlers.DeletePricingRule)(w, r)
+		default:
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// GET semua promo, POST promo baru
+	handleFunc("/api/admin/promotions", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			middleware.AdminMiddleware(handlers.GetPromotions)(w, r)
+		case http.MethodPost:
+			middleware.AdminMiddleware(handlers.CreatePromotion)(w, r)
+		default:
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// GET, PUT, DELETE promo by ID
+	handleFunc("/api/admin/promotions/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			middleware.AdminMiddleware(handlers.GetPromotionByID)(w, r)
+		case http.MethodPut:
+			middleware.AdminMiddleware(handlers.UpdatePromotion)(w, r)
+		case http.MethodDelete:
+			middleware.AdminMiddleware(handlers.DeletePromotion)(w, r)
+		default:
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// GET semua webhook subscription, POST subscription baru
+	handleFunc("/api/admin/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			middleware.AdminMiddleware(handlers.GetWebhooks)(w, r)
+		case http.MethodPost:
+			middleware.AdminMiddleware(handlers.CreateWebhook)(w, r)
+		default:
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// GET, PUT, DELETE webhook subscription by ID
+	handleFunc("/api/admin/webhooks/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			middleware.AdminMiddleware(handlers.GetWebhookByID)(w, r)
+		case http.MethodPut:
+			middleware.AdminMiddleware(handlers.UpdateWebhook)(w, r)
+		case http.MethodDelete:
+			middleware.AdminMiddleware(handlers.DeleteWebhook)(w, r)
+		default:
+			apierror.Respond(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	// GET riwayat delivery webhook
+	handleFunc("/api/admin/webhook-deliveries", middleware.AdminMiddleware(handlers.GetWebhookDeliveries))
+
+	// GET delivery by ID, POST /redeliver kirim ulang
+	handleFunc("/api/admin/webhook-deliveries/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/redeliver") {
+			middleware.AdminMiddleware(handlers.RedeliverWebhook)(w, r)
+			return
+		}
+		middleware.AdminMiddleware(handlers.GetWebhookDeliveryByID)(w, r)
+	})
+
+	// GET audit log perubahan data
+	handleFunc("/api/admin/audit-log", middleware.AdminMiddleware(handlers.GetAuditLog))
+
+	// GET laporan revenue, occupancy, top-movies, refunds (JSON atau CSV)
+	handleFunc("/api/admin/reports/", middleware.AdminMiddleware(handlers.GetReport))
+
+	// POST import CSV/JSON movies, cinemas, studios, schedules
+	handleFunc("/api/admin/import/", middleware.AdminMiddleware(handlers.ImportData))
+
+	// Spesifikasi OpenAPI dan halaman referensinya
+	handleFunc("/openapi.json", handlers.GetOpenAPISpec)
+	handleFunc("/docs", handlers.GetAPIDocs)
 
 	// Root endpoint
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	handleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/" {
-			http.NotFound(w, r)
+			apierror.Respond(w, r, http.StatusNotFound, apierror.CodeNotFound, "Resource not found")
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"mkp/openapi"
+	"testing"
+)
+
+// TestRoutesDocumented memastikan setiap route yang didaftarkan punya operasi OpenAPI dan sebaliknya
+func TestRoutesDocumented(t *testing.T) {
+	setupRoutes()
+	for _, problem := range openapi.Check(routePatterns) {
+		t.Error(problem)
+	}
+}
@@ -3,6 +3,8 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"mkp/apierror"
+	"mkp/models"
 	"net/http"
 	"strings"
 
@@ -15,6 +17,7 @@ var JWTSecret = []byte("your-secret-key-change-this-in-production")
 type Claims struct {
 	UserID int    `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -23,13 +26,13 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			respondWithError(w, http.StatusUnauthorized, "Authorization header required")
+			apierror.Respond(w, r, http.StatusUnauthorized, apierror.CodeAuthRequired, "Authorization header required")
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			respondWithError(w, http.StatusUnauthorized, "Invalid authorization header format")
+			apierror.Respond(w, r, http.StatusUnauthorized, apierror.CodeInvalidAuthHeader, "Invalid authorization header format")
 			return
 		}
 
@@ -45,7 +48,7 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		})
 
 		if err != nil {
-			respondWithError(w, http.StatusUnauthorized, "Invalid or expired token")
+			apierror.Respond(w, r, http.StatusUnauthorized, apierror.CodeInvalidToken, "Invalid or expired token")
 			return
 		}
 
@@ -54,19 +57,49 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			// Simpan user info ke context untuk digunakan di handler
 			ctx := context.WithValue(r.Context(), "userID", claims.UserID)
 			ctx = context.WithValue(ctx, "email", claims.Email)
+			ctx = context.WithValue(ctx, "role", claims.Role)
 
 			// Lanjutkan ke handler berikutnya dengan context yang sudah berisi user info
 			next.ServeHTTP(w, r.WithContext(ctx))
 		} else {
-			respondWithError(w, http.StatusUnauthorized, "Invalid token claims")
+			apierror.Respond(w, r, http.StatusUnauthorized, apierror.CodeInvalidToken, "Invalid token claims")
 			return
 		}
 	}
 }
 
-// Helper function untuk mengirim error response
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	fmt.Fprintf(w, `{"error": "%s"}`, message)
+// OptionalAuthMiddleware middleware untuk route publik. Request tanpa header Authorization
+// tetap diteruskan sebagai anonim; jika token dikirim, token tetap divalidasi dan user
+// disimpan ke context seperti AuthMiddleware.
+func OptionalAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		AuthMiddleware(next)(w, r)
+	}
+}
+
+// AdminMiddleware middleware untuk route yang hanya boleh diakses admin
+func AdminMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if !IsAdmin(r.Context()) {
+			apierror.Respond(w, r, http.StatusForbidden, apierror.CodeForbidden, "Admin access required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserID mengambil ID user yang sedang login dari context request
+func UserID(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value("userID").(int)
+	return userID, ok
+}
+
+// IsAdmin mengecek apakah user yang sedang login memiliki role ADMIN
+func IsAdmin(ctx context.Context) bool {
+	role, _ := ctx.Value("role").(string)
+	return role == models.RoleAdmin
 }
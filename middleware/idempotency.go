@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"mkp/apierror"
+	"mkp/config"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader header yang dikirim client untuk request POST yang boleh di-retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyReplayedHeader ditambahkan pada response yang dikirim ulang dari penyimpanan
+const IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength panjang maksimum Idempotency-Key
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentBodyBytes batas body request yang di-hash
+const maxIdempotentBodyBytes = 1 << 20
+
+// idempotencyLease lama klaim key yang belum punya response dianggap masih diproses; setelah itu
+// (misal server mati di tengah request) key boleh diklaim ulang
+const idempotencyLease = time.Minute
+
+// Idempotent middleware untuk Idempotency-Key, dipasang di dalam AuthMiddleware.
+// Response pertama (selain 5xx) disimpan per user+key beserta hash request dan dikirim ulang
+// untuk retry dengan key yang sama. Key yang dipakai ulang dengan body berbeda ditolak 422;
+// retry saat request pertama belum selesai ditolak 409, kecuali klaimnya sudah lebih lama dari
+// idempotencyLease. Key berlaku 24 jam.
+// Tanpa header, request diteruskan apa adanya.
+func Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			apierror.Respond(w, r, http.StatusBadRequest, apierror.CodeInvalidIdempotencyKey,
+				"Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		userID, ok := UserID(r.Context())
+		if !ok {
+			apierror.Respond(w, r, http.StatusUnauthorized, apierror.CodeAuthRequired, "Authorization header required")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+		if err != nil {
+			apierror.Respond(w, r, http.StatusRequestEntityTooLarge, apierror.CodeInvalidRequestBody, "Request body is too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		// Klaim key; baris yang sudah kedaluwarsa atau klaim yang lease-nya habis ditimpa seolah-olah belum ada
+		var claimed bool
+		err = config.DB.QueryRow(`
+			INSERT INTO idempotency_keys (user_id, key, request_hash, created_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (user_id, key) DO UPDATE
+				SET request_hash = EXCLUDED.request_hash, response_status = NULL, response_content_type = NULL,
+					response_body = NULL, created_at = NOW(), completed_at = NULL
+				WHERE idempotency_keys.created_at < NOW() - INTERVAL '24 hours'
+					OR (idempotency_keys.response_status IS NULL AND idempotency_keys.created_at < NOW() - make_interval(secs => $4))
+			RETURNING true
+		`, userID, key, requestHash, idempotencyLease.Seconds()).Scan(&claimed)
+		if err == sql.ErrNoRows {
+			replayIdempotent(w, r, userID, key, requestHash)
+			return
+		}
+		if err != nil {
+			apierror.Respond(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+			return
+		}
+
+		// Klaim dilepas jika handler panic agar retry tidak tertahan 409
+		completed := false
+		defer func() {
+			if !completed {
+				config.DB.Exec("DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2", userID, key)
+			}
+		}()
+
+		recorder := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+		next(recorder, r)
+		completed = true
+
+		// Error server tidak disimpan agar client bisa mencoba lagi dengan key yang sama
+		if recorder.status >= http.StatusInternalServerError {
+			config.DB.Exec("DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2", userID, key)
+		} else {
+			config.DB.Exec(`
+				UPDATE idempotency_keys
+				SET response_status = $3, response_content_type = $4, response_body = $5, completed_at = NOW()
+				WHERE user_id = $1 AND key = $2
+			`, userID, key, recorder.status, recorder.header.Get("Content-Type"), recorder.body.Bytes())
+		}
+
+		for name, values := range recorder.header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(recorder.status)
+		w.Write(recorder.body.Bytes())
+	}
+}
+
+// replayIdempotent mengirim ulang response tersimpan untuk key yang sudah pernah dipakai
+func replayIdempotent(w http.ResponseWriter, r *http.Request, userID int, key, requestHash string) {
+	var storedHash string
+	var status sql.NullInt64
+	var contentType sql.NullString
+	var body []byte
+	err := config.DB.QueryRow(`
+		SELECT request_hash, response_status, response_content_type, response_body
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2
+	`, userID, key).Scan(&storedHash, &status, &contentType, &body)
+	if err != nil {
+		apierror.Respond(w, r, http.StatusInternalServerError, apierror.CodeDatabaseError, "Database error")
+		return
+	}
+
+	if storedHash != requestHash {
+		apierror.Respond(w, r, http.StatusUnprocessableEntity, apierror.CodeIdempotencyKeyReused,
+			"Idempotency-Key was already used with a different request")
+		return
+	}
+	if !status.Valid {
+		w.Header().Set("Retry-After", "1")
+		apierror.Respond(w, r, http.StatusConflict, apierror.CodeIdempotencyKeyInProgress,
+			"A request with this Idempotency-Key is still being processed")
+		return
+	}
+
+	if contentType.String != "" {
+		w.Header().Set("Content-Type", contentType.String)
+	}
+	w.Header().Set(IdempotencyReplayedHeader, "true")
+	w.WriteHeader(int(status.Int64))
+	w.Write(body)
+}
+
+// responseRecorder menampung response handler agar bisa disimpan sebelum dikirim
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	return rec.body.Write(b)
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"mkp/apierror"
+	"net/http"
+)
+
+// RequestID middleware untuk memberi setiap request ID unik.
+// Jika client sudah mengirim X-Request-ID, nilai tersebut dipakai ulang.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(apierror.RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+			r.Header.Set(apierror.RequestIDHeader, id)
+		}
+
+		w.Header().Set(apierror.RequestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newRequestID membuat ID acak 16 byte dalam format hex
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"mkp/config"
+	"mkp/migrations"
+	"os"
+)
+
+// schemaSQL skema lengkap, dipakai migrate untuk database yang masih kosong
+//
+//go:embed db_design/schema.sql
+var schemaSQL string
+
+// runMigrate menjalankan migration yang belum jalan. Database kosong langsung dibuat dari
+// db_design/schema.sql. Database lama yang dibuat manual ditandai dulu dengan -baseline
+// <versi terakhir yang sudah diterapkan> atau -baseline all.
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	status := fs.Bool("status", false, "tampilkan status migration tanpa menjalankan")
+	baseline := fs.String("baseline", "", "tandai migration sampai versi ini (atau all) sudah jalan")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	config.InitDB()
+	defer config.CloseDB()
+
+	if *status {
+		states, err := migrations.Status(config.DB)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "migrate:", err)
+			return 1
+		}
+		for _, state := range states {
+			applied := "pending"
+			if state.AppliedAt != nil {
+				applied = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%s_%s\t%s\n", state.Version, state.Name, applied)
+		}
+		return 0
+	}
+
+	if *baseline != "" {
+		marked, err := migrations.Baseline(config.DB, *baseline)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "migrate:", err)
+			return 1
+		}
+		fmt.Printf("Marked %d migrations as applied\n", len(marked))
+	}
+
+	ran, err := migrations.Migrate(config.DB, schemaSQL)
+	for _, m := range ran {
+		fmt.Printf("Applied %s_%s\n", m.Version, m.Name)
+	}
+	if errors.Is(err, migrations.ErrNoHistory) {
+		fmt.Fprintln(os.Stderr, "migrate:", err, "(e.g. -baseline all if the schema matches db_design/schema.sql)")
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+	if len(ran) == 0 {
+		fmt.Println("Database is up to date")
+	}
+	return 0
+}
@@ -0,0 +1,22 @@
+-- Timezone per cinema dan showtime dalam timestamptz.
+-- Data lama (timestamp tanpa timezone) diinterpretasikan sebagai waktu lokal cinema.
+
+ALTER TABLE "cinemas" ADD COLUMN IF NOT EXISTS "timezone" varchar NOT NULL DEFAULT 'Asia/Jakarta';
+COMMENT ON COLUMN "cinemas"."timezone" IS 'Timezone IANA, misal: Asia/Jakarta (WIB), Asia/Makassar (WITA)';
+
+ALTER TABLE "schedules" ADD COLUMN "start_time_tz" timestamptz;
+ALTER TABLE "schedules" ADD COLUMN "end_time_tz" timestamptz;
+
+UPDATE "schedules" s
+SET "start_time_tz" = s."start_time" AT TIME ZONE c."timezone",
+    "end_time_tz" = s."end_time" AT TIME ZONE c."timezone"
+FROM "studios" st
+JOIN "cinemas" c ON st."cinema_id" = c."id"
+WHERE s."studio_id" = st."id";
+
+ALTER TABLE "schedules" DROP COLUMN "start_time";
+ALTER TABLE "schedules" DROP COLUMN "end_time";
+ALTER TABLE "schedules" RENAME COLUMN "start_time_tz" TO "start_time";
+ALTER TABLE "schedules" RENAME COLUMN "end_time_tz" TO "end_time";
+ALTER TABLE "schedules" ALTER COLUMN "start_time" SET NOT NULL;
+ALTER TABLE "schedules" ALTER COLUMN "end_time" SET NOT NULL;
@@ -0,0 +1,37 @@
+-- Role user (admin), tipe studio, kategori kursi, hari libur dan aturan harga dinamis.
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" varchar NOT NULL DEFAULT 'CUSTOMER';
+COMMENT ON COLUMN "users"."role" IS 'CUSTOMER, ADMIN';
+
+ALTER TABLE "studios" ADD COLUMN IF NOT EXISTS "studio_type" varchar NOT NULL DEFAULT 'REGULAR';
+COMMENT ON COLUMN "studios"."studio_type" IS 'REGULAR, IMAX, PREMIERE';
+UPDATE "studios" SET "studio_type" = 'IMAX' WHERE "name" ILIKE '%IMAX%';
+
+ALTER TABLE "seats" ADD COLUMN IF NOT EXISTS "category" varchar NOT NULL DEFAULT 'REGULAR';
+COMMENT ON COLUMN "seats"."category" IS 'REGULAR, PREMIUM, COUPLE';
+
+CREATE TABLE IF NOT EXISTS "holidays" (
+  "holiday_date" date PRIMARY KEY,
+  "name" varchar NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "pricing_rules" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "days_of_week" integer[],
+  "time_from" time,
+  "time_to" time,
+  "studio_type" varchar,
+  "seat_category" varchar,
+  "holiday_only" boolean NOT NULL DEFAULT false,
+  "adjustment_type" varchar NOT NULL,
+  "adjustment_value" decimal(10,2) NOT NULL,
+  "priority" integer NOT NULL DEFAULT 0,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+COMMENT ON COLUMN "pricing_rules"."days_of_week" IS '0 = Minggu ... 6 = Sabtu, NULL = semua hari';
+COMMENT ON COLUMN "pricing_rules"."time_from" IS 'Jam mulai tayang (waktu lokal cinema), misal: 17:00 untuk prime time';
+COMMENT ON COLUMN "pricing_rules"."adjustment_type" IS 'PERCENTAGE, FIXED';
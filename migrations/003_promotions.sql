@@ -0,0 +1,45 @@
+-- Promo code / voucher diskon dan pencatatan pemakaiannya per transaksi.
+
+CREATE TABLE IF NOT EXISTS "promotions" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "code" varchar UNIQUE NOT NULL,
+  "description" text,
+  "discount_type" varchar NOT NULL,
+  "discount_value" decimal(10,2) NOT NULL,
+  "max_discount" decimal(10,2),
+  "min_spend" decimal(10,2) NOT NULL DEFAULT 0,
+  "valid_from" timestamptz NOT NULL,
+  "valid_until" timestamptz NOT NULL,
+  "usage_limit" integer,
+  "usage_limit_per_user" integer,
+  "used_count" integer NOT NULL DEFAULT 0,
+  "movie_ids" integer[],
+  "cinema_ids" integer[],
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS "promotion_usages" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "promotion_id" integer NOT NULL REFERENCES "promotions" ("id"),
+  "user_id" integer NOT NULL REFERENCES "users" ("id"),
+  "transaction_id" integer NOT NULL REFERENCES "transactions" ("id"),
+  "discount_amount" decimal(10,2) NOT NULL,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE INDEX IF NOT EXISTS "promotion_usages_promotion_user_idx" ON "promotion_usages" ("promotion_id", "user_id");
+
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "subtotal_amount" decimal(10,2);
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "discount_amount" decimal(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "promotion_id" integer REFERENCES "promotions" ("id");
+UPDATE "transactions" SET "subtotal_amount" = "total_amount" WHERE "subtotal_amount" IS NULL;
+
+COMMENT ON COLUMN "promotions"."code" IS 'Kode voucher, selalu huruf besar, misal: NONTONHEMAT';
+COMMENT ON COLUMN "promotions"."discount_type" IS 'PERCENTAGE, FIXED';
+COMMENT ON COLUMN "promotions"."max_discount" IS 'Batas potongan untuk diskon persentase, NULL = tanpa batas';
+COMMENT ON COLUMN "promotions"."usage_limit" IS 'Batas pemakaian global, NULL = tanpa batas';
+COMMENT ON COLUMN "promotions"."movie_ids" IS 'Hanya berlaku untuk film ini, NULL = semua film';
+COMMENT ON COLUMN "promotions"."cinema_ids" IS 'Hanya berlaku di cinema ini, NULL = semua cinema';
+COMMENT ON COLUMN "transactions"."total_amount" IS 'Total setelah diskon';
@@ -0,0 +1,17 @@
+-- Full-text search dan trigram similarity untuk pencarian film.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE "movies" ADD COLUMN IF NOT EXISTS "search_vector" tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce("title", '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce("description", '')), 'B')
+  ) STORED;
+
+CREATE INDEX IF NOT EXISTS "movies_search_vector_idx" ON "movies" USING gin ("search_vector");
+CREATE INDEX IF NOT EXISTS "movies_title_trgm_idx" ON "movies" USING gin (lower("title") gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS "movies_title_compact_trgm_idx" ON "movies"
+  USING gin (regexp_replace(lower("title"), '[^a-z0-9]+', '', 'g') gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS "movies_description_trgm_idx" ON "movies" USING gin (lower("description") gin_trgm_ops);
+
+COMMENT ON COLUMN "movies"."search_vector" IS 'Dihitung otomatis dari title (bobot A) dan description (bobot B)';
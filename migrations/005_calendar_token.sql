@@ -0,0 +1,5 @@
+-- Token rahasia per user untuk URL feed iCalendar tiket (dipakai aplikasi kalender tanpa header Authorization).
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "calendar_token" varchar UNIQUE;
+
+COMMENT ON COLUMN "users"."calendar_token" IS 'Token feed iCalendar tiket, NULL = belum pernah dibuat';
@@ -0,0 +1,22 @@
+-- Audit log semua perubahan data: siapa, kapan, apa, beserta snapshot sebelum/sesudah.
+
+CREATE TABLE IF NOT EXISTS "audit_log" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "actor_id" integer REFERENCES "users" ("id"),
+  "action" varchar NOT NULL,
+  "entity_type" varchar NOT NULL,
+  "entity_id" integer,
+  "before" jsonb,
+  "after" jsonb,
+  "changes" jsonb NOT NULL DEFAULT '{}',
+  "request_id" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX IF NOT EXISTS "audit_log_entity_idx" ON "audit_log" ("entity_type", "entity_id");
+CREATE INDEX IF NOT EXISTS "audit_log_actor_idx" ON "audit_log" ("actor_id");
+CREATE INDEX IF NOT EXISTS "audit_log_created_at_idx" ON "audit_log" ("created_at");
+
+COMMENT ON COLUMN "audit_log"."action" IS 'CREATE, UPDATE, DELETE, IMPORT';
+COMMENT ON COLUMN "audit_log"."entity_type" IS 'Nama tabel, misal: schedules, pricing_rules, promotions';
+COMMENT ON COLUMN "audit_log"."changes" IS 'Kolom yang berubah: {"kolom": {"from": ..., "to": ...}}';
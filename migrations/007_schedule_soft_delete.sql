@@ -0,0 +1,7 @@
+-- Soft delete jadwal tayang: baris tetap ada agar tiket dan transaksi lama tetap valid.
+
+ALTER TABLE "schedules" ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+
+CREATE INDEX IF NOT EXISTS "schedules_active_start_time_idx" ON "schedules" ("start_time") WHERE "deleted_at" IS NULL;
+
+COMMENT ON COLUMN "schedules"."deleted_at" IS 'Waktu soft delete, NULL = aktif';
@@ -0,0 +1,6 @@
+-- Optimistic concurrency jadwal tayang: version naik setiap kali baris diubah dan
+-- dikirim ke client sebagai ETag, update/delete wajib membawa If-Match yang sesuai.
+
+ALTER TABLE "schedules" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN "schedules"."version" IS 'Naik setiap update, dipakai sebagai ETag';
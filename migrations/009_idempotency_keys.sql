@@ -0,0 +1,18 @@
+-- Idempotency-Key untuk POST yang di-retry client: response pertama disimpan dan dikirim ulang.
+
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+  "user_id" integer NOT NULL REFERENCES "users" ("id"),
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response_status" integer,
+  "response_content_type" varchar,
+  "response_body" bytea,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz,
+  PRIMARY KEY ("user_id", "key")
+);
+
+CREATE INDEX IF NOT EXISTS "idempotency_keys_created_at_idx" ON "idempotency_keys" ("created_at");
+
+COMMENT ON COLUMN "idempotency_keys"."request_hash" IS 'SHA-256 dari method, path dan body request pertama';
+COMMENT ON COLUMN "idempotency_keys"."response_status" IS 'NULL = request pertama masih diproses';
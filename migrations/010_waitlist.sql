@@ -0,0 +1,21 @@
+-- Waitlist jadwal yang penuh: kursi yang terlepas ditawarkan FIFO dengan batas waktu klaim.
+
+CREATE TABLE IF NOT EXISTS "waitlist_entries" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "schedule_id" integer NOT NULL REFERENCES "schedules" ("id"),
+  "user_id" integer NOT NULL REFERENCES "users" ("id"),
+  "seats" integer NOT NULL CHECK ("seats" > 0),
+  "status" varchar NOT NULL DEFAULT 'WAITING',
+  "offered_at" timestamptz,
+  "offer_expires_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- Satu entry aktif per user per jadwal
+CREATE UNIQUE INDEX IF NOT EXISTS "waitlist_entries_active_idx" ON "waitlist_entries" ("schedule_id", "user_id")
+  WHERE "status" IN ('WAITING', 'OFFERED');
+CREATE INDEX IF NOT EXISTS "waitlist_entries_queue_idx" ON "waitlist_entries" ("schedule_id", "status", "created_at");
+
+COMMENT ON COLUMN "waitlist_entries"."status" IS 'WAITING, OFFERED, CLAIMED, EXPIRED, CANCELLED';
+COMMENT ON COLUMN "waitlist_entries"."offer_expires_at" IS 'Batas klaim kursi yang ditawarkan, setelah itu ditawarkan ke antrian berikutnya';
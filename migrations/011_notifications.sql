@@ -0,0 +1,23 @@
+-- Notifikasi email: bahasa per user dan outbox yang ditulis dalam transaksi yang sama
+-- dengan perubahan bisnisnya, lalu dikirim oleh dispatcher dengan retry.
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "locale" varchar NOT NULL DEFAULT 'id';
+
+CREATE TABLE IF NOT EXISTS "notification_outbox" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" integer NOT NULL REFERENCES "users" ("id"),
+  "template" varchar NOT NULL,
+  "data" jsonb NOT NULL DEFAULT '{}',
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" text,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS "notification_outbox_pending_idx" ON "notification_outbox" ("next_attempt_at") WHERE "status" = 'PENDING';
+
+COMMENT ON COLUMN "users"."locale" IS 'Bahasa notifikasi: id, en';
+COMMENT ON COLUMN "notification_outbox"."template" IS 'booking_confirmed, booking_cancelled, schedule_changed, schedule_cancelled, waitlist_offer';
+COMMENT ON COLUMN "notification_outbox"."status" IS 'PENDING, SENT, FAILED';
@@ -0,0 +1,39 @@
+-- Webhook keluar untuk partner: subscription per jenis event yang didaftarkan admin dan
+-- antrian delivery yang ditulis dalam transaksi yang sama dengan perubahan bisnisnya.
+
+CREATE TABLE IF NOT EXISTS "webhook_subscriptions" (
+  "id" SERIAL PRIMARY KEY,
+  "url" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "event_types" varchar[] NOT NULL,
+  "secret" varchar NOT NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "subscription_id" integer NOT NULL REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE,
+  "event_id" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_status_code" integer,
+  "last_error" text,
+  "last_attempt_at" timestamptz,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "redelivery_of" bigint REFERENCES "webhook_deliveries" ("id") ON DELETE SET NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS "webhook_deliveries_pending_idx" ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'PENDING';
+CREATE INDEX IF NOT EXISTS "webhook_deliveries_subscription_idx" ON "webhook_deliveries" ("subscription_id", "id");
+
+COMMENT ON COLUMN "webhook_subscriptions"."event_types" IS 'schedule.created, schedule.updated, schedule.cancelled, ticket.sold';
+COMMENT ON COLUMN "webhook_subscriptions"."secret" IS 'Kunci HMAC-SHA256 untuk header X-MKP-Signature';
+COMMENT ON COLUMN "webhook_deliveries"."event_id" IS 'Sama untuk semua delivery dari satu event, dipakai partner untuk deduplikasi';
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'PENDING, DELIVERED, DEAD';
+COMMENT ON COLUMN "webhook_deliveries"."redelivery_of" IS 'Delivery asal jika dibuat lewat endpoint redeliver';
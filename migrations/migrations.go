@@ -0,0 +1,216 @@
+// Package migrations menjalankan file migration SQL di folder ini secara berurutan dan
+// mencatat yang sudah jalan di tabel schema_migrations, dipakai oleh subcommand migrate.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// ErrNoHistory database sudah berisi tabel tetapi belum punya riwayat migration, misal dibuat
+// manual dari schema.sql sebelum subcommand migrate ada. Tandai dulu lewat Baseline.
+var ErrNoHistory = errors.New("database has tables but no migration history; run with -baseline")
+
+// Migration satu file migration, Version adalah prefix angka nama file (misal 001)
+type Migration struct {
+	Version string
+	Name    string
+	SQL     string
+}
+
+// State migration beserta waktu dijalankan, AppliedAt nil jika belum
+type State struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+const createHistoryTable = `
+	CREATE TABLE IF NOT EXISTS "schema_migrations" (
+		"version" varchar PRIMARY KEY,
+		"name" varchar NOT NULL,
+		"applied_at" timestamptz NOT NULL DEFAULT (now())
+	)
+`
+
+// All semua migration urut versi
+func All() ([]Migration, error) {
+	names, err := files.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	all := []Migration{}
+	for _, entry := range names {
+		version, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: name must be <version>_<name>.sql", entry.Name())
+		}
+		content, err := files.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, Migration{Version: version, Name: name, SQL: string(content)})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all, nil
+}
+
+// Status semua migration dan kapan dijalankan
+func Status(db *sql.DB) ([]State, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := history(db)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]State, len(all))
+	for i, m := range all {
+		states[i] = State{Migration: m}
+		if at, ok := applied[m.Version]; ok {
+			states[i].AppliedAt = &at
+		}
+	}
+	return states, nil
+}
+
+// Migrate menjalankan migration yang belum tercatat, masing-masing dalam transaksinya sendiri,
+// dan mengembalikan migration yang dijalankan. Database kosong dibuat langsung dari schema
+// (schema.sql lengkap) lalu semua migration ditandai sudah jalan karena sudah termasuk di dalamnya.
+func Migrate(db *sql.DB, schema string) ([]Migration, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	var hasTables bool
+	if err := db.QueryRow(`SELECT to_regclass('users') IS NOT NULL`).Scan(&hasTables); err != nil {
+		return nil, err
+	}
+	if !hasTables {
+		tx, err := db.Begin()
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+		if _, err := tx.Exec(schema); err != nil {
+			return nil, fmt.Errorf("schema: %w", err)
+		}
+		if err := markApplied(tx, all); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	}
+
+	applied, err := history(db)
+	if err != nil {
+		return nil, err
+	}
+	if len(applied) == 0 {
+		return nil, ErrNoHistory
+	}
+
+	ran := []Migration{}
+	for _, m := range all {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := apply(db, m); err != nil {
+			return ran, err
+		}
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// Baseline menandai migration sampai version (inklusif) sudah jalan tanpa menjalankannya,
+// untuk database lama yang skemanya sudah sampai versi tersebut. Version "all" berarti semua.
+func Baseline(db *sql.DB, version string) ([]Migration, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+	marked := []Migration{}
+	found := version == "all"
+	for _, m := range all {
+		if version != "all" && m.Version > version {
+			break
+		}
+		if m.Version == version {
+			found = true
+		}
+		marked = append(marked, m)
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown migration version %q", version)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := markApplied(tx, marked); err != nil {
+		return nil, err
+	}
+	return marked, tx.Commit()
+}
+
+func apply(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(m.SQL); err != nil {
+		return fmt.Errorf("migration %s_%s: %w", m.Version, m.Name, err)
+	}
+	if err := markApplied(tx, []Migration{m}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func markApplied(tx *sql.Tx, ms []Migration) error {
+	if _, err := tx.Exec(createHistoryTable); err != nil {
+		return err
+	}
+	for _, m := range ms {
+		_, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2) ON CONFLICT (version) DO NOTHING`,
+			m.Version, m.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// history versi yang sudah jalan beserta waktunya
+func history(db *sql.DB) (map[string]time.Time, error) {
+	if _, err := db.Exec(createHistoryTable); err != nil {
+		return nil, err
+	}
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[string]time.Time{}
+	for rows.Next() {
+		var version string
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"mkp/apierror"
+	"mkp/validation"
+	"time"
+)
+
+// Status transaksi
+const (
+	TransactionStatusPending   = "PENDING"
+	TransactionStatusPaid      = "PAID"
+	TransactionStatusCancelled = "CANCELLED"
+	TransactionStatusRefunded  = "REFUNDED"
+)
+
+// Metode pembayaran yang diterima
+const (
+	PaymentMethodCreditCard   = "CREDIT_CARD"
+	PaymentMethodBankTransfer = "BANK_TRANSFER"
+	PaymentMethodEWallet      = "E_WALLET"
+)
+
+// MaxSeatsPerBooking batas jumlah kursi dalam satu booking
+const MaxSeatsPerBooking = 10
+
+// Transaction model transaksi pembelian tiket beserta tiketnya
+type Transaction struct {
+	ID             int        `json:"id"`
+	UserID         int        `json:"user_id"`
+	ScheduleID     int        `json:"schedule_id"`
+	SubtotalAmount float64    `json:"subtotal_amount"`
+	DiscountAmount float64    `json:"discount_amount"`
+	TotalAmount    float64    `json:"total_amount"`
+	PromotionID    *int       `json:"promotion_id"`
+	PromoCode      *string    `json:"promo_code"`
+	PaymentMethod  *string    `json:"payment_method"`
+	PaymentTime    *time.Time `json:"payment_time"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Tickets        []Ticket   `json:"tickets"`
+}
+
+// Ticket model tiket per kursi, price adalah harga final saat booking
+type Ticket struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	ScheduleID    int       `json:"schedule_id"`
+	SeatID        int       `json:"seat_id"`
+	SeatLabel     string    `json:"seat_label"`
+	Price         float64   `json:"price"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BookingRequest model untuk memesan kursi pada sebuah jadwal
+type BookingRequest struct {
+	ScheduleID int     `json:"schedule_id" validate:"required,gt=0"`
+	SeatIDs    []int   `json:"seat_ids"`
+	PromoCode  *string `json:"promo_code" validate:"min=3,max=32"`
+}
+
+// Validate memastikan seat_ids terisi, unik dan tidak melebihi batas
+func (req BookingRequest) Validate() []apierror.FieldError {
+	if len(req.SeatIDs) == 0 {
+		return []apierror.FieldError{{Field: "seat_ids", Code: validation.CodeRequired, Message: "seat_ids is required"}}
+	}
+	if len(req.SeatIDs) > MaxSeatsPerBooking {
+		return []apierror.FieldError{{Field: "seat_ids", Code: validation.CodeOutOfRange, Message: fmt.Sprintf("seat_ids must contain at most %d seats", MaxSeatsPerBooking)}}
+	}
+
+	seen := map[int]bool{}
+	for _, id := range req.SeatIDs {
+		if id <= 0 || seen[id] {
+			return []apierror.FieldError{{Field: "seat_ids", Code: validation.CodeDuplicate, Message: "seat_ids must contain unique positive IDs"}}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// PaymentRequest model untuk membayar booking PENDING
+type PaymentRequest struct {
+	PaymentMethod string `json:"payment_method" validate:"required,oneof=CREDIT_CARD BANK_TRANSFER E_WALLET"`
+}
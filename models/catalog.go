@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Movie model film
+type Movie struct {
+	ID              int       `json:"id"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	DurationMinutes int       `json:"duration_minutes"`
+	ReleaseDate     *string   `json:"release_date"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// MovieSearchResult film hasil pencarian beserta skor relevansinya
+type MovieSearchResult struct {
+	Movie
+	Relevance float64 `json:"relevance"`
+}
+
+// Cinema model cabang bioskop
+type Cinema struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	City      string     `json:"city"`
+	Address   string     `json:"address"`
+	Timezone  string     `json:"timezone"`
+	CreatedAt *time.Time `json:"created_at"`
+	Studios   []Studio   `json:"studios,omitempty"`
+}
+
+// Studio model studio di dalam cinema
+type Studio struct {
+	ID         int    `json:"id"`
+	CinemaID   int    `json:"cinema_id"`
+	Name       string `json:"name"`
+	TotalSeats int    `json:"total_seats"`
+	StudioType string `json:"studio_type"`
+}
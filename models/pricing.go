@@ -0,0 +1,119 @@
+package models
+
+import (
+	"fmt"
+	"mkp/apierror"
+	"mkp/validation"
+	"time"
+)
+
+// Tipe penyesuaian harga pada pricing rule
+const (
+	AdjustmentPercentage = "PERCENTAGE"
+	AdjustmentFixed      = "FIXED"
+)
+
+// Tipe studio dan kategori kursi yang dikenal oleh pricing rule
+const (
+	StudioTypeRegular  = "REGULAR"
+	StudioTypeIMAX     = "IMAX"
+	StudioTypePremiere = "PREMIERE"
+
+	SeatCategoryRegular = "REGULAR"
+	SeatCategoryPremium = "PREMIUM"
+	SeatCategoryCouple  = "COUPLE"
+)
+
+// PricingRule aturan penyesuaian harga tiket. Semua kriteria yang diisi harus cocok
+// agar rule berlaku; kriteria kosong berarti berlaku untuk semua.
+type PricingRule struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	DaysOfWeek      []int     `json:"days_of_week"`
+	TimeFrom        *string   `json:"time_from"`
+	TimeTo          *string   `json:"time_to"`
+	StudioType      *string   `json:"studio_type"`
+	SeatCategory    *string   `json:"seat_category"`
+	HolidayOnly     bool      `json:"holiday_only"`
+	AdjustmentType  string    `json:"adjustment_type"`
+	AdjustmentValue float64   `json:"adjustment_value"`
+	Priority        int       `json:"priority"`
+	Active          bool      `json:"active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PricingRuleRequest model untuk membuat atau mengganti pricing rule
+type PricingRuleRequest struct {
+	Name            string  `json:"name" validate:"required,max=100"`
+	DaysOfWeek      []int   `json:"days_of_week"`
+	TimeFrom        *string `json:"time_from" validate:"clock"`
+	TimeTo          *string `json:"time_to" validate:"clock"`
+	StudioType      *string `json:"studio_type" validate:"oneof=REGULAR IMAX PREMIERE"`
+	SeatCategory    *string `json:"seat_category" validate:"oneof=REGULAR PREMIUM COUPLE"`
+	HolidayOnly     bool    `json:"holiday_only"`
+	AdjustmentType  string  `json:"adjustment_type" validate:"required,oneof=PERCENTAGE FIXED"`
+	AdjustmentValue float64 `json:"adjustment_value" validate:"required"`
+	Priority        int     `json:"priority" validate:"min=0"`
+	Active          *bool   `json:"active"`
+}
+
+// Validate mengecek days_of_week dan pasangan time_from/time_to
+func (req PricingRuleRequest) Validate() []apierror.FieldError {
+	errs := []apierror.FieldError{}
+
+	for _, day := range req.DaysOfWeek {
+		if day < 0 || day > 6 {
+			errs = append(errs, apierror.FieldError{
+				Field:   "days_of_week",
+				Code:    validation.CodeOutOfRange,
+				Message: fmt.Sprintf("days_of_week must be between 0 (Sunday) and 6 (Saturday), got %d", day),
+			})
+			break
+		}
+	}
+
+	if (req.TimeFrom == nil) != (req.TimeTo == nil) {
+		errs = append(errs, apierror.FieldError{
+			Field:   "time_to",
+			Code:    validation.CodeRequired,
+			Message: "time_from and time_to must be set together",
+		})
+	}
+
+	if req.AdjustmentType == AdjustmentPercentage && req.AdjustmentValue < -100 {
+		errs = append(errs, apierror.FieldError{
+			Field:   "adjustment_value",
+			Code:    validation.CodeOutOfRange,
+			Message: "adjustment_value must be at least -100 for PERCENTAGE rules",
+		})
+	}
+
+	return errs
+}
+
+// AppliedRule rule yang ikut membentuk harga sebuah kursi
+type AppliedRule struct {
+	RuleID int     `json:"rule_id"`
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+}
+
+// SeatPrice harga final satu kursi beserta rincian rule yang berlaku
+type SeatPrice struct {
+	SeatID       int           `json:"seat_id"`
+	SeatLabel    string        `json:"seat_label"`
+	Category     string        `json:"category"`
+	BasePrice    float64       `json:"base_price"`
+	Price        float64       `json:"price"`
+	AppliedRules []AppliedRule `json:"applied_rules"`
+}
+
+// PriceQuote rincian harga untuk satu jadwal dan sekumpulan kursi
+type PriceQuote struct {
+	ScheduleID int         `json:"schedule_id"`
+	BasePrice  float64     `json:"base_price"`
+	IsHoliday  bool        `json:"is_holiday"`
+	Seats      []SeatPrice `json:"seats"`
+	Total      float64     `json:"total"`
+}
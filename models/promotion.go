@@ -0,0 +1,81 @@
+package models
+
+import (
+	"mkp/apierror"
+	"mkp/validation"
+	"time"
+)
+
+// Tipe diskon promo
+const (
+	DiscountPercentage = "PERCENTAGE"
+	DiscountFixed      = "FIXED"
+)
+
+// Promotion model voucher diskon
+type Promotion struct {
+	ID                int       `json:"id"`
+	Code              string    `json:"code"`
+	Description       string    `json:"description"`
+	DiscountType      string    `json:"discount_type"`
+	DiscountValue     float64   `json:"discount_value"`
+	MaxDiscount       *float64  `json:"max_discount"`
+	MinSpend          float64   `json:"min_spend"`
+	ValidFrom         time.Time `json:"valid_from"`
+	ValidUntil        time.Time `json:"valid_until"`
+	UsageLimit        *int      `json:"usage_limit"`
+	UsageLimitPerUser *int      `json:"usage_limit_per_user"`
+	UsedCount         int       `json:"used_count"`
+	MovieIDs          []int     `json:"movie_ids"`
+	CinemaIDs         []int     `json:"cinema_ids"`
+	Active            bool      `json:"active"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// PromotionRequest model untuk membuat atau mengganti promo
+type PromotionRequest struct {
+	Code              string   `json:"code" validate:"required,min=3,max=32"`
+	Description       string   `json:"description" validate:"max=500"`
+	DiscountType      string   `json:"discount_type" validate:"required,oneof=PERCENTAGE FIXED"`
+	DiscountValue     float64  `json:"discount_value" validate:"required,gt=0"`
+	MaxDiscount       *float64 `json:"max_discount" validate:"gt=0"`
+	MinSpend          float64  `json:"min_spend" validate:"min=0"`
+	ValidFrom         string   `json:"valid_from" validate:"required,datetime"`
+	ValidUntil        string   `json:"valid_until" validate:"required,datetime"`
+	UsageLimit        *int     `json:"usage_limit" validate:"gt=0"`
+	UsageLimitPerUser *int     `json:"usage_limit_per_user" validate:"gt=0"`
+	MovieIDs          []int    `json:"movie_ids"`
+	CinemaIDs         []int    `json:"cinema_ids"`
+	Active            *bool    `json:"active"`
+}
+
+// Validate mengecek persentase maksimal 100 dan periode berlaku
+func (req PromotionRequest) Validate() []apierror.FieldError {
+	errs := []apierror.FieldError{}
+	if req.DiscountType == DiscountPercentage && req.DiscountValue > 100 {
+		errs = append(errs, apierror.FieldError{
+			Field:   "discount_value",
+			Code:    validation.CodeOutOfRange,
+			Message: "discount_value must be at most 100 for PERCENTAGE promotions",
+		})
+	}
+
+	from, errFrom := validation.ParseDateTime(req.ValidFrom)
+	until, errUntil := validation.ParseDateTime(req.ValidUntil)
+	if errFrom == nil && errUntil == nil && !until.After(from) {
+		errs = append(errs, apierror.FieldError{
+			Field:   "valid_until",
+			Code:    validation.CodeInvalidRange,
+			Message: "valid_until must be after valid_from",
+		})
+	}
+	return errs
+}
+
+// AppliedPromotion hasil penerapan promo pada sebuah booking
+type AppliedPromotion struct {
+	PromotionID    int     `json:"promotion_id"`
+	Code           string  `json:"code"`
+	DiscountAmount float64 `json:"discount_amount"`
+}
@@ -1,37 +1,187 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"mkp/apierror"
+	"mkp/recurrence"
+	"mkp/validation"
+	"time"
+)
 
+// Schedule model jadwal tayang. StartTime dan EndTime selalu dalam UTC,
+// sedangkan field *_local berisi waktu yang sama dalam timezone cinema.
 type Schedule struct {
-	ID         int       `json:"id"`
-	MovieID    int       `json:"movie_id"`
-	StudioID   int       `json:"studio_id"`
-	StartTime  time.Time `json:"start_time"`
-	EndTime    time.Time `json:"end_time"`
-	Price      float64   `json:"price"`
-	Status     string    `json:"status"`
-	CreatedAt  time.Time `json:"created_at"`
-	MovieTitle string    `json:"movie_title,omitempty"`
-	StudioName string    `json:"studio_name,omitempty"`
-	CinemaName string    `json:"cinema_name,omitempty"`
-}
-
-// ScheduleCreateRequest model untuk membuat jadwal baru
+	ID             int        `json:"id"`
+	MovieID        int        `json:"movie_id"`
+	StudioID       int        `json:"studio_id"`
+	StartTime      time.Time  `json:"start_time"`
+	EndTime        time.Time  `json:"end_time"`
+	StartTimeLocal string     `json:"start_time_local"`
+	EndTimeLocal   string     `json:"end_time_local"`
+	Timezone       string     `json:"timezone"`
+	Price          float64    `json:"price"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	Version        int        `json:"version"`
+	MovieTitle     string     `json:"movie_title,omitempty"`
+	StudioName     string     `json:"studio_name,omitempty"`
+	CinemaName     string     `json:"cinema_name,omitempty"`
+}
+
+// SetTimezone menormalkan waktu ke UTC dan mengisi waktu lokal sesuai timezone cinema
+func (s *Schedule) SetTimezone(loc *time.Location) {
+	s.StartTime = s.StartTime.UTC()
+	s.EndTime = s.EndTime.UTC()
+	s.Timezone = loc.String()
+	s.StartTimeLocal = s.StartTime.In(loc).Format(time.RFC3339)
+	s.EndTimeLocal = s.EndTime.In(loc).Format(time.RFC3339)
+}
+
+// Status jadwal tayang yang valid
+const (
+	ScheduleStatusShowing   = "SHOWING"
+	ScheduleStatusCancelled = "CANCELLED"
+	ScheduleStatusEnded     = "ENDED"
+)
+
+// ScheduleCreateRequest model untuk membuat jadwal baru, juga body PUT (penggantian penuh)
 type ScheduleCreateRequest struct {
-	MovieID   int     `json:"movie_id"`
-	StudioID  int     `json:"studio_id"`
-	StartTime string  `json:"start_time"`
-	EndTime   string  `json:"end_time"`
-	Price     float64 `json:"price"`
-	Status    string  `json:"status"`
-}
-
-// ScheduleUpdateRequest model untuk update jadwal
-type ScheduleUpdateRequest struct {
-	MovieID   *int     `json:"movie_id,omitempty"`
-	StudioID  *int     `json:"studio_id,omitempty"`
-	StartTime *string  `json:"start_time,omitempty"`
-	EndTime   *string  `json:"end_time,omitempty"`
-	Price     *float64 `json:"price,omitempty"`
-	Status    *string  `json:"status,omitempty"`
+	MovieID   int     `json:"movie_id" validate:"required,gt=0"`
+	StudioID  int     `json:"studio_id" validate:"required,gt=0"`
+	StartTime string  `json:"start_time" validate:"required,datetime"`
+	EndTime   string  `json:"end_time" validate:"required,datetime"`
+	Price     float64 `json:"price" validate:"required,gt=0"`
+	Status    string  `json:"status" validate:"oneof=SHOWING CANCELLED ENDED"`
+}
+
+// Validate memastikan start_time lebih awal dari end_time
+func (req ScheduleCreateRequest) Validate() []apierror.FieldError {
+	return validateTimeRange(req.StartTime, req.EndTime)
+}
+
+// validateTimeRange mengecek urutan waktu; format yang salah sudah dilaporkan oleh rule datetime.
+// Campuran waktu lokal dan RFC 3339 baru bisa dibandingkan setelah timezone cinema diketahui.
+func validateTimeRange(start, end string) []apierror.FieldError {
+	if validation.HasOffset(start) != validation.HasOffset(end) {
+		return nil
+	}
+	startTime, err := validation.ParseDateTime(start)
+	if err != nil {
+		return nil
+	}
+	endTime, err := validation.ParseDateTime(end)
+	if err != nil {
+		return nil
+	}
+	if !endTime.After(startTime) {
+		return []apierror.FieldError{{
+			Field:   "end_time",
+			Code:    validation.CodeInvalidRange,
+			Message: "end_time must be after start_time",
+		}}
+	}
+	return nil
+}
+
+// Batas bulk schedule agar satu request tidak mengunci studio terlalu lama
+const (
+	MaxBulkSchedules = 500
+	MaxBulkRangeDays = 62
+)
+
+// ScheduleBulkRequest model untuk membuat banyak jadwal sekaligus.
+// Tanggal kemunculan diambil dari recurrence (RRULE, default setiap hari) dalam rentang
+// start_date..end_date, lalu dikalikan dengan time_slots untuk setiap studio.
+// Jam pada time_slots adalah waktu lokal cinema masing-masing studio.
+type ScheduleBulkRequest struct {
+	MovieID         int      `json:"movie_id" validate:"required,gt=0"`
+	StudioIDs       []int    `json:"studio_ids"`
+	StartDate       string   `json:"start_date" validate:"required,date"`
+	EndDate         string   `json:"end_date" validate:"required,date"`
+	TimeSlots       []string `json:"time_slots"`
+	Recurrence      string   `json:"recurrence"`
+	DurationMinutes *int     `json:"duration_minutes" validate:"gt=0,max=600"`
+	Price           float64  `json:"price" validate:"required,gt=0"`
+	Status          string   `json:"status" validate:"oneof=SHOWING CANCELLED ENDED"`
+	DryRun          bool     `json:"dry_run"`
+}
+
+// Validate mengecek studio_ids, time_slots, rentang tanggal dan format recurrence
+func (req ScheduleBulkRequest) Validate() []apierror.FieldError {
+	errs := []apierror.FieldError{}
+
+	if len(req.StudioIDs) == 0 {
+		errs = append(errs, apierror.FieldError{Field: "studio_ids", Code: validation.CodeRequired, Message: "studio_ids is required"})
+	} else if !uniquePositive(req.StudioIDs) {
+		errs = append(errs, apierror.FieldError{Field: "studio_ids", Code: validation.CodeDuplicate, Message: "studio_ids must contain unique positive IDs"})
+	}
+
+	if len(req.TimeSlots) == 0 {
+		errs = append(errs, apierror.FieldError{Field: "time_slots", Code: validation.CodeRequired, Message: "time_slots is required"})
+	}
+	seen := map[string]bool{}
+	for i, slot := range req.TimeSlots {
+		field := fmt.Sprintf("time_slots[%d]", i)
+		if _, err := time.Parse(validation.ClockLayout, slot); err != nil {
+			errs = append(errs, apierror.FieldError{Field: field, Code: validation.CodeInvalidClock, Message: field + " must use format HH:MM"})
+		} else if seen[slot] {
+			errs = append(errs, apierror.FieldError{Field: field, Code: validation.CodeDuplicate, Message: field + " is duplicated"})
+		}
+		seen[slot] = true
+	}
+
+	startDate, errStart := time.Parse(validation.DateLayout, req.StartDate)
+	endDate, errEnd := time.Parse(validation.DateLayout, req.EndDate)
+	if errStart == nil && errEnd == nil {
+		if endDate.Before(startDate) {
+			errs = append(errs, apierror.FieldError{Field: "end_date", Code: validation.CodeInvalidRange, Message: "end_date must not be before start_date"})
+		} else if endDate.Sub(startDate).Hours()/24 >= MaxBulkRangeDays {
+			errs = append(errs, apierror.FieldError{
+				Field:   "end_date",
+				Code:    validation.CodeOutOfRange,
+				Message: fmt.Sprintf("date range must not exceed %d days", MaxBulkRangeDays),
+			})
+		}
+	}
+
+	if req.Recurrence != "" {
+		if _, err := recurrence.Parse(req.Recurrence); err != nil {
+			errs = append(errs, apierror.FieldError{Field: "recurrence", Code: validation.CodeInvalidRecurrence, Message: err.Error()})
+		}
+	}
+
+	return errs
+}
+
+// ScheduleBulkItem satu jadwal hasil ekspansi bulk request beserta error-nya
+type ScheduleBulkItem struct {
+	Index          int                   `json:"index"`
+	ID             int                   `json:"id,omitempty"`
+	StudioID       int                   `json:"studio_id"`
+	StartTime      time.Time             `json:"start_time"`
+	EndTime        time.Time             `json:"end_time"`
+	StartTimeLocal string                `json:"start_time_local"`
+	EndTimeLocal   string                `json:"end_time_local"`
+	Errors         []apierror.FieldError `json:"errors,omitempty"`
+}
+
+// ScheduleBulkResult laporan bulk create, dikembalikan pada dry run maupun setelah insert
+type ScheduleBulkResult struct {
+	DryRun  bool               `json:"dry_run"`
+	Valid   bool               `json:"valid"`
+	Total   int                `json:"total"`
+	Invalid int                `json:"invalid"`
+	Items   []ScheduleBulkItem `json:"items"`
+}
+
+func uniquePositive(ids []int) bool {
+	seen := map[int]bool{}
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
 }
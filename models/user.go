@@ -2,19 +2,35 @@ package models
 
 import "time"
 
+// Role user
+const (
+	RoleCustomer = "CUSTOMER"
+	RoleAdmin    = "ADMIN"
+)
+
 type User struct {
 	ID           int       `json:"id"`
 	Fullname     string    `json:"fullname"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	Locale       string    `json:"locale"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// RegisterRequest model untuk request registrasi
+type RegisterRequest struct {
+	Fullname string `json:"fullname" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=6,max=72,maxbytes=72"`
+	Locale   string `json:"locale" validate:"oneof=id en"`
+}
+
 // LoginRequest model untuk request login
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
 }
 
 // LoginResponse model untuk response login
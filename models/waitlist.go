@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Status entry waitlist
+const (
+	WaitlistStatusWaiting   = "WAITING"
+	WaitlistStatusOffered   = "OFFERED"
+	WaitlistStatusClaimed   = "CLAIMED"
+	WaitlistStatusExpired   = "EXPIRED"
+	WaitlistStatusCancelled = "CANCELLED"
+)
+
+// WaitlistEntry antrian user untuk jadwal yang penuh. Saat kursi terlepas entry menjadi OFFERED
+// dan user harus booking sebelum offer_expires_at; position hanya diisi untuk status WAITING.
+type WaitlistEntry struct {
+	ID             int        `json:"id"`
+	ScheduleID     int        `json:"schedule_id"`
+	UserID         int        `json:"user_id"`
+	Seats          int        `json:"seats"`
+	Status         string     `json:"status"`
+	Position       *int       `json:"position,omitempty"`
+	OfferedAt      *time.Time `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WaitlistRequest model untuk masuk waitlist sebuah jadwal
+type WaitlistRequest struct {
+	ScheduleID int `json:"schedule_id" validate:"required,gt=0"`
+	Seats      int `json:"seats" validate:"required,gt=0,max=10"`
+}
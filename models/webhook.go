@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"mkp/apierror"
+	"mkp/validation"
+	"strings"
+	"time"
+)
+
+// Jenis event webhook untuk partner
+const (
+	WebhookEventScheduleCreated   = "schedule.created"
+	WebhookEventScheduleUpdated   = "schedule.updated"
+	WebhookEventScheduleCancelled = "schedule.cancelled"
+	WebhookEventTicketSold        = "ticket.sold"
+)
+
+// WebhookEventTypes semua jenis event yang bisa disubscribe
+var WebhookEventTypes = []string{
+	WebhookEventScheduleCreated,
+	WebhookEventScheduleUpdated,
+	WebhookEventScheduleCancelled,
+	WebhookEventTicketSold,
+}
+
+// Status delivery webhook
+const (
+	WebhookDeliveryPending   = "PENDING"
+	WebhookDeliveryDelivered = "DELIVERED"
+	WebhookDeliveryDead      = "DEAD"
+)
+
+// WebhookSubscription endpoint partner yang menerima event. Secret hanya ditampilkan
+// di response create.
+type WebhookSubscription struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	EventTypes  []string  `json:"event_types"`
+	Secret      string    `json:"secret,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookSubscriptionRequest model untuk membuat atau mengganti subscription.
+// Secret kosong saat create berarti dibuatkan otomatis; saat update berarti secret lama dipakai.
+type WebhookSubscriptionRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	Description string   `json:"description" validate:"max=500"`
+	EventTypes  []string `json:"event_types"`
+	Secret      string   `json:"secret" validate:"min=16,max=256"`
+	Active      *bool    `json:"active"`
+}
+
+// Validate mengecek event_types tidak kosong dan semuanya dikenal
+func (req WebhookSubscriptionRequest) Validate() []apierror.FieldError {
+	if len(req.EventTypes) == 0 {
+		return []apierror.FieldError{{
+			Field: "event_types", Code: validation.CodeRequired, Message: "event_types is required",
+		}}
+	}
+	errs := []apierror.FieldError{}
+	for i, eventType := range req.EventTypes {
+		if !isWebhookEventType(eventType) {
+			errs = append(errs, apierror.FieldError{
+				Field:   fmt.Sprintf("event_types[%d]", i),
+				Code:    validation.CodeInvalidEnum,
+				Message: "event type must be one of: " + strings.Join(WebhookEventTypes, ", "),
+			})
+		}
+	}
+	return errs
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, known := range WebhookEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery satu pengiriman event ke satu subscription beserta hasil percobaan terakhirnya
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int             `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      *string         `json:"last_error"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	RedeliveryOf   *int64          `json:"redelivery_of"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
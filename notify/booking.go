@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"fmt"
+	"mkp/models"
+)
+
+// localTime format waktu lokal cinema yang dipakai di isi notifikasi
+const localTime = "'YYYY-MM-DD HH24:MI'"
+
+// EnqueueBooking menulis notifikasi untuk pemilik booking beserta detail jadwal dan kursinya
+func EnqueueBooking(tx Querier, transactionID int, template string) error {
+	var userID int
+	var fullname, status, movieTitle, cinemaName, studioName, timezone, startTime, seats string
+	var total float64
+	err := tx.QueryRow(`
+		SELECT tr.user_id, u.fullname, tr.status, tr.total_amount,
+			COALESCE(m.title, ''), COALESCE(c.name, ''), COALESCE(st.name, ''), COALESCE(c.timezone, ''),
+			to_char(s.start_time AT TIME ZONE COALESCE(c.timezone, 'UTC'), `+localTime+`),
+			COALESCE(string_agg(se.row_code || se.seat_number, ', ' ORDER BY se.row_code, se.seat_number), '')
+		FROM transactions tr
+		JOIN users u ON tr.user_id = u.id
+		JOIN schedules s ON tr.schedule_id = s.id
+		LEFT JOIN movies m ON s.movie_id = m.id
+		LEFT JOIN studios st ON s.studio_id = st.id
+		LEFT JOIN cinemas c ON st.cinema_id = c.id
+		LEFT JOIN tickets t ON t.transaction_id = tr.id
+		LEFT JOIN seats se ON t.seat_id = se.id
+		WHERE tr.id = $1
+		GROUP BY tr.id, u.id, s.id, m.id, st.id, c.id
+	`, transactionID).Scan(&userID, &fullname, &status, &total, &movieTitle, &cinemaName, &studioName, &timezone, &startTime, &seats)
+	if err != nil {
+		return err
+	}
+
+	return Enqueue(tx, userID, template, map[string]interface{}{
+		"fullname":         fullname,
+		"booking_id":       transactionID,
+		"status":           status,
+		"total_amount":     fmt.Sprintf("%.0f", total),
+		"movie_title":      movieTitle,
+		"cinema_name":      cinemaName,
+		"studio_name":      studioName,
+		"timezone":         timezone,
+		"start_time_local": startTime,
+		"seats":            seats,
+	})
+}
+
+// EnqueueScheduleHolders menulis notifikasi untuk semua pemilik booking PENDING/PAID sebuah jadwal,
+// misal saat jadwal diubah atau dibatalkan
+func EnqueueScheduleHolders(tx Querier, scheduleID int, template string) error {
+	rows, err := tx.Query("SELECT id FROM transactions WHERE schedule_id = $1 AND status IN ($2, $3) ORDER BY id",
+		scheduleID, models.TransactionStatusPending, models.TransactionStatusPaid)
+	if err != nil {
+		return err
+	}
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := EnqueueBooking(tx, id, template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnqueueWaitlistOffer menulis notifikasi tawaran kursi untuk satu entry waitlist
+func EnqueueWaitlistOffer(tx Querier, entryID int) error {
+	var userID, seats int
+	var fullname, movieTitle, cinemaName, timezone, startTime, expiresAt string
+	err := tx.QueryRow(`
+		SELECT w.user_id, u.fullname, w.seats,
+			COALESCE(m.title, ''), COALESCE(c.name, ''), COALESCE(c.timezone, ''),
+			to_char(s.start_time AT TIME ZONE COALESCE(c.timezone, 'UTC'), `+localTime+`),
+			to_char(w.offer_expires_at AT TIME ZONE COALESCE(c.timezone, 'UTC'), `+localTime+`)
+		FROM waitlist_entries w
+		JOIN users u ON w.user_id = u.id
+		JOIN schedules s ON w.schedule_id = s.id
+		LEFT JOIN movies m ON s.movie_id = m.id
+		LEFT JOIN studios st ON s.studio_id = st.id
+		LEFT JOIN cinemas c ON st.cinema_id = c.id
+		WHERE w.id = $1
+	`, entryID).Scan(&userID, &fullname, &seats, &movieTitle, &cinemaName, &timezone, &startTime, &expiresAt)
+	if err != nil {
+		return err
+	}
+
+	return Enqueue(tx, userID, TemplateWaitlistOffer, map[string]interface{}{
+		"fullname":               fullname,
+		"waitlist_id":            entryID,
+		"seats":                  seats,
+		"movie_title":            movieTitle,
+		"cinema_name":            cinemaName,
+		"timezone":               timezone,
+		"start_time_local":       startTime,
+		"offer_expires_at_local": expiresAt,
+	})
+}
@@ -0,0 +1,266 @@
+package notify
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/smtp"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Locale bahasa template yang tersedia
+const (
+	LocaleID = "id"
+	LocaleEN = "en"
+)
+
+// DefaultLocale bahasa notifikasi jika user belum memilih
+const DefaultLocale = LocaleID
+
+// Status baris outbox
+const (
+	StatusPending = "PENDING"
+	StatusSent    = "SENT"
+	StatusFailed  = "FAILED"
+)
+
+// MaxAttempts jumlah percobaan kirim sebelum baris outbox ditandai FAILED
+const MaxAttempts = 8
+
+// Email pesan yang sudah dirender dan siap dikirim
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier mengirim satu pesan. Error berarti pesan akan dicoba lagi oleh dispatcher.
+type Notifier interface {
+	Send(email Email) error
+}
+
+// Querier dipenuhi oleh *sql.Tx, agar outbox ditulis dalam transaksi perubahan bisnisnya
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Enqueue menulis notifikasi untuk user ke outbox. Email dan bahasa diambil dari data user
+// saat dikirim, data dipakai sebagai isi template.
+func Enqueue(tx Querier, userID int, template string, data map[string]interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO notification_outbox (user_id, template, data, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, 0, NOW(), NOW())
+	`, userID, template, payload, StatusPending)
+	return err
+}
+
+// SMTPNotifier mengirim email lewat server SMTP
+type SMTPNotifier struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// Send mengirim email teks biasa dengan subject ter-encode UTF-8
+func (n SMTPNotifier) Send(email Email) error {
+	var auth smtp.Auth
+	if n.Username != "" {
+		host := strings.Split(n.Addr, ":")[0]
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+
+	message := "From: " + n.From + "\r\n" +
+		"To: " + email.To + "\r\n" +
+		"Subject: =?UTF-8?B?" + base64.StdEncoding.EncodeToString([]byte(email.Subject)) + "?=\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + strings.ReplaceAll(email.Body, "\n", "\r\n")
+	return smtp.SendMail(n.Addr, auth, n.From, []string{email.To}, []byte(message))
+}
+
+// FileNotifier menulis email ke file (satu JSON per baris), untuk development tanpa SMTP
+type FileNotifier struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Send menambahkan email ke akhir file
+func (n *FileNotifier) Send(email Email) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(map[string]interface{}{
+		"to":      email.To,
+		"subject": email.Subject,
+		"body":    email.Body,
+		"sent_at": time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// FromEnv memilih notifier dari environment: SMTP jika SMTP_ADDR diisi
+// (SMTP_FROM, SMTP_USERNAME, SMTP_PASSWORD), selain itu file NOTIFY_FILE
+// (default notifications.log).
+func FromEnv() Notifier {
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		from := os.Getenv("SMTP_FROM")
+		if from == "" {
+			from = "no-reply@mkp-cinema.local"
+		}
+		return SMTPNotifier{Addr: addr, From: from, Username: os.Getenv("SMTP_USERNAME"), Password: os.Getenv("SMTP_PASSWORD")}
+	}
+	path := os.Getenv("NOTIFY_FILE")
+	if path == "" {
+		path = "notifications.log"
+	}
+	return &FileNotifier{Path: path}
+}
+
+// Dispatcher mengirim isi outbox lewat Notifier dengan retry dan backoff eksponensial
+type Dispatcher struct {
+	DB        *sql.DB
+	Notifier  Notifier
+	BatchSize int
+}
+
+// Run memproses outbox setiap interval; dipanggil sebagai goroutine dari main
+func (d Dispatcher) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for {
+			n, err := d.DispatchOnce()
+			if err != nil {
+				log.Println("notification dispatcher:", err)
+			}
+			if err != nil || n < d.batchSize() {
+				break
+			}
+		}
+	}
+}
+
+// claimLease lama baris yang sudah diklaim tidak diambil dispatcher lain. Jika instance mati
+// sebelum mencatat hasil pengiriman, baris diambil lagi setelah lease habis.
+const claimLease = 5 * time.Minute
+
+// DispatchOnce mengirim satu batch notifikasi yang sudah jatuh tempo dan mengembalikan
+// jumlah baris yang diproses. Batch diklaim dengan SKIP LOCKED dan next_attempt_at dimajukan
+// sebagai lease lalu langsung di-commit, sehingga pengiriman email tidak terjadi di dalam
+// transaksi dan beberapa instance API bisa menjalankan dispatcher bersamaan tanpa mengirim dobel.
+// Hasil setiap pengiriman dicatat dengan update tersendiri.
+func (d Dispatcher) DispatchOnce() (int, error) {
+	rows, err := d.DB.Query(`
+		WITH claimed AS (
+			SELECT id FROM notification_outbox
+			WHERE status = $1 AND next_attempt_at <= NOW()
+			ORDER BY id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE notification_outbox o
+		SET next_attempt_at = NOW() + make_interval(secs => $4)
+		FROM claimed, users u
+		WHERE o.id = claimed.id AND o.user_id = u.id
+		RETURNING o.id, o.template, o.data, o.attempts, u.email, COALESCE(u.locale, $3)
+	`, StatusPending, d.batchSize(), DefaultLocale, claimLease.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	type outboxRow struct {
+		id       int64
+		template string
+		data     []byte
+		attempts int
+		email    string
+		locale   string
+	}
+	batch := []outboxRow{}
+	for rows.Next() {
+		var row outboxRow
+		if err := rows.Scan(&row.id, &row.template, &row.data, &row.attempts, &row.email, &row.locale); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	// RETURNING tidak menjamin urutan; notifikasi dikirim sesuai urutan masuk outbox
+	sort.Slice(batch, func(i, j int) bool { return batch[i].id < batch[j].id })
+
+	for i, row := range batch {
+		data := map[string]interface{}{}
+		json.Unmarshal(row.data, &data)
+
+		email, err := Render(row.template, row.locale, data)
+		if err == nil {
+			email.To = row.email
+			err = d.Notifier.Send(email)
+		}
+
+		if err == nil {
+			_, err = d.DB.Exec("UPDATE notification_outbox SET status = $1, attempts = attempts + 1, sent_at = NOW(), last_error = NULL WHERE id = $2",
+				StatusSent, row.id)
+		} else {
+			status := StatusPending
+			if row.attempts+1 >= MaxAttempts {
+				status = StatusFailed
+			}
+			_, err = d.DB.Exec(`
+				UPDATE notification_outbox
+				SET status = $1, attempts = attempts + 1, last_error = $2, next_attempt_at = NOW() + make_interval(secs => $3)
+				WHERE id = $4
+			`, status, err.Error(), Backoff(row.attempts+1).Seconds(), row.id)
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(batch), nil
+}
+
+func (d Dispatcher) batchSize() int {
+	if d.BatchSize <= 0 {
+		return 50
+	}
+	return d.BatchSize
+}
+
+// Backoff jeda sebelum percobaan berikutnya: 30 detik dikali dua setiap gagal, maksimal 1 jam
+func Backoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Nama template notifikasi
+const (
+	TemplateBookingConfirmed  = "booking_confirmed"
+	TemplateBookingCancelled  = "booking_cancelled"
+	TemplateScheduleChanged   = "schedule_changed"
+	TemplateScheduleCancelled = "schedule_cancelled"
+	TemplateWaitlistOffer     = "waitlist_offer"
+)
+
+// ErrUnknownTemplate template atau bahasa tidak terdaftar
+var ErrUnknownTemplate = errors.New("unknown notification template")
+
+// message subject dan body satu template dalam satu bahasa
+type message struct {
+	subject string
+	body    string
+}
+
+// templates isi notifikasi per template per bahasa. Data yang tersedia ditulis oleh
+// fungsi Enqueue* di booking.go; waktu sudah dalam timezone cinema.
+var templates = map[string]map[string]message{
+	TemplateBookingConfirmed: {
+		LocaleID: {
+			subject: "Pembayaran berhasil - {{.movie_title}}",
+			body: `Halo {{.fullname}},
+
+Pembayaran booking #{{.booking_id}} sudah kami terima.
+
+Film   : {{.movie_title}}
+Bioskop: {{.cinema_name}}, {{.studio_name}}
+Jadwal : {{.start_time_local}} ({{.timezone}})
+Kursi  : {{.seats}}
+Total  : Rp {{.total_amount}}
+
+Sampai jumpa di bioskop!`,
+		},
+		LocaleEN: {
+			subject: "Payment received - {{.movie_title}}",
+			body: `Hi {{.fullname}},
+
+We have received the payment for booking #{{.booking_id}}.
+
+Movie  : {{.movie_title}}
+Cinema : {{.cinema_name}}, {{.studio_name}}
+Time   : {{.start_time_local}} ({{.timezone}})
+Seats  : {{.seats}}
+Total  : IDR {{.total_amount}}
+
+See you at the cinema!`,
+		},
+	},
+	TemplateBookingCancelled: {
+		LocaleID: {
+			subject: "Booking #{{.booking_id}} dibatalkan",
+			body: `Halo {{.fullname}},
+
+Booking #{{.booking_id}} untuk {{.movie_title}} pada {{.start_time_local}} ({{.timezone}}) sudah dibatalkan.
+{{if eq .status "REFUNDED"}}Dana sebesar Rp {{.total_amount}} akan dikembalikan ke metode pembayaran Anda.{{else}}Kursi {{.seats}} sudah dilepas.{{end}}`,
+		},
+		LocaleEN: {
+			subject: "Booking #{{.booking_id}} cancelled",
+			body: `Hi {{.fullname}},
+
+Booking #{{.booking_id}} for {{.movie_title}} on {{.start_time_local}} ({{.timezone}}) has been cancelled.
+{{if eq .status "REFUNDED"}}IDR {{.total_amount}} will be refunded to your payment method.{{else}}Seats {{.seats}} have been released.{{end}}`,
+		},
+	},
+	TemplateScheduleChanged: {
+		LocaleID: {
+			subject: "Perubahan jadwal {{.movie_title}}",
+			body: `Halo {{.fullname}},
+
+Jadwal untuk booking #{{.booking_id}} berubah. Jadwal terbaru:
+
+Film   : {{.movie_title}}
+Bioskop: {{.cinema_name}}, {{.studio_name}}
+Jadwal : {{.start_time_local}} ({{.timezone}})
+Kursi  : {{.seats}}`,
+		},
+		LocaleEN: {
+			subject: "Showtime changed - {{.movie_title}}",
+			body: `Hi {{.fullname}},
+
+The showtime for booking #{{.booking_id}} has changed. Updated details:
+
+Movie  : {{.movie_title}}
+Cinema : {{.cinema_name}}, {{.studio_name}}
+Time   : {{.start_time_local}} ({{.timezone}})
+Seats  : {{.seats}}`,
+		},
+	},
+	TemplateScheduleCancelled: {
+		LocaleID: {
+			subject: "Jadwal {{.movie_title}} dibatalkan",
+			body: `Halo {{.fullname}},
+
+Mohon maaf, penayangan {{.movie_title}} di {{.cinema_name}} pada {{.start_time_local}} ({{.timezone}}) dibatalkan.
+Silakan batalkan booking #{{.booking_id}} untuk mendapatkan pengembalian dana penuh.`,
+		},
+		LocaleEN: {
+			subject: "Showtime cancelled - {{.movie_title}}",
+			body: `Hi {{.fullname}},
+
+We are sorry, the showing of {{.movie_title}} at {{.cinema_name}} on {{.start_time_local}} ({{.timezone}}) has been cancelled.
+Please cancel booking #{{.booking_id}} to receive a full refund.`,
+		},
+	},
+	TemplateWaitlistOffer: {
+		LocaleID: {
+			subject: "Kursi tersedia untuk {{.movie_title}}",
+			body: `Halo {{.fullname}},
+
+{{.seats}} kursi untuk {{.movie_title}} di {{.cinema_name}} pada {{.start_time_local}} ({{.timezone}}) sekarang tersedia untuk Anda.
+Segera booking sebelum {{.offer_expires_at_local}}; setelah itu kursi ditawarkan ke antrian berikutnya.`,
+		},
+		LocaleEN: {
+			subject: "Seats available for {{.movie_title}}",
+			body: `Hi {{.fullname}},
+
+{{.seats}} seats for {{.movie_title}} at {{.cinema_name}} on {{.start_time_local}} ({{.timezone}}) are now available for you.
+Book them before {{.offer_expires_at_local}}; after that they will be offered to the next person in line.`,
+		},
+	},
+}
+
+// Render mengisi template dalam bahasa user; bahasa yang tidak dikenal memakai DefaultLocale
+func Render(name, locale string, data map[string]interface{}) (Email, error) {
+	locales, ok := templates[name]
+	if !ok {
+		return Email{}, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	msg, ok := locales[strings.ToLower(locale)]
+	if !ok {
+		msg = locales[DefaultLocale]
+	}
+
+	subject, err := execute(msg.subject, data)
+	if err != nil {
+		return Email{}, err
+	}
+	body, err := execute(msg.body, data)
+	if err != nil {
+		return Email{}, err
+	}
+	return Email{Subject: subject, Body: body}, nil
+}
+
+func execute(text string, data map[string]interface{}) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,8 @@
+package openapi
+
+import _ "embed"
+
+// DocsHTML halaman referensi API (Redoc) yang membaca /openapi.json
+//
+//go:embed docs.html
+var DocsHTML []byte
@@ -0,0 +1,322 @@
+// Package openapi membangun spesifikasi OpenAPI 3.1 API dari tabel operasi di operations.go.
+// Schema body diturunkan dari struct models lewat reflection (tag json dan validate),
+// sehingga perubahan model langsung terlihat di spesifikasi.
+package openapi
+
+import (
+	"fmt"
+	"mkp/apierror"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version versi OpenAPI yang dipakai dokumen
+const Version = "3.1.0"
+
+// Document dokumen OpenAPI
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers"`
+	Tags       []Tag               `json:"tags"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info metadata API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server base URL API
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag kelompok operasi
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem operasi per HTTP method (huruf kecil) pada satu path
+type PathItem map[string]*Operation
+
+// Operation satu endpoint
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter parameter path, query atau header
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody body request
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response satu status response
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header header response
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType schema untuk satu content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components schema dan security scheme bersama
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme cara autentikasi
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Level autentikasi operasi, sesuai middleware yang dipasang di main.go
+const (
+	authNone = iota
+	authOptional
+	authBearer
+	authAdmin
+)
+
+// bearerScheme nama security scheme JWT
+const bearerScheme = "bearerAuth"
+
+// operation deklarasi satu endpoint. Pattern adalah pattern http.HandleFunc yang melayaninya,
+// dipakai Check untuk memastikan setiap route punya entry di spesifikasi.
+type operation struct {
+	Pattern        string
+	Method         string
+	Path           string
+	Tag            string
+	Summary        string
+	Description    string
+	Auth           int
+	Params         []Parameter
+	Request        interface{}
+	RequestTypes   []string // default application/json
+	Status         int
+	Response       interface{}
+	ContentType    string // default application/json
+	AltContentType string // response alternatif berupa teks, misal text/csv lewat ?format=csv
+	Headers        map[string]Header
+	Errors         []int
+}
+
+// Spec membangun dokumen OpenAPI dari semua operasi
+func Spec() *Document {
+	reg := newRegistry()
+	reg.name(apierror.Problem{}, "Problem")
+	for _, named := range namedSchemas {
+		reg.name(named.value, named.name)
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   "MKP Cinema API",
+			Version: "1.0",
+			Description: "Errors use RFC 7807 problem+json with a stable `code`; validation errors list " +
+				"per-field `errors`. Every response carries an `X-Request-ID` header.",
+		},
+		Servers: []Server{{URL: "/"}},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	seenTags := map[string]bool{}
+	for _, op := range operations {
+		if !seenTags[op.Tag] {
+			seenTags[op.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: op.Tag})
+		}
+		if doc.Paths[op.Path] == nil {
+			doc.Paths[op.Path] = PathItem{}
+		}
+		doc.Paths[op.Path][strings.ToLower(op.Method)] = op.build(reg)
+	}
+
+	doc.Components.Schemas = reg.schemas
+	return doc
+}
+
+func (op operation) build(reg *registry) *Operation {
+	built := &Operation{
+		OperationID: operationID(op.Method, op.Path),
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        []string{op.Tag},
+		Parameters:  append(pathParams(op.Path), op.Params...),
+		Responses:   map[string]Response{},
+	}
+
+	switch op.Auth {
+	case authOptional:
+		built.Security = []map[string][]string{{}, {bearerScheme: {}}}
+	case authBearer, authAdmin:
+		built.Security = []map[string][]string{{bearerScheme: {}}}
+	}
+	if op.Auth == authAdmin {
+		built.Description = strings.TrimSpace(built.Description + "\n\nRequires the ADMIN role.")
+	}
+
+	if op.Request != nil {
+		requestTypes := op.RequestTypes
+		if len(requestTypes) == 0 {
+			requestTypes = []string{"application/json"}
+		}
+		schema := reg.ref(op.Request)
+		built.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{}}
+		for _, requestType := range requestTypes {
+			built.RequestBody.Content[requestType] = MediaType{Schema: schema}
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	contentType := op.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	success := Response{Description: http.StatusText(status), Headers: op.Headers}
+	if op.Response != nil {
+		success.Content = map[string]MediaType{contentType: {Schema: reg.ref(op.Response)}}
+	}
+	if op.AltContentType != "" {
+		success.Content[op.AltContentType] = MediaType{Schema: &Schema{Type: "string"}}
+	}
+	built.Responses[strconv.Itoa(status)] = success
+
+	errorStatuses := append([]int{}, op.Errors...)
+	if op.Request != nil {
+		errorStatuses = append(errorStatuses, http.StatusBadRequest)
+	}
+	switch op.Auth {
+	case authBearer:
+		errorStatuses = append(errorStatuses, http.StatusUnauthorized)
+	case authAdmin:
+		errorStatuses = append(errorStatuses, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	errorStatuses = append(errorStatuses, http.StatusInternalServerError)
+	problem := map[string]MediaType{apierror.ContentType: {Schema: reg.ref(apierror.Problem{})}}
+	for _, code := range errorStatuses {
+		built.Responses[strconv.Itoa(code)] = Response{Description: http.StatusText(code), Content: problem}
+	}
+	return built
+}
+
+// pathParams parameter untuk setiap {name} di path. Nama yang berakhiran id bertipe integer.
+func pathParams(path string) []Parameter {
+	params := []Parameter{}
+	for _, segment := range strings.Split(path, "/") {
+		start := strings.Index(segment, "{")
+		end := strings.Index(segment, "}")
+		if start < 0 || end < start {
+			continue
+		}
+		name := segment[start+1 : end]
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "_id") {
+			schema = &Schema{Type: "integer", Format: "int32", Minimum: float(1)}
+		}
+		if enum, ok := pathEnums[name]; ok {
+			for _, value := range enum() {
+				schema.Enum = append(schema.Enum, value)
+			}
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	return params
+}
+
+// operationID nama unik operasi, misal GET /api/schedules/{id}/price-preview menjadi getSchedulesIdPricePreview
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '_' || r == '.'
+	}) {
+		if part == "api" {
+			continue
+		}
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	if id == strings.ToLower(method) {
+		id += "Root"
+	}
+	return id
+}
+
+// Check membandingkan pattern yang didaftarkan ke ServeMux dengan tabel operasi dan
+// mengembalikan daftar masalah: route tanpa entry spesifikasi, atau entry untuk route yang
+// tidak ada lagi. Kosong berarti spesifikasi lengkap.
+func Check(patterns []string) []string {
+	registered := map[string]bool{}
+	for _, pattern := range patterns {
+		registered[pattern] = true
+	}
+	documented := map[string]bool{}
+	for _, op := range operations {
+		documented[op.Pattern] = true
+	}
+
+	problems := []string{}
+	for pattern := range registered {
+		if !documented[pattern] {
+			problems = append(problems, fmt.Sprintf("route %s has no OpenAPI operation", pattern))
+		}
+	}
+	for pattern := range documented {
+		if !registered[pattern] {
+			problems = append(problems, fmt.Sprintf("OpenAPI operation uses unregistered route %s", pattern))
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, op := range operations {
+		key := op.Method + " " + op.Path
+		if seen[key] {
+			problems = append(problems, fmt.Sprintf("duplicate OpenAPI operation %s", key))
+		}
+		seen[key] = true
+	}
+	sort.Strings(problems)
+	return problems
+}
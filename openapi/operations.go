@@ -0,0 +1,568 @@
+package openapi
+
+import (
+	"mkp/apierror"
+	"mkp/audit"
+	"mkp/importer"
+	"mkp/models"
+	"mkp/report"
+	"mkp/seatstream"
+	"net/http"
+	"strconv"
+)
+
+// namedSchemas nama component untuk struct di luar package models
+var namedSchemas = []struct {
+	value interface{}
+	name  string
+}{
+	{apierror.FieldError{}, "FieldError"},
+	{audit.Entry{}, "AuditEntry"},
+	{audit.Change{}, "AuditChange"},
+	{importer.Result{}, "ImportResult"},
+	{importer.RowError{}, "ImportRowError"},
+	{seatstream.Change{}, "SeatChange"},
+}
+
+// pathEnums nilai yang diizinkan untuk parameter path selain ID
+var pathEnums = map[string]func() []string{
+	"report": func() []string { return []string{"revenue", "occupancy", "top-movies", "refunds"} },
+	"entity": importer.Entities,
+}
+
+// Schema response yang tidak punya struct sendiri di models
+var (
+	messageResponse = &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"message": {Type: "string"}},
+		Required:   []string{"message"},
+	}
+	createdResponse = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"message": {Type: "string"},
+			"id":      {Type: "integer", Format: "int32"},
+		},
+		Required: []string{"message", "id"},
+	}
+	calendarFeedResponse = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"url":        {Type: "string", Format: "uri"},
+			"webcal_url": {Type: "string", Format: "uri"},
+		},
+		Required: []string{"url", "webcal_url"},
+	}
+	rootResponse = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"message": {Type: "string"},
+			"version": {Type: "string"},
+		},
+	}
+	textSchema   = &Schema{Type: "string"}
+	binarySchema = &Schema{Type: "string", Format: "binary"}
+)
+
+// reportResponse bungkus laporan JSON; isi rows tergantung jenis laporan
+func reportResponse(reg *registry) *Schema {
+	rows := &Schema{OneOf: []*Schema{
+		{Type: "array", Items: reg.ref(report.RevenueRow{})},
+		{Type: "array", Items: reg.ref(report.OccupancyRow{})},
+		{Type: "array", Items: reg.ref(report.TopMovieRow{})},
+		{Type: "array", Items: reg.ref(report.RefundRow{})},
+	}}
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"report":   {Type: "string"},
+			"from":     {Type: "string", Format: "date"},
+			"to":       {Type: "string", Format: "date"},
+			"group_by": {Type: "string", Enum: []interface{}{report.GroupByDay, report.GroupByCinema, report.GroupByMovie}},
+			"rows":     rows,
+		},
+		Required: []string{"report", "from", "to", "rows"},
+	}
+}
+
+// seatStream body stream kursi; schema data event ikut didaftarkan ke components
+func seatStream(reg *registry) *Schema {
+	reg.schemas["SeatSnapshot"] = seatSnapshotEvent
+	reg.ref(seatstream.Change{})
+	return &Schema{
+		Type:        "string",
+		Description: "text/event-stream; `snapshot` data is a SeatSnapshot, `held`/`released`/`sold` data is a SeatChange",
+	}
+}
+
+// seatSnapshotEvent data event "snapshot" pada stream kursi
+var seatSnapshotEvent = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"schedule_id": {Type: "integer", Format: "int32"},
+		"held":        {Type: "array", Items: &Schema{Type: "integer", Format: "int32"}},
+		"sold":        {Type: "array", Items: &Schema{Type: "integer", Format: "int32"}},
+	},
+	Required: []string{"schedule_id", "held", "sold"},
+}
+
+// Parameter yang dipakai beberapa operasi
+var (
+	idempotencyKeyHeader = Parameter{
+		Name: "Idempotency-Key", In: "header",
+		Description: "Optional key (max 255 chars); retries with the same key and body replay the first response",
+		Schema:      &Schema{Type: "string", MaxLength: intPtr(255)},
+	}
+	ifMatchHeader = Parameter{
+		Name: "If-Match", In: "header", Required: true,
+		Description: "ETag from GET /api/schedules/{id}",
+		Schema:      &Schema{Type: "string"},
+	}
+	formatParam    = query("format", "csv to download CSV instead of JSON (also via Accept: text/csv)", enum("json", "csv"))
+	includeDeleted = query("include_deleted", "Admin only: include soft-deleted schedules", &Schema{Type: "boolean"})
+	cityParam      = query("city", "Case-insensitive city name", textSchema)
+	beforeIDParam  = query("before_id", "Cursor: id of the last entry of the previous page", positiveInt())
+	dryRunParam    = query("dry_run", "Validate only, nothing is written", &Schema{Type: "boolean"})
+	etagHeader     = map[string]Header{"ETag": {Description: "Schedule version, send back as If-Match", Schema: textSchema}}
+)
+
+func query(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func enum(values ...string) *Schema {
+	schema := &Schema{Type: "string"}
+	for _, value := range values {
+		schema.Enum = append(schema.Enum, value)
+	}
+	return schema
+}
+
+func positiveInt() *Schema {
+	return &Schema{Type: "integer", Format: "int32", Minimum: float(1)}
+}
+
+func limitParam(defaultLimit, maxLimit int) Parameter {
+	return query("limit", "", &Schema{Type: "integer", Format: "int32", Minimum: float(1), Maximum: float(maxLimit),
+		Description: "Default " + strconv.Itoa(defaultLimit)})
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// operations semua endpoint API. Setiap route yang didaftarkan di main.go harus punya minimal
+// satu operasi dengan Pattern yang sama; go run . openapi -check gagal jika belum.
+var operations = []operation{
+	// Auth
+	{
+		Pattern: "/api/register", Method: http.MethodPost, Path: "/api/register", Tag: "Auth",
+		Summary: "Register a new customer account",
+		Request: models.RegisterRequest{}, Status: http.StatusCreated, Response: models.LoginResponse{},
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Pattern: "/api/login", Method: http.MethodPost, Path: "/api/login", Tag: "Auth",
+		Summary: "Log in and receive a JWT",
+		Request: models.LoginRequest{}, Response: models.LoginResponse{},
+		Errors: []int{http.StatusUnauthorized},
+	},
+
+	// Catalogue
+	{
+		Pattern: "/api/movies", Method: http.MethodGet, Path: "/api/movies", Tag: "Catalogue",
+		Summary: "List movies", Auth: authOptional,
+		Description: "With city, only movies with upcoming SHOWING schedules in that city.",
+		Params:      []Parameter{cityParam},
+		Response:    []models.Movie{},
+	},
+	{
+		Pattern: "/api/movies/search", Method: http.MethodGet, Path: "/api/movies/search", Tag: "Catalogue",
+		Summary: "Search movies by title and description", Auth: authOptional,
+		Description: "Combines full-text search with trigram similarity, so typos still match.",
+		Params: []Parameter{
+			{Name: "q", In: "query", Required: true, Schema: &Schema{Type: "string", MinLength: intPtr(2)}},
+			cityParam,
+			limitParam(20, 50),
+		},
+		Response: []models.MovieSearchResult{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+	{
+		Pattern: "/api/movies/", Method: http.MethodGet, Path: "/api/movies/{id}", Tag: "Catalogue",
+		Summary: "Get a movie", Auth: authOptional,
+		Response: models.Movie{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/cinemas", Method: http.MethodGet, Path: "/api/cinemas", Tag: "Catalogue",
+		Summary: "List cinemas", Auth: authOptional,
+		Params:   []Parameter{cityParam},
+		Response: []models.Cinema{},
+	},
+	{
+		Pattern: "/api/cinemas/", Method: http.MethodGet, Path: "/api/cinemas/{id}", Tag: "Catalogue",
+		Summary: "Get a cinema with its studios", Auth: authOptional,
+		Response: models.Cinema{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/cinemas/", Method: http.MethodGet, Path: "/api/cinemas/{id}/schedule.ics", Tag: "Catalogue",
+		Summary:  "iCalendar feed of upcoming schedules in a cinema",
+		Response: textSchema, ContentType: "text/calendar",
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/showtimes", Method: http.MethodGet, Path: "/api/showtimes", Tag: "Catalogue",
+		Summary: "List upcoming SHOWING schedules", Auth: authOptional,
+		Params: []Parameter{
+			query("date", "Local cinema date", &Schema{Type: "string", Format: "date"}),
+			query("cinema_id", "", positiveInt()),
+			query("movie_id", "", positiveInt()),
+			cityParam,
+		},
+		Response: []models.Schedule{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+
+	// Schedules
+	{
+		Pattern: "/api/schedules", Method: http.MethodGet, Path: "/api/schedules", Tag: "Schedules",
+		Summary: "List schedules", Auth: authBearer,
+		Params:   []Parameter{includeDeleted, formatParam},
+		Response: []models.Schedule{}, AltContentType: "text/csv",
+	},
+	{
+		Pattern: "/api/schedules/create", Method: http.MethodPost, Path: "/api/schedules/create", Tag: "Schedules",
+		Summary: "Create a schedule", Auth: authAdmin,
+		Description: "start_time and end_time without offset are interpreted in the cinema timezone.",
+		Params:      []Parameter{idempotencyKeyHeader},
+		Request:     models.ScheduleCreateRequest{}, Status: http.StatusCreated, Response: createdResponse,
+		Errors: []int{http.StatusConflict, http.StatusUnprocessableEntity},
+	},
+	{
+		Pattern: "/api/schedules/bulk", Method: http.MethodPost, Path: "/api/schedules/bulk", Tag: "Schedules",
+		Summary: "Create schedules for a date range and time slots", Auth: authAdmin,
+		Description: "All-or-nothing. With dry_run the generated schedules are returned with status 200 and nothing is written.",
+		Params:      []Parameter{dryRunParam},
+		Request:     models.ScheduleBulkRequest{}, Status: http.StatusCreated, Response: models.ScheduleBulkResult{},
+		Errors: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		Pattern: "/api/schedules/", Method: http.MethodGet, Path: "/api/schedules/{id}", Tag: "Schedules",
+		Summary: "Get a schedule", Auth: authBearer,
+		Description: "Supports If-None-Match with the returned ETag (304 Not Modified).",
+		Params:      []Parameter{includeDeleted},
+		Response:    models.Schedule{}, Headers: etagHeader,
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/schedules/", Method: http.MethodPut, Path: "/api/schedules/{id}", Tag: "Schedules",
+		Summary: "Replace a schedule", Auth: authAdmin,
+		Params:  []Parameter{ifMatchHeader},
+		Request: models.ScheduleCreateRequest{}, Response: models.Schedule{}, Headers: etagHeader,
+		Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusPreconditionFailed, http.StatusPreconditionRequired},
+	},
+	{
+		Pattern: "/api/schedules/", Method: http.MethodPatch, Path: "/api/schedules/{id}", Tag: "Schedules",
+		Summary: "Partially update a schedule with JSON Merge Patch", Auth: authAdmin,
+		Description: "Fields that are omitted stay unchanged, null removes a field. The merged schedule is validated like PUT.",
+		Params:      []Parameter{ifMatchHeader},
+		Request:     models.ScheduleCreateRequest{}, RequestTypes: []string{"application/merge-patch+json", "application/json"},
+		Response: models.Schedule{}, Headers: etagHeader,
+		Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusPreconditionFailed,
+			http.StatusPreconditionRequired, http.StatusUnsupportedMediaType},
+	},
+	{
+		Pattern: "/api/schedules/", Method: http.MethodDelete, Path: "/api/schedules/{id}", Tag: "Schedules",
+		Summary: "Soft delete a schedule", Auth: authAdmin,
+		Description: "Rejected while the schedule has PAID tickets. PENDING bookings are cancelled.",
+		Params:      []Parameter{ifMatchHeader},
+		Response:    messageResponse,
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict,
+			http.StatusPreconditionFailed, http.StatusPreconditionRequired},
+	},
+	{
+		Pattern: "/api/schedules/", Method: http.MethodPost, Path: "/api/schedules/{id}/restore", Tag: "Schedules",
+		Summary: "Restore a soft-deleted schedule", Auth: authAdmin,
+		Response: messageResponse,
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Pattern: "/api/schedules/", Method: http.MethodGet, Path: "/api/schedules/{id}/price-preview", Tag: "Schedules",
+		Summary: "Preview ticket prices for seats", Auth: authBearer,
+		Params: []Parameter{{
+			Name: "seat_ids", In: "query", Required: true, Description: "Comma-separated seat IDs, e.g. 10,11",
+			Schema: &Schema{Type: "string", Pattern: `^\d+(,\d+)*$`},
+		}},
+		Response: models.PriceQuote{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/schedules/", Method: http.MethodGet, Path: "/api/schedules/{id}/seats/stream", Tag: "Schedules",
+		Summary: "Stream seat availability (Server-Sent Events)", Auth: authOptional,
+		Description: "The first event `snapshot` lists held and sold seats. After that events `held`, `released` " +
+			"and `sold` carry a SeatChange. The server closes the stream when the client falls behind; reconnect " +
+			"to get a new snapshot.",
+		Response: seatStream, ContentType: "text/event-stream",
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	// Bookings
+	{
+		Pattern: "/api/bookings", Method: http.MethodGet, Path: "/api/bookings", Tag: "Bookings",
+		Summary: "List my bookings", Auth: authBearer,
+		Response: []models.Transaction{},
+	},
+	{
+		Pattern: "/api/bookings", Method: http.MethodPost, Path: "/api/bookings", Tag: "Bookings",
+		Summary: "Book seats", Auth: authBearer,
+		Description: "Creates a PENDING booking that holds the seats until it is paid or the hold expires.",
+		Params:      []Parameter{idempotencyKeyHeader},
+		Request:     models.BookingRequest{}, Status: http.StatusCreated, Response: models.Transaction{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	},
+	{
+		Pattern: "/api/bookings/", Method: http.MethodGet, Path: "/api/bookings/{id}", Tag: "Bookings",
+		Summary: "Get my booking", Auth: authBearer,
+		Response: models.Transaction{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/bookings/", Method: http.MethodPost, Path: "/api/bookings/{id}/pay", Tag: "Bookings",
+		Summary: "Pay a PENDING booking", Auth: authBearer,
+		Params:  []Parameter{idempotencyKeyHeader},
+		Request: models.PaymentRequest{}, Response: models.Transaction{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	},
+	{
+		Pattern: "/api/bookings/", Method: http.MethodPost, Path: "/api/bookings/{id}/cancel", Tag: "Bookings",
+		Summary: "Cancel a booking", Auth: authBearer,
+		Description: "PENDING bookings become CANCELLED, PAID bookings become REFUNDED. The seats are offered to the waitlist.",
+		Response:    models.Transaction{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	},
+
+	// Waitlist
+	{
+		Pattern: "/api/waitlist", Method: http.MethodGet, Path: "/api/waitlist", Tag: "Waitlist",
+		Summary: "List my active waitlist entries", Auth: authBearer,
+		Response: []models.WaitlistEntry{},
+	},
+	{
+		Pattern: "/api/waitlist", Method: http.MethodPost, Path: "/api/waitlist", Tag: "Waitlist",
+		Summary: "Join the waitlist of a full schedule", Auth: authBearer,
+		Request: models.WaitlistRequest{}, Status: http.StatusCreated, Response: models.WaitlistEntry{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Pattern: "/api/waitlist/", Method: http.MethodDelete, Path: "/api/waitlist/{id}", Tag: "Waitlist",
+		Summary: "Leave the waitlist", Auth: authBearer,
+		Response: messageResponse,
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	// Calendar
+	{
+		Pattern: "/api/calendar/feed", Method: http.MethodGet, Path: "/api/calendar/feed", Tag: "Calendar",
+		Summary: "Get my private ticket calendar URL", Auth: authBearer,
+		Response: calendarFeedResponse,
+	},
+	{
+		Pattern: "/api/calendar/feed", Method: http.MethodPost, Path: "/api/calendar/feed", Tag: "Calendar",
+		Summary: "Rotate my calendar token", Auth: authBearer,
+		Description: "The old feed URL stops working.",
+		Response:    calendarFeedResponse,
+	},
+	{
+		Pattern: "/api/calendar/", Method: http.MethodGet, Path: "/api/calendar/{token}.ics", Tag: "Calendar",
+		Summary:     "Private iCalendar feed of my tickets",
+		Description: "Authenticated by the token in the URL because calendar apps cannot send a bearer token.",
+		Response:    textSchema, ContentType: "text/calendar",
+		Errors: []int{http.StatusNotFound},
+	},
+
+	// Admin: pricing rules
+	{
+		Pattern: "/api/admin/pricing-rules", Method: http.MethodGet, Path: "/api/admin/pricing-rules", Tag: "Pricing",
+		Summary: "List pricing rules", Auth: authAdmin,
+		Response: []models.PricingRule{},
+	},
+	{
+		Pattern: "/api/admin/pricing-rules", Method: http.MethodPost, Path: "/api/admin/pricing-rules", Tag: "Pricing",
+		Summary: "Create a pricing rule", Auth: authAdmin,
+		Request: models.PricingRuleRequest{}, Status: http.StatusCreated, Response: createdResponse,
+	},
+	{
+		Pattern: "/api/admin/pricing-rules/", Method: http.MethodGet, Path: "/api/admin/pricing-rules/{id}", Tag: "Pricing",
+		Summary: "Get a pricing rule", Auth: authAdmin,
+		Response: models.PricingRule{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/admin/pricing-rules/", Method: http.MethodPut, Path: "/api/admin/pricing-rules/{id}", Tag: "Pricing",
+		Summary: "Replace a pricing rule", Auth: authAdmin,
+		Request: models.PricingRuleRequest{}, Response: messageResponse,
+		Errors: []int{http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/admin/pricing-rules/", Method: http.MethodDelete, Path: "/api/admin/pricing-rules/{id}", Tag: "Pricing",
+		Summary: "Delete a pricing rule", Auth: authAdmin,
+		Response: messageResponse,
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	// Admin: promotions
+	{
+		Pattern: "/api/admin/promotions", Method: http.MethodGet, Path: "/api/admin/promotions", Tag: "Promotions",
+		Summary: "List promotions", Auth: authAdmin,
+		Response: []models.Promotion{},
+	},
+	{
+		Pattern: "/api/admin/promotions", Method: http.MethodPost, Path: "/api/admin/promotions", Tag: "Promotions",
+		Summary: "Create a promotion", Auth: authAdmin,
+		Request: models.PromotionRequest{}, Status: http.StatusCreated, Response: createdResponse,
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Pattern: "/api/admin/promotions/", Method: http.MethodGet, Path: "/api/admin/promotions/{id}", Tag: "Promotions",
+		Summary: "Get a promotion", Auth: authAdmin,
+		Response: models.Promotion{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/admin/promotions/", Method: http.MethodPut, Path: "/api/admin/promotions/{id}", Tag: "Promotions",
+		Summary: "Replace a promotion", Auth: authAdmin,
+		Request: models.PromotionRequest{}, Response: messageResponse,
+		Errors: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Pattern: "/api/admin/promotions/", Method: http.MethodDelete, Path: "/api/admin/promotions/{id}", Tag: "Promotions",
+		Summary: "Delete a promotion", Auth: authAdmin,
+		Response: messageResponse,
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	// Admin: webhooks
+	{
+		Pattern: "/api/admin/webhooks", Method: http.MethodGet, Path: "/api/admin/webhooks", Tag: "Webhooks",
+		Summary: "List webhook subscriptions", Auth: authAdmin,
+		Response: []models.WebhookSubscription{},
+	},
+	{
+		Pattern: "/api/admin/webhooks", Method: http.MethodPost, Path: "/api/admin/webhooks", Tag: "Webhooks",
+		Summary: "Create a webhook subscription", Auth: authAdmin,
+		Description: "The signing secret is only returned in this response. Without secret one is generated.",
+		Request:     models.WebhookSubscriptionRequest{}, Status: http.StatusCreated, Response: models.WebhookSubscription{},
+	},
+	{
+		Pattern: "/api/admin/webhooks/", Method: http.MethodGet, Path: "/api/admin/webhooks/{id}", Tag: "Webhooks",
+		Summary: "Get a webhook subscription", Auth: authAdmin,
+		Response: models.WebhookSubscription{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/admin/webhooks/", Method: http.MethodPut, Path: "/api/admin/webhooks/{id}", Tag: "Webhooks",
+		Summary: "Replace a webhook subscription", Auth: authAdmin,
+		Description: "An empty secret keeps the current one.",
+		Request:     models.WebhookSubscriptionRequest{}, Response: models.WebhookSubscription{},
+		Errors: []int{http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/admin/webhooks/", Method: http.MethodDelete, Path: "/api/admin/webhooks/{id}", Tag: "Webhooks",
+		Summary: "Delete a webhook subscription and its deliveries", Auth: authAdmin,
+		Response: messageResponse,
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/admin/webhook-deliveries", Method: http.MethodGet, Path: "/api/admin/webhook-deliveries", Tag: "Webhooks",
+		Summary: "List webhook deliveries, newest first", Auth: authAdmin,
+		Params: []Parameter{
+			query("event_type", "", enum(models.WebhookEventTypes...)),
+			query("event_id", "", textSchema),
+			query("status", "", enum(models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryDead)),
+			query("subscription_id", "", positiveInt()),
+			beforeIDParam,
+			limitParam(50, 200),
+		},
+		Response: []models.WebhookDelivery{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+	{
+		Pattern: "/api/admin/webhook-deliveries/", Method: http.MethodGet, Path: "/api/admin/webhook-deliveries/{id}", Tag: "Webhooks",
+		Summary: "Get a webhook delivery", Auth: authAdmin,
+		Response: models.WebhookDelivery{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/admin/webhook-deliveries/", Method: http.MethodPost, Path: "/api/admin/webhook-deliveries/{id}/redeliver", Tag: "Webhooks",
+		Summary: "Queue a new delivery with the same payload", Auth: authAdmin,
+		Status: http.StatusAccepted, Response: models.WebhookDelivery{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	// Admin: audit, reports, import
+	{
+		Pattern: "/api/admin/audit-log", Method: http.MethodGet, Path: "/api/admin/audit-log", Tag: "Admin",
+		Summary: "List audit log entries, newest first", Auth: authAdmin,
+		Params: []Parameter{
+			query("entity_type", "Table name, e.g. schedules", textSchema),
+			query("entity_id", "", positiveInt()),
+			query("actor_id", "", positiveInt()),
+			query("action", "", textSchema),
+			query("request_id", "", textSchema),
+			query("from", "RFC 3339 or YYYY-MM-DD", textSchema),
+			query("to", "RFC 3339 or YYYY-MM-DD", textSchema),
+			beforeIDParam,
+			limitParam(50, 200),
+		},
+		Response: []audit.Entry{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+	{
+		Pattern: "/api/admin/reports/", Method: http.MethodGet, Path: "/api/admin/reports/{report}", Tag: "Admin",
+		Summary: "Sales reports", Auth: authAdmin,
+		Description: "Without from/to the report covers the last 30 days. group_by applies to revenue and refunds, " +
+			"sort and limit to top-movies.",
+		Params: []Parameter{
+			query("from", "Local show date", &Schema{Type: "string", Format: "date"}),
+			query("to", "Local show date", &Schema{Type: "string", Format: "date"}),
+			query("cinema_id", "", positiveInt()),
+			query("movie_id", "", positiveInt()),
+			query("group_by", "", enum(report.GroupByDay, report.GroupByCinema, report.GroupByMovie)),
+			query("sort", "", enum(report.SortByRevenue, report.SortByTickets)),
+			limitParam(10, 100),
+			formatParam,
+		},
+		Response: reportResponse, AltContentType: "text/csv",
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Pattern: "/api/admin/import/", Method: http.MethodPost, Path: "/api/admin/import/{entity}", Tag: "Admin",
+		Summary: "Import movies, cinemas, studios or schedules from CSV or JSON", Auth: authAdmin,
+		Description: "All rows are upserted in one transaction; if any row is invalid nothing is written (422). " +
+			"The format comes from ?format= or Content-Type.",
+		Params:  []Parameter{dryRunParam, query("format", "", enum("csv", "json"))},
+		Request: binarySchema, RequestTypes: []string{"text/csv", "application/json"},
+		Response: importer.Result{},
+		Errors:   []int{http.StatusNotFound, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity},
+	},
+
+	// Lain-lain
+	{
+		Pattern: "/openapi.json", Method: http.MethodGet, Path: "/openapi.json", Tag: "Meta",
+		Summary:  "This OpenAPI document",
+		Response: &Schema{Type: "object"},
+	},
+	{
+		Pattern: "/docs", Method: http.MethodGet, Path: "/docs", Tag: "Meta",
+		Summary:  "API reference page",
+		Response: textSchema, ContentType: "text/html",
+	},
+	{
+		Pattern: "/", Method: http.MethodGet, Path: "/", Tag: "Meta",
+		Summary:  "API welcome message",
+		Response: rootResponse,
+		Errors:   []int{http.StatusNotFound},
+	},
+}
@@ -0,0 +1,251 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema JSON Schema (dialek OpenAPI 3.1) untuk body request/response
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+// registry mengumpulkan schema struct bernama ke components/schemas
+type registry struct {
+	names   map[reflect.Type]string
+	schemas map[string]*Schema
+}
+
+func newRegistry() *registry {
+	return &registry{names: map[reflect.Type]string{}, schemas: map[string]*Schema{}}
+}
+
+// name memberi nama component untuk struct dari package selain models agar tidak bentrok,
+// misal audit.Change dan seatstream.Change. Harus dipanggil sebelum struct pertama kali dipakai.
+func (reg *registry) name(v interface{}, name string) {
+	reg.names[reflect.TypeOf(v)] = name
+}
+
+// ref mengembalikan schema untuk nilai Go; struct bernama menjadi $ref ke components.
+// Nilai juga boleh berupa *Schema atau func(*registry) *Schema untuk schema yang ditulis manual.
+func (reg *registry) ref(v interface{}) *Schema {
+	if v == nil {
+		return &Schema{}
+	}
+	if schema, ok := v.(*Schema); ok {
+		return schema
+	}
+	if build, ok := v.(func(*registry) *Schema); ok {
+		return build(reg)
+	}
+	return reg.typeSchema(reflect.TypeOf(v))
+}
+
+func (reg *registry) typeSchema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawJSONType:
+		// jsonb apa adanya, termasuk null
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(reg.typeSchema(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: reg.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reg.typeSchema(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return reg.structSchema(t)
+		}
+		name, ok := reg.names[t]
+		if !ok {
+			name = t.Name()
+			for other, otherName := range reg.names {
+				if otherName == name && other != t {
+					panic(fmt.Sprintf("openapi: schema name %s used by %s and %s", name, other, t))
+				}
+			}
+			reg.names[t] = name
+		}
+		if _, ok := reg.schemas[name]; !ok {
+			// placeholder dulu agar struct yang saling mereferensi tidak berulang tanpa henti
+			reg.schemas[name] = &Schema{}
+			*reg.schemas[name] = *reg.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	panic(fmt.Sprintf("openapi: unsupported type %s", t))
+}
+
+// structSchema membuat schema object dari field ber-tag json. Untuk model request (punya tag
+// validate) field wajib diambil dari rule required; untuk model response semua field tanpa
+// omitempty dianggap selalu ada.
+func (reg *registry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	isRequest := hasValidateTag(t)
+	reg.addFields(schema, t, isRequest)
+	return schema
+}
+
+func (reg *registry) addFields(schema *Schema, t reflect.Type, isRequest bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			// field embedded digabung seperti encoding/json
+			reg.addFields(schema, sf.Type, isRequest)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		rules := strings.Split(sf.Tag.Get("validate"), ",")
+		property := reg.typeSchema(sf.Type)
+		applyRules(property, sf.Type, rules)
+		schema.Properties[name] = property
+
+		required := !strings.Contains(opts, "omitempty")
+		if isRequest {
+			required = hasRule(rules, "required")
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// applyRules menerjemahkan tag validate ke keyword JSON Schema
+func applyRules(schema *Schema, t reflect.Type, rules []string) {
+	isNullable := t.Kind() == reflect.Ptr
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	isString := t.Kind() == reflect.String
+
+	for _, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch {
+			case isString && key == "min":
+				schema.MinLength = &n
+			case isString:
+				schema.MaxLength = &n
+			case key == "min":
+				schema.Minimum = float(n)
+			default:
+				schema.Maximum = float(n)
+			}
+		case "gt":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				schema.ExclusiveMinimum = &n
+			}
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "date":
+			schema.Format = "date"
+		case "datetime":
+			schema.Description = "RFC 3339 or local time YYYY-MM-DD HH:MM:SS"
+		case "clock":
+			schema.Pattern = `^([01]\d|2[0-3]):[0-5]\d$`
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, option)
+			}
+			if isNullable {
+				schema.Enum = append(schema.Enum, nil)
+			}
+		}
+	}
+}
+
+// nullable menambahkan null ke tipe schema (OpenAPI 3.1 memakai type array, bukan nullable)
+func nullable(schema *Schema) *Schema {
+	switch typ := schema.Type.(type) {
+	case string:
+		schema.Type = []string{typ, "null"}
+		return schema
+	case nil:
+		if schema.Ref == "" {
+			return schema
+		}
+	}
+	return &Schema{OneOf: []*Schema{schema, {Type: "null"}}}
+}
+
+func hasValidateTag(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("validate") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRule(rules []string, name string) bool {
+	for _, rule := range rules {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+func float(n int) *float64 {
+	f := float64(n)
+	return &f
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mkp/openapi"
+	"os"
+)
+
+// runOpenAPI menulis spesifikasi OpenAPI ke stdout. Dengan -check hanya memastikan setiap route
+// di setupRoutes punya entry di spesifikasi (dan sebaliknya); exit code 1 jika ada yang kurang,
+// dipakai di CI agar route baru tidak lupa didokumentasikan.
+func runOpenAPI(args []string) int {
+	fs := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	check := fs.Bool("check", false, "cek kelengkapan spesifikasi terhadap route yang terdaftar")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *check {
+		setupRoutes()
+		problems := openapi.Check(routePatterns)
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem)
+		}
+		if len(problems) > 0 {
+			return 1
+		}
+		fmt.Printf("OpenAPI spec covers all %d routes\n", len(routePatterns))
+		return 0
+	}
+
+	out, err := json.MarshalIndent(openapi.Spec(), "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(out))
+	return 0
+}
@@ -0,0 +1,97 @@
+// Package overlap mencari jadwal tayang yang bentrok di studio yang sama, dipakai bulk create
+// jadwal dan import agar keduanya menerapkan aturan yang sama.
+package overlap
+
+import (
+	"database/sql"
+	"mkp/models"
+	"sort"
+	"time"
+)
+
+// Slot jadwal baru yang akan ditulis
+type Slot struct {
+	StudioID   int
+	Start, End time.Time
+}
+
+// Conflict satu slot yang bentrok. WithSlot index slot lain di daftar yang sama (-1 jika
+// bentrok dengan jadwal tersimpan), ScheduleID jadwal tersimpan yang bentrok (0 jika bentrok
+// di dalam daftar).
+type Conflict struct {
+	Slot       int
+	WithSlot   int
+	ScheduleID int
+}
+
+// Find mencari slot yang bentrok dengan slot lain di daftar yang sama dan dengan jadwal
+// tersimpan yang belum dihapus dan tidak CANCELLED. Slot bisa punya dua conflict (di daftar dan
+// di database). Caller sebaiknya sudah mengunci baris studio agar penulisan paralel tidak saling lolos.
+func Find(tx *sql.Tx, slots []Slot) ([]Conflict, error) {
+	byStudio := map[int][]int{}
+	for i, slot := range slots {
+		byStudio[slot.StudioID] = append(byStudio[slot.StudioID], i)
+	}
+
+	studioIDs := make([]int, 0, len(byStudio))
+	for studioID := range byStudio {
+		studioIDs = append(studioIDs, studioID)
+	}
+	sort.Ints(studioIDs)
+
+	conflicts := []Conflict{}
+	for _, studioID := range studioIDs {
+		indexes := byStudio[studioID]
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return slots[indexes[a]].Start.Before(slots[indexes[b]].Start)
+		})
+
+		// Bentrok di dalam daftar: setelah diurutkan, bandingkan dengan slot yang selesai paling akhir
+		latest := indexes[0]
+		for _, i := range indexes[1:] {
+			if slots[i].Start.Before(slots[latest].End) {
+				conflicts = append(conflicts, Conflict{Slot: i, WithSlot: latest})
+			}
+			if slots[i].End.After(slots[latest].End) {
+				latest = i
+			}
+		}
+
+		rows, err := tx.Query(`
+			SELECT id, start_time, end_time
+			FROM schedules
+			WHERE studio_id = $1 AND deleted_at IS NULL AND status <> $2 AND start_time < $3 AND end_time > $4
+		`, studioID, models.ScheduleStatusCancelled, slots[latest].End, slots[indexes[0]].Start)
+		if err != nil {
+			return nil, err
+		}
+
+		type existingSchedule struct {
+			id         int
+			start, end time.Time
+		}
+		existing := []existingSchedule{}
+		for rows.Next() {
+			var e existingSchedule
+			if err := rows.Scan(&e.id, &e.start, &e.end); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			existing = append(existing, e)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, i := range indexes {
+			for _, e := range existing {
+				if slots[i].Start.Before(e.end) && slots[i].End.After(e.start) {
+					conflicts = append(conflicts, Conflict{Slot: i, WithSlot: -1, ScheduleID: e.id})
+					break
+				}
+			}
+		}
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"mkp/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// tokenRefreshMargin token diperbarui jika sisa umurnya kurang dari ini
+const tokenRefreshMargin = time.Minute
+
+// Register mendaftarkan user baru dan menyimpan token serta kredensialnya untuk login ulang
+func (c *Client) Register(ctx context.Context, req models.RegisterRequest) (*models.LoginResponse, error) {
+	call := newRequest(http.MethodPost, "/api/register")
+	call.auth = false
+	call.body = req
+
+	var resp models.LoginResponse
+	if err := c.do(ctx, call, &resp); err != nil {
+		return nil, err
+	}
+	c.setSession(resp.Token, req.Email, req.Password)
+	return &resp, nil
+}
+
+// Login login dan menyimpan token. Kredensial disimpan di memori agar client bisa login ulang
+// saat token kedaluwarsa.
+func (c *Client) Login(ctx context.Context, email, password string) (*models.LoginResponse, error) {
+	resp, err := c.login(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	c.setSession(resp.Token, email, password)
+	return resp, nil
+}
+
+// SetToken memakai token yang sudah ada, misal token service account. Tanpa kredensial
+// client tidak bisa login ulang saat token kedaluwarsa.
+func (c *Client) SetToken(token string) {
+	c.setSession(token, "", "")
+}
+
+// Token token yang sedang dipakai
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// Logout menghapus token dan kredensial dari client
+func (c *Client) Logout() {
+	c.setSession("", "", "")
+}
+
+func (c *Client) login(ctx context.Context, email, password string) (*models.LoginResponse, error) {
+	call := newRequest(http.MethodPost, "/api/login")
+	call.auth = false
+	call.body = models.LoginRequest{Email: email, Password: password}
+
+	var resp models.LoginResponse
+	if err := c.do(ctx, call, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) setSession(token, email, password string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.tokenExpiry = tokenExpiry(token)
+	c.email = email
+	c.password = password
+}
+
+func (c *Client) hasCredentials() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.email != ""
+}
+
+// validToken token untuk header Authorization, login ulang lebih dulu jika hampir kedaluwarsa
+func (c *Client) validToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token, expiry, canRelogin := c.token, c.tokenExpiry, c.email != ""
+	c.mu.Unlock()
+
+	if canRelogin && (token == "" || (!expiry.IsZero() && time.Until(expiry) < tokenRefreshMargin)) {
+		if err := c.relogin(ctx); err != nil {
+			return "", err
+		}
+		return c.Token(), nil
+	}
+	return token, nil
+}
+
+// relogin login ulang dengan kredensial tersimpan
+func (c *Client) relogin(ctx context.Context) error {
+	c.mu.Lock()
+	email, password := c.email, c.password
+	c.mu.Unlock()
+
+	resp, err := c.login(ctx, email, password)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Kredensial bisa sudah diganti goroutine lain selama login berlangsung
+	if c.email == email {
+		c.token = resp.Token
+		c.tokenExpiry = tokenExpiry(resp.Token)
+	}
+	return nil
+}
+
+// tokenExpiry membaca klaim exp dari JWT tanpa verifikasi tanda tangan (verifikasi tugas server);
+// zero time jika token tidak bisa dibaca
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.ExpiresAt, 0)
+}
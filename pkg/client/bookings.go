@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"mkp/models"
+	"net/http"
+	"strconv"
+)
+
+// Bookings semua booking milik user yang sedang login
+func (c *Client) Bookings(ctx context.Context) ([]models.Transaction, error) {
+	transactions := []models.Transaction{}
+	return transactions, c.do(ctx, newRequest(http.MethodGet, "/api/bookings"), &transactions)
+}
+
+// Booking detail booking beserta tiketnya
+func (c *Client) Booking(ctx context.Context, id int) (*models.Transaction, error) {
+	var transaction models.Transaction
+	if err := c.do(ctx, newRequest(http.MethodGet, bookingPath(id)), &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// CreateBooking memesan kursi; booking berstatus PENDING dan kursi ditahan sampai dibayar.
+// Dikirim dengan Idempotency-Key sehingga retry tidak memesan dua kali.
+func (c *Client) CreateBooking(ctx context.Context, req models.BookingRequest) (*models.Transaction, error) {
+	call := newRequest(http.MethodPost, "/api/bookings")
+	call.body = req
+	call.idempotencyKey = true
+
+	var transaction models.Transaction
+	if err := c.do(ctx, call, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// PayBooking membayar booking PENDING dengan salah satu models.PaymentMethod*.
+// Dikirim dengan Idempotency-Key sehingga retry tidak membayar dua kali.
+func (c *Client) PayBooking(ctx context.Context, id int, paymentMethod string) (*models.Transaction, error) {
+	call := newRequest(http.MethodPost, bookingPath(id)+"/pay")
+	call.body = models.PaymentRequest{PaymentMethod: paymentMethod}
+	call.idempotencyKey = true
+
+	var transaction models.Transaction
+	if err := c.do(ctx, call, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// CancelBooking membatalkan booking: PENDING menjadi CANCELLED, PAID menjadi REFUNDED.
+// Tidak diulang otomatis karena server tidak mendukung Idempotency-Key di endpoint ini.
+func (c *Client) CancelBooking(ctx context.Context, id int) (*models.Transaction, error) {
+	var transaction models.Transaction
+	if err := c.do(ctx, newRequest(http.MethodPost, bookingPath(id)+"/cancel"), &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// Tickets semua tiket yang sudah dibayar milik user yang sedang login, urut sesuai booking
+func (c *Client) Tickets(ctx context.Context) ([]models.Ticket, error) {
+	transactions, err := c.Bookings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tickets := []models.Ticket{}
+	for _, transaction := range transactions {
+		if transaction.Status == models.TransactionStatusPaid {
+			tickets = append(tickets, transaction.Tickets...)
+		}
+	}
+	return tickets, nil
+}
+
+// CalendarFeed URL feed iCalendar privat berisi tiket user; rotate true mengganti token
+// sehingga URL lama tidak berlaku lagi
+func (c *Client) CalendarFeed(ctx context.Context, rotate bool) (*CalendarFeed, error) {
+	method := http.MethodGet
+	if rotate {
+		method = http.MethodPost
+	}
+	var feed CalendarFeed
+	if err := c.do(ctx, newRequest(method, "/api/calendar/feed"), &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// CalendarFeed URL feed kalender tiket
+type CalendarFeed struct {
+	URL       string `json:"url"`
+	WebcalURL string `json:"webcal_url"`
+}
+
+func bookingPath(id int) string {
+	return "/api/bookings/" + strconv.Itoa(id)
+}
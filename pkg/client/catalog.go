@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"mkp/models"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// MovieSearch parameter pencarian film
+type MovieSearch struct {
+	Query string
+	City  string
+	// Limit 0 memakai default server (20), maksimum 50
+	Limit int
+}
+
+// ShowtimeFilter filter jadwal tayang mendatang; field kosong tidak dipakai
+type ShowtimeFilter struct {
+	// Date tanggal lokal cinema, YYYY-MM-DD
+	Date     string
+	CinemaID int
+	MovieID  int
+	City     string
+}
+
+// Movies daftar film, city opsional membatasi ke film yang tayang di kota tersebut
+func (c *Client) Movies(ctx context.Context, city string) ([]models.Movie, error) {
+	call := newRequest(http.MethodGet, "/api/movies")
+	call.query = optional(url.Values{}, "city", city)
+
+	movies := []models.Movie{}
+	return movies, c.do(ctx, call, &movies)
+}
+
+// Movie detail film
+func (c *Client) Movie(ctx context.Context, id int) (*models.Movie, error) {
+	var movie models.Movie
+	if err := c.do(ctx, newRequest(http.MethodGet, "/api/movies/"+strconv.Itoa(id)), &movie); err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+// SearchMovies mencari film berdasarkan judul dan deskripsi, hasil terurut relevansi
+func (c *Client) SearchMovies(ctx context.Context, search MovieSearch) ([]models.MovieSearchResult, error) {
+	call := newRequest(http.MethodGet, "/api/movies/search")
+	call.query = url.Values{"q": {search.Query}}
+	optional(call.query, "city", search.City)
+	optionalInt(call.query, "limit", search.Limit)
+
+	results := []models.MovieSearchResult{}
+	return results, c.do(ctx, call, &results)
+}
+
+// Cinemas daftar cinema, city opsional
+func (c *Client) Cinemas(ctx context.Context, city string) ([]models.Cinema, error) {
+	call := newRequest(http.MethodGet, "/api/cinemas")
+	call.query = optional(url.Values{}, "city", city)
+
+	cinemas := []models.Cinema{}
+	return cinemas, c.do(ctx, call, &cinemas)
+}
+
+// Cinema detail cinema beserta studionya
+func (c *Client) Cinema(ctx context.Context, id int) (*models.Cinema, error) {
+	var cinema models.Cinema
+	if err := c.do(ctx, newRequest(http.MethodGet, "/api/cinemas/"+strconv.Itoa(id)), &cinema); err != nil {
+		return nil, err
+	}
+	return &cinema, nil
+}
+
+// Showtimes jadwal SHOWING mendatang sesuai filter
+func (c *Client) Showtimes(ctx context.Context, filter ShowtimeFilter) ([]models.Schedule, error) {
+	call := newRequest(http.MethodGet, "/api/showtimes")
+	call.query = optional(url.Values{}, "date", filter.Date)
+	optionalInt(call.query, "cinema_id", filter.CinemaID)
+	optionalInt(call.query, "movie_id", filter.MovieID)
+	optional(call.query, "city", filter.City)
+
+	schedules := []models.Schedule{}
+	return schedules, c.do(ctx, call, &schedules)
+}
+
+func optional(query url.Values, name, value string) url.Values {
+	if value != "" {
+		query.Set(name, value)
+	}
+	return query
+}
+
+func optionalInt(query url.Values, name string, value int) url.Values {
+	if value != 0 {
+		query.Set(name, strconv.Itoa(value))
+	}
+	return query
+}
@@ -0,0 +1,286 @@
+// Package client adalah client Go untuk MKP Cinema API.
+//
+//	c := client.New("http://localhost:8080")
+//	if _, err := c.Login(ctx, "admin@mkp.id", "secret"); err != nil { ... }
+//	schedules, err := c.Schedules(ctx)
+//
+// Setelah Login atau Register token disimpan dan diperbarui otomatis (login ulang) menjelang
+// kedaluwarsa atau saat server menolaknya. Request idempotent (GET, PUT dan DELETE tanpa If-Match,
+// dan POST yang didukung Idempotency-Key) diulang saat gagal jaringan, 429, 502, 503 dan 504. Error dari server
+// dikembalikan sebagai *Error yang bisa dicek dengan errors.Is, misal errors.Is(err, client.ErrNotFound).
+package client
+
+import (
+	"bytes"
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default konfigurasi Client
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 3
+	DefaultRetryDelay = 200 * time.Millisecond
+	maxRetryDelay     = 5 * time.Second
+)
+
+// Client client API. Field boleh diubah setelah New dan sebelum Client dipakai;
+// method Client aman dipanggil dari beberapa goroutine.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// MaxRetries jumlah pengulangan maksimum request idempotent, 0 berarti tidak diulang
+	MaxRetries int
+	// RetryDelay jeda awal sebelum retry, berlipat dua setiap percobaan
+	RetryDelay time.Duration
+	UserAgent  string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	email       string
+	password    string
+}
+
+// New membuat client untuk baseURL, misal http://localhost:8080
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+		MaxRetries: DefaultMaxRetries,
+		RetryDelay: DefaultRetryDelay,
+		UserAgent:  "mkp-go-client",
+	}
+}
+
+// request satu panggilan API
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        interface{}
+	contentType string
+	header      http.Header
+	auth        bool
+	// idempotencyKey dikirim sebagai Idempotency-Key agar POST aman diulang
+	idempotencyKey bool
+}
+
+// newRequest request JSON yang memerlukan token
+func newRequest(method, path string) *request {
+	return &request{method: method, path: path, auth: true, header: http.Header{}}
+}
+
+// retryable true jika request aman dikirim ulang tanpa efek ganda. PUT dan DELETE dengan
+// If-Match tidak diulang: jika percobaan pertama sudah berhasil di server, retry-nya ditolak
+// 412 karena version sudah naik dan caller salah mengira perubahannya gagal.
+func (r *request) retryable() bool {
+	switch r.method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPut, http.MethodDelete:
+		return r.header.Get("If-Match") == ""
+	}
+	return r.idempotencyKey
+}
+
+// do mengirim request dan men-decode response JSON ke out (boleh nil)
+func (c *Client) do(ctx context.Context, req *request, out interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return decodeError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("mkp api: decode %s %s response: %w", req.method, req.path, err)
+	}
+	return nil
+}
+
+// send mengirim request dengan retry dan login ulang; response >= 400 yang tidak diulang
+// dikembalikan apa adanya ke pemanggil
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("mkp api: encode %s %s request: %w", req.method, req.path, err)
+		}
+	}
+	if req.idempotencyKey && req.header.Get("Idempotency-Key") == "" {
+		req.header.Set("Idempotency-Key", newIdempotencyKey())
+	}
+
+	relogged := false
+	for attempt := 0; ; attempt++ {
+		httpReq, err := c.newHTTPRequest(ctx, req, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient().Do(httpReq)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if req.retryable() && attempt < c.MaxRetries {
+				if err := sleep(ctx, c.backoff(attempt)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		// Token ditolak (kedaluwarsa atau secret server berganti): login ulang sekali
+		if resp.StatusCode == http.StatusUnauthorized && req.auth && !relogged && c.hasCredentials() {
+			discard(resp)
+			relogged = true
+			if err := c.relogin(ctx); err != nil {
+				return nil, err
+			}
+			attempt--
+			continue
+		}
+
+		if req.retryable() && attempt < c.MaxRetries && shouldRetry(req, resp) {
+			delay := retryAfter(resp, c.backoff(attempt))
+			discard(resp)
+			if err := sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return resp, nil
+	}
+}
+
+func (c *Client) newHTTPRequest(ctx context.Context, req *request, payload []byte) (*http.Request, error) {
+	target := c.BaseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if payload != nil {
+		contentType := req.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if c.UserAgent != "" {
+		httpReq.Header.Set("User-Agent", c.UserAgent)
+	}
+	if req.auth {
+		token, err := c.validToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return httpReq, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// backoff jeda eksponensial dengan jitter untuk percobaan ke-attempt (mulai 0)
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	delay <<= attempt
+	if delay > maxRetryDelay || delay <= 0 {
+		delay = maxRetryDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// shouldRetry true untuk status yang menandakan server sementara tidak bisa melayani. 409 hanya
+// diulang jika request dengan Idempotency-Key yang sama masih diproses (ditandai Retry-After).
+func shouldRetry(req *request, resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return req.idempotencyKey && resp.Header.Get("Retry-After") != ""
+	}
+	return false
+}
+
+// retryAfter membaca header Retry-After (detik), jika tidak ada memakai fallback
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		delay := time.Duration(seconds) * time.Second
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+		return delay
+	}
+	return fallback
+}
+
+// sleep menunggu d atau sampai ctx dibatalkan
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discard membaca habis dan menutup body agar koneksi bisa dipakai ulang
+func discard(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+// newIdempotencyKey key acak 32 karakter hex
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := cryptorand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
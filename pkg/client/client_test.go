@@ -0,0 +1,360 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mkp/apierror"
+	"mkp/models"
+	"mkp/pkg/client"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient client ke server test dengan jeda retry kecil agar test cepat
+func newTestClient(t *testing.T, handler http.HandlerFunc) *client.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c := client.New(server.URL)
+	c.RetryDelay = time.Millisecond
+	c.SetToken("token")
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeProblem(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", apierror.ContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apierror.New(status, code, "test problem"))
+}
+
+// testToken JWT tanpa tanda tangan valid dengan klaim exp; cukup untuk client yang hanya membaca exp
+func testToken(name string, expiresAt time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":%q,"exp":%d}`, name, expiresAt.Unix())))
+	return header + "." + claims + ".sig"
+}
+
+func TestRetryTransientStatus(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var calls atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) < 3 {
+					w.Header().Set("Retry-After", "0")
+					writeProblem(w, status, apierror.CodeInternalError)
+					return
+				}
+				writeJSON(w, http.StatusOK, models.Schedule{ID: 7})
+			})
+
+			schedule, err := c.Schedule(context.Background(), 7)
+			if err != nil {
+				t.Fatalf("Schedule: %v", err)
+			}
+			if schedule.ID != 7 || calls.Load() != 3 {
+				t.Errorf("got schedule %d after %d calls, want 7 after 3", schedule.ID, calls.Load())
+			}
+		})
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeProblem(w, http.StatusServiceUnavailable, apierror.CodeInternalError)
+	})
+	c.MaxRetries = 2
+
+	_, err := c.Schedule(context.Background(), 1)
+	if !errors.Is(err, client.ErrServer) {
+		t.Errorf("err = %v, want ErrServer", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3 (1 + MaxRetries)", calls.Load())
+	}
+}
+
+func TestNoRetryConditionalWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(c *client.Client) error
+	}{
+		{"PUT", func(c *client.Client) error {
+			_, err := c.UpdateSchedule(context.Background(), 1, 3, models.ScheduleCreateRequest{})
+			return err
+		}},
+		{"DELETE", func(c *client.Client) error {
+			return c.DeleteSchedule(context.Background(), 1, 3)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if r.Method != tt.name || r.Header.Get("If-Match") != `"3"` {
+					t.Errorf("got %s with If-Match %q", r.Method, r.Header.Get("If-Match"))
+				}
+				writeProblem(w, http.StatusServiceUnavailable, apierror.CodeInternalError)
+			})
+
+			if err := tt.call(c); !errors.Is(err, client.ErrServer) {
+				t.Errorf("err = %v, want ErrServer", err)
+			}
+			if calls.Load() != 1 {
+				t.Errorf("calls = %d, want 1", calls.Load())
+			}
+		})
+	}
+}
+
+func TestNoRetryPostWithoutIdempotencyKey(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeProblem(w, http.StatusServiceUnavailable, apierror.CodeInternalError)
+	})
+
+	if _, err := c.CreateSchedulesBulk(context.Background(), models.ScheduleBulkRequest{}); !errors.Is(err, client.ErrServer) {
+		t.Errorf("err = %v, want ErrServer", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestRetryIdempotencyKeyInProgress(t *testing.T) {
+	var mu sync.Mutex
+	keys := []string{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		n := len(keys)
+		mu.Unlock()
+		if n == 1 {
+			w.Header().Set("Retry-After", "0")
+			writeProblem(w, http.StatusConflict, apierror.CodeIdempotencyKeyInProgress)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"message": "created", "id": 42})
+	})
+
+	id, err := c.CreateSchedule(context.Background(), models.ScheduleCreateRequest{})
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	if id != 42 {
+		t.Errorf("id = %d, want 42", id)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Idempotency-Key per attempt = %q, want the same non-empty key twice", keys)
+	}
+}
+
+func TestNoRetryConflictWithoutRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeProblem(w, http.StatusConflict, apierror.CodeScheduleOverlap)
+	})
+
+	_, err := c.CreateSchedule(context.Background(), models.ScheduleCreateRequest{})
+	if !errors.Is(err, client.ErrConflict) || !client.HasCode(err, apierror.CodeScheduleOverlap) {
+		t.Errorf("err = %v, want ErrConflict with %s", err, apierror.CodeScheduleOverlap)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+// loginServer API dengan satu token yang diterima; POST /api/login menerbitkan token baru
+type loginServer struct {
+	mu     sync.Mutex
+	valid  string
+	logins int
+	next   func() string
+}
+
+func (s *loginServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.URL.Path == "/api/login" {
+		s.logins++
+		s.valid = s.next()
+		writeJSON(w, http.StatusOK, models.LoginResponse{Token: s.valid})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+s.valid {
+		writeProblem(w, http.StatusUnauthorized, apierror.CodeInvalidToken)
+		return
+	}
+	writeJSON(w, http.StatusOK, models.Schedule{ID: 1})
+}
+
+func TestReloginOnceOnUnauthorized(t *testing.T) {
+	n := 0
+	server := &loginServer{next: func() string {
+		n++
+		return testToken(fmt.Sprintf("login-%d", n), time.Now().Add(time.Hour))
+	}}
+	c := newTestClient(t, server.handle)
+	if _, err := c.Login(context.Background(), "admin@mkp.id", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	// Server "restart": token lama tidak berlaku lagi
+	server.mu.Lock()
+	server.valid = "rotated"
+	server.mu.Unlock()
+
+	if _, err := c.Schedule(context.Background(), 1); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if server.logins != 2 {
+		t.Errorf("logins = %d, want 2 (initial + one relogin)", server.logins)
+	}
+}
+
+func TestReloginNotRepeated(t *testing.T) {
+	var logins, calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/login" {
+			logins.Add(1)
+			writeJSON(w, http.StatusOK, models.LoginResponse{Token: testToken("user", time.Now().Add(time.Hour))})
+			return
+		}
+		calls.Add(1)
+		writeProblem(w, http.StatusUnauthorized, apierror.CodeInvalidToken)
+	})
+	if _, err := c.Login(context.Background(), "admin@mkp.id", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if _, err := c.Schedule(context.Background(), 1); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+	if logins.Load() != 2 || calls.Load() != 2 {
+		t.Errorf("logins = %d, calls = %d, want 2 and 2", logins.Load(), calls.Load())
+	}
+}
+
+func TestReloginBeforeExpiry(t *testing.T) {
+	expiring := true
+	server := &loginServer{next: func() string {
+		// Login pertama menerbitkan token yang hampir kedaluwarsa
+		expiry := time.Now().Add(time.Hour)
+		if expiring {
+			expiring = false
+			expiry = time.Now().Add(10 * time.Second)
+		}
+		return testToken("user", expiry)
+	}}
+	c := newTestClient(t, server.handle)
+	if _, err := c.Login(context.Background(), "admin@mkp.id", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	first := c.Token()
+
+	if _, err := c.Schedule(context.Background(), 1); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if server.logins != 2 || c.Token() == first {
+		t.Errorf("logins = %d, token refreshed = %v; want 2 and true", server.logins, c.Token() != first)
+	}
+}
+
+func TestContextCancelledDuringBackoff(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "5")
+		writeProblem(w, http.StatusServiceUnavailable, apierror.CodeInternalError)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := c.Schedule(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("returned after %s, want shortly after the context deadline", elapsed)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		sentinel   error
+		code       string
+		detail     string
+		fieldError string
+	}{
+		{
+			name: "problem json",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				problem := apierror.New(http.StatusUnprocessableEntity, apierror.CodeValidationFailed, "invalid").
+					WithErrors(apierror.FieldError{Field: "price", Code: "OUT_OF_RANGE", Message: "price must be greater than 0"})
+				w.Header().Set("Content-Type", apierror.ContentType)
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(problem)
+			},
+			sentinel: client.ErrUnprocessable, code: apierror.CodeValidationFailed, detail: "invalid", fieldError: "price",
+		},
+		{
+			name: "plain text from proxy",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte("no such route\n"))
+			},
+			sentinel: client.ErrNotFound, detail: "no such route",
+		},
+		{
+			name: "precondition failed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeProblem(w, http.StatusPreconditionFailed, apierror.CodePreconditionFailed)
+			},
+			sentinel: client.ErrPreconditionFailed, code: apierror.CodePreconditionFailed, detail: "test problem",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.handler)
+			_, err := c.Schedule(context.Background(), 1)
+
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *client.Error", err)
+			}
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.sentinel)
+			}
+			if apiErr.Code != tt.code || apiErr.Detail != tt.detail {
+				t.Errorf("code, detail = %q, %q; want %q, %q", apiErr.Code, apiErr.Detail, tt.code, tt.detail)
+			}
+			if tt.fieldError != "" && apiErr.FieldError(tt.fieldError) == nil {
+				t.Errorf("FieldError(%q) = nil", tt.fieldError)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mkp/apierror"
+	"net/http"
+	"strings"
+)
+
+// Error sentinel untuk errors.Is, dicocokkan dengan status HTTP *Error
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrUnprocessable      = errors.New("unprocessable entity")
+	ErrServer             = errors.New("server error")
+)
+
+// Error response error dari API (problem+json). Code berisi kode stabil seperti
+// apierror.CodeSeatUnavailable, Errors berisi detail per field untuk VALIDATION_FAILED.
+type Error struct {
+	StatusCode int
+	apierror.Problem
+}
+
+// Error implementasi error
+func (e *Error) Error() string {
+	message := e.Detail
+	if message == "" {
+		message = e.Title
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("mkp api: %d %s", e.StatusCode, message)
+	}
+	return fmt.Sprintf("mkp api: %d %s: %s", e.StatusCode, e.Code, message)
+}
+
+// Is mencocokkan error dengan sentinel sesuai status HTTP
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrPreconditionFailed:
+		return e.StatusCode == http.StatusPreconditionFailed || e.StatusCode == http.StatusPreconditionRequired
+	case ErrUnprocessable:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// FieldError detail error untuk satu field, nil jika tidak ada
+func (e *Error) FieldError(field string) *apierror.FieldError {
+	for i := range e.Errors {
+		if e.Errors[i].Field == field {
+			return &e.Errors[i]
+		}
+	}
+	return nil
+}
+
+// HasCode true jika err adalah *Error dengan kode tersebut
+func HasCode(err error, code string) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// decodeError membaca body error. Response yang bukan problem+json (misal dari proxy)
+// tetap menjadi *Error dengan body sebagai Detail.
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	contentType := resp.Header.Get("Content-Type")
+	if strings.Contains(contentType, "json") && json.Unmarshal(body, &apiErr.Problem) == nil && apiErr.Code != "" {
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = resp.Header.Get(apierror.RequestIDHeader)
+		}
+		return apiErr
+	}
+
+	apiErr.Problem = apierror.Problem{
+		Title:     http.StatusText(resp.StatusCode),
+		Status:    resp.StatusCode,
+		Detail:    strings.TrimSpace(string(body)),
+		RequestID: resp.Header.Get(apierror.RequestIDHeader),
+	}
+	return apiErr
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"mkp/models"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// createdResponse response create yang hanya berisi id
+type createdResponse struct {
+	Message string `json:"message"`
+	ID      int    `json:"id"`
+}
+
+// Schedules semua jadwal yang belum dihapus
+func (c *Client) Schedules(ctx context.Context) ([]models.Schedule, error) {
+	schedules := []models.Schedule{}
+	return schedules, c.do(ctx, newRequest(http.MethodGet, "/api/schedules"), &schedules)
+}
+
+// Schedule detail jadwal. Schedule.Version dipakai sebagai If-Match pada update dan delete.
+func (c *Client) Schedule(ctx context.Context, id int) (*models.Schedule, error) {
+	var schedule models.Schedule
+	if err := c.do(ctx, newRequest(http.MethodGet, schedulePath(id)), &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// CreateSchedule membuat jadwal dan mengembalikan ID-nya. Request dikirim dengan
+// Idempotency-Key sehingga retry tidak membuat jadwal ganda.
+func (c *Client) CreateSchedule(ctx context.Context, req models.ScheduleCreateRequest) (int, error) {
+	call := newRequest(http.MethodPost, "/api/schedules/create")
+	call.body = req
+	call.idempotencyKey = true
+
+	var created createdResponse
+	if err := c.do(ctx, call, &created); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// CreateSchedulesBulk membuat banyak jadwal sekaligus; dengan req.DryRun hanya validasi.
+// Jika ada jadwal yang tidak valid error berupa *Error 422 dan tidak ada yang disimpan.
+func (c *Client) CreateSchedulesBulk(ctx context.Context, req models.ScheduleBulkRequest) (*models.ScheduleBulkResult, error) {
+	call := newRequest(http.MethodPost, "/api/schedules/bulk")
+	call.body = req
+
+	var result models.ScheduleBulkResult
+	if err := c.do(ctx, call, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateSchedule mengganti seluruh isi jadwal. version adalah Schedule.Version yang terakhir
+// dibaca; ErrPreconditionFailed jika jadwal sudah diubah orang lain sejak itu. Karena itu request
+// tidak diulang otomatis saat gagal jaringan; baca ulang jadwal untuk memastikan hasilnya.
+func (c *Client) UpdateSchedule(ctx context.Context, id, version int, req models.ScheduleCreateRequest) (*models.Schedule, error) {
+	call := newRequest(http.MethodPut, schedulePath(id))
+	call.body = req
+	call.header.Set("If-Match", versionETag(version))
+
+	var schedule models.Schedule
+	if err := c.do(ctx, call, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// PatchSchedule mengubah sebagian jadwal dengan JSON Merge Patch, misal
+// map[string]interface{}{"price": 50000}. Nilai nil menghapus field.
+func (c *Client) PatchSchedule(ctx context.Context, id, version int, patch map[string]interface{}) (*models.Schedule, error) {
+	call := newRequest(http.MethodPatch, schedulePath(id))
+	call.body = patch
+	call.contentType = "application/merge-patch+json"
+	call.header.Set("If-Match", versionETag(version))
+
+	var schedule models.Schedule
+	if err := c.do(ctx, call, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// DeleteSchedule soft delete jadwal. Ditolak (ErrConflict) jika masih ada tiket PAID.
+func (c *Client) DeleteSchedule(ctx context.Context, id, version int) error {
+	call := newRequest(http.MethodDelete, schedulePath(id))
+	call.header.Set("If-Match", versionETag(version))
+	return c.do(ctx, call, nil)
+}
+
+// PreviewSchedulePrice harga tiket untuk kursi tertentu sebelum booking
+func (c *Client) PreviewSchedulePrice(ctx context.Context, id int, seatIDs []int) (*models.PriceQuote, error) {
+	ids := make([]string, len(seatIDs))
+	for i, seatID := range seatIDs {
+		ids[i] = strconv.Itoa(seatID)
+	}
+	call := newRequest(http.MethodGet, schedulePath(id)+"/price-preview")
+	call.query = url.Values{"seat_ids": {strings.Join(ids, ",")}}
+
+	var quote models.PriceQuote
+	if err := c.do(ctx, call, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func schedulePath(id int) string {
+	return "/api/schedules/" + strconv.Itoa(id)
+}
+
+// versionETag ETag jadwal sesuai format server, misal "3"
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
@@ -0,0 +1,323 @@
+package pricing
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"mkp/config"
+	"mkp/models"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrScheduleNotFound jadwal tidak ditemukan
+	ErrScheduleNotFound = errors.New("schedule not found")
+	// ErrSeatNotFound salah satu kursi tidak ada di studio jadwal tersebut
+	ErrSeatNotFound = errors.New("seat not found in schedule studio")
+)
+
+// Querier dipenuhi oleh *sql.DB dan *sql.Tx sehingga harga bisa dihitung di dalam transaksi booking
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// ScheduleContext data jadwal yang dibutuhkan untuk mencocokkan rule
+type ScheduleContext struct {
+	ScheduleID int
+	BasePrice  float64
+	LocalStart time.Time // start_time dalam timezone cinema
+	StudioType string
+	IsHoliday  bool
+}
+
+// Seat data kursi yang dibutuhkan untuk mencocokkan rule
+type Seat struct {
+	ID       int
+	Label    string
+	Category string
+}
+
+// Matches mengecek apakah rule berlaku untuk jadwal dan kursi tertentu
+func Matches(rule models.PricingRule, sched ScheduleContext, seat Seat) bool {
+	if !rule.Active {
+		return false
+	}
+	if rule.HolidayOnly && !sched.IsHoliday {
+		return false
+	}
+	if len(rule.DaysOfWeek) > 0 && !containsDay(rule.DaysOfWeek, int(sched.LocalStart.Weekday())) {
+		return false
+	}
+	if rule.TimeFrom != nil && rule.TimeTo != nil && !inTimeBand(sched.LocalStart, *rule.TimeFrom, *rule.TimeTo) {
+		return false
+	}
+	if rule.StudioType != nil && *rule.StudioType != sched.StudioType {
+		return false
+	}
+	if rule.SeatCategory != nil && *rule.SeatCategory != seat.Category {
+		return false
+	}
+	return true
+}
+
+// Calculate menghitung harga satu kursi. Rule yang cocok diterapkan berurutan
+// berdasarkan priority; persentase selalu dihitung dari harga dasar jadwal
+// sehingga hasilnya tidak bergantung pada urutan. Harga tidak pernah negatif.
+func Calculate(sched ScheduleContext, seat Seat, rules []models.PricingRule) models.SeatPrice {
+	sorted := make([]models.PricingRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	price := sched.BasePrice
+	applied := []models.AppliedRule{}
+	for _, rule := range sorted {
+		if !Matches(rule, sched, seat) {
+			continue
+		}
+
+		amount := rule.AdjustmentValue
+		if rule.AdjustmentType == models.AdjustmentPercentage {
+			amount = sched.BasePrice * rule.AdjustmentValue / 100
+		}
+		amount = round(amount)
+
+		price += amount
+		applied = append(applied, models.AppliedRule{
+			RuleID: rule.ID,
+			Name:   rule.Name,
+			Amount: amount,
+		})
+	}
+
+	return models.SeatPrice{
+		SeatID:       seat.ID,
+		SeatLabel:    seat.Label,
+		Category:     seat.Category,
+		BasePrice:    sched.BasePrice,
+		Price:        math.Max(0, round(price)),
+		AppliedRules: applied,
+	}
+}
+
+// Quote menghitung harga semua kursi yang dipilih untuk sebuah jadwal
+func Quote(q Querier, scheduleID int, seatIDs []int) (models.PriceQuote, error) {
+	sched, studioID, err := LoadScheduleContext(q, scheduleID)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	seats, err := LoadSeats(q, studioID, seatIDs)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	rules, err := LoadActiveRules(q)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	quote := models.PriceQuote{
+		ScheduleID: scheduleID,
+		BasePrice:  sched.BasePrice,
+		IsHoliday:  sched.IsHoliday,
+		Seats:      []models.SeatPrice{},
+	}
+	for _, seat := range seats {
+		seatPrice := Calculate(sched, seat, rules)
+		quote.Seats = append(quote.Seats, seatPrice)
+		quote.Total += seatPrice.Price
+	}
+	quote.Total = round(quote.Total)
+
+	return quote, nil
+}
+
+// LoadScheduleContext mengambil harga dasar, waktu lokal, tipe studio dan status hari libur jadwal
+func LoadScheduleContext(q Querier, scheduleID int) (ScheduleContext, int, error) {
+	var sched ScheduleContext
+	var studioID int
+	var startTime time.Time
+	var timezone string
+
+	query := `
+		SELECT s.price, s.start_time, s.studio_id, st.studio_type, c.timezone
+		FROM schedules s
+		JOIN studios st ON s.studio_id = st.id
+		JOIN cinemas c ON st.cinema_id = c.id
+		WHERE s.id = $1 AND s.deleted_at IS NULL
+	`
+	err := q.QueryRow(query, scheduleID).Scan(&sched.BasePrice, &startTime, &studioID, &sched.StudioType, &timezone)
+	if err == sql.ErrNoRows {
+		return sched, 0, ErrScheduleNotFound
+	}
+	if err != nil {
+		return sched, 0, err
+	}
+
+	sched.ScheduleID = scheduleID
+	sched.LocalStart = startTime.In(config.LoadLocation(timezone))
+
+	// Hari libur ditentukan dari tanggal lokal cinema
+	err = q.QueryRow("SELECT EXISTS(SELECT 1 FROM holidays WHERE holiday_date = $1)",
+		sched.LocalStart.Format("2006-01-02")).Scan(&sched.IsHoliday)
+	if err != nil {
+		return sched, 0, err
+	}
+
+	return sched, studioID, nil
+}
+
+// LoadSeats mengambil kursi berdasarkan ID dan memastikan semuanya ada di studio yang sama
+func LoadSeats(q Querier, studioID int, seatIDs []int) ([]Seat, error) {
+	ids := make([]int64, len(seatIDs))
+	for i, id := range seatIDs {
+		ids[i] = int64(id)
+	}
+
+	query := `
+		SELECT id, row_code, seat_number, category
+		FROM seats
+		WHERE studio_id = $1 AND id = ANY($2)
+		ORDER BY row_code, seat_number
+	`
+	rows, err := q.Query(query, studioID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seats := []Seat{}
+	for rows.Next() {
+		var seat Seat
+		var row string
+		var number int
+		if err := rows.Scan(&seat.ID, &row, &number, &seat.Category); err != nil {
+			return nil, err
+		}
+		seat.Label = row + strconv.Itoa(number)
+		seats = append(seats, seat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(seats) != len(seatIDs) {
+		return nil, ErrSeatNotFound
+	}
+	return seats, nil
+}
+
+// LoadActiveRules mengambil semua pricing rule yang aktif
+func LoadActiveRules(q Querier) ([]models.PricingRule, error) {
+	rows, err := q.Query(RuleSelectQuery + " WHERE active = true ORDER BY priority, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.PricingRule{}
+	for rows.Next() {
+		rule, err := ScanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// RuleSelectQuery query dasar pricing rule, dipakai bersama ScanRule
+const RuleSelectQuery = `
+	SELECT id, name, days_of_week, to_char(time_from, 'HH24:MI'), to_char(time_to, 'HH24:MI'),
+		studio_type, seat_category, holiday_only, adjustment_type, adjustment_value,
+		priority, active, created_at, updated_at
+	FROM pricing_rules
+`
+
+// ScanRule membaca satu baris hasil RuleSelectQuery
+func ScanRule(row interface{ Scan(...interface{}) error }) (models.PricingRule, error) {
+	var rule models.PricingRule
+	var days pq.Int64Array
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&days,
+		&rule.TimeFrom,
+		&rule.TimeTo,
+		&rule.StudioType,
+		&rule.SeatCategory,
+		&rule.HolidayOnly,
+		&rule.AdjustmentType,
+		&rule.AdjustmentValue,
+		&rule.Priority,
+		&rule.Active,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return rule, err
+	}
+
+	rule.DaysOfWeek = []int{}
+	for _, day := range days {
+		rule.DaysOfWeek = append(rule.DaysOfWeek, int(day))
+	}
+	return rule, nil
+}
+
+// DaysArray mengubah days_of_week menjadi parameter array PostgreSQL (NULL jika kosong)
+func DaysArray(days []int) interface{} {
+	if len(days) == 0 {
+		return nil
+	}
+	values := make(pq.Int64Array, len(days))
+	for i, day := range days {
+		values[i] = int64(day)
+	}
+	return values
+}
+
+// inTimeBand mengecek jam tayang lokal berada di [from, to). Band yang melewati
+// tengah malam (misal 22:00 - 02:00) juga didukung.
+func inTimeBand(t time.Time, from, to string) bool {
+	fromTime, err := time.Parse("15:04", from)
+	if err != nil {
+		return false
+	}
+	toTime, err := time.Parse("15:04", to)
+	if err != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	start := fromTime.Hour()*60 + fromTime.Minute()
+	end := toTime.Hour()*60 + toTime.Minute()
+
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func containsDay(days []int, day int) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
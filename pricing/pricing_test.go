@@ -0,0 +1,126 @@
+package pricing
+
+import (
+	"mkp/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestInTimeBand(t *testing.T) {
+	tests := []struct {
+		clock    string
+		from, to string
+		want     bool
+	}{
+		{"12:00", "12:00", "17:00", true},
+		{"16:59", "12:00", "17:00", true},
+		{"17:00", "12:00", "17:00", false},
+		{"11:59", "12:00", "17:00", false},
+		// Band yang melewati tengah malam
+		{"22:00", "22:00", "02:00", true},
+		{"23:59", "22:00", "02:00", true},
+		{"00:00", "22:00", "02:00", true},
+		{"01:59", "22:00", "02:00", true},
+		{"02:00", "22:00", "02:00", false},
+		{"21:59", "22:00", "02:00", false},
+		{"12:00", "22:00", "02:00", false},
+		// from == to berarti band kosong
+		{"10:00", "10:00", "10:00", false},
+		{"10:00", "bad", "12:00", false},
+		{"10:00", "09:00", "25:00", false},
+	}
+	for _, tt := range tests {
+		clock, _ := time.Parse("15:04", tt.clock)
+		if got := inTimeBand(clock, tt.from, tt.to); got != tt.want {
+			t.Errorf("inTimeBand(%s, %s-%s) = %v, want %v", tt.clock, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	// Jumat 19:30 waktu lokal
+	friday := ScheduleContext{BasePrice: 50000, LocalStart: time.Date(2026, 1, 2, 19, 30, 0, 0, time.UTC), StudioType: models.StudioTypeRegular}
+	seat := Seat{ID: 1, Category: models.SeatCategoryPremium}
+
+	tests := []struct {
+		name  string
+		rule  models.PricingRule
+		sched ScheduleContext
+		want  bool
+	}{
+		{"no conditions", models.PricingRule{Active: true}, friday, true},
+		{"inactive", models.PricingRule{}, friday, false},
+		{"weekend days", models.PricingRule{Active: true, DaysOfWeek: []int{0, 5, 6}}, friday, true},
+		{"weekday only", models.PricingRule{Active: true, DaysOfWeek: []int{1, 2, 3, 4}}, friday, false},
+		{"evening band", models.PricingRule{Active: true, TimeFrom: strPtr("18:00"), TimeTo: strPtr("23:00")}, friday, true},
+		{"matinee band", models.PricingRule{Active: true, TimeFrom: strPtr("10:00"), TimeTo: strPtr("14:00")}, friday, false},
+		{"only time_from ignored", models.PricingRule{Active: true, TimeFrom: strPtr("10:00")}, friday, true},
+		{"holiday only on normal day", models.PricingRule{Active: true, HolidayOnly: true}, friday, false},
+		{"holiday only on holiday", models.PricingRule{Active: true, HolidayOnly: true}, ScheduleContext{LocalStart: friday.LocalStart, IsHoliday: true}, true},
+		{"studio type", models.PricingRule{Active: true, StudioType: strPtr(models.StudioTypePremiere)}, friday, false},
+		{"seat category", models.PricingRule{Active: true, SeatCategory: strPtr(models.SeatCategoryPremium)}, friday, true},
+		{"other seat category", models.PricingRule{Active: true, SeatCategory: strPtr(models.SeatCategoryCouple)}, friday, false},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.rule, tt.sched, seat); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	sched := ScheduleContext{BasePrice: 50000, LocalStart: time.Date(2026, 1, 3, 23, 0, 0, 0, time.UTC), StudioType: models.StudioTypeRegular}
+	seat := Seat{ID: 9, Label: "J1", Category: models.SeatCategoryCouple}
+
+	weekend := models.PricingRule{ID: 1, Name: "Weekend", Active: true, DaysOfWeek: []int{0, 6}, Priority: 10,
+		AdjustmentType: models.AdjustmentPercentage, AdjustmentValue: 20}
+	couple := models.PricingRule{ID: 2, Name: "Couple", Active: true, SeatCategory: strPtr(models.SeatCategoryCouple), Priority: 5,
+		AdjustmentType: models.AdjustmentFixed, AdjustmentValue: 15000}
+	midnight := models.PricingRule{ID: 3, Name: "Midnight", Active: true, TimeFrom: strPtr("22:00"), TimeTo: strPtr("02:00"), Priority: 5,
+		AdjustmentType: models.AdjustmentPercentage, AdjustmentValue: -10}
+	weekday := models.PricingRule{ID: 4, Name: "Weekday", Active: true, DaysOfWeek: []int{1, 2, 3, 4, 5},
+		AdjustmentType: models.AdjustmentFixed, AdjustmentValue: -5000}
+	huge := models.PricingRule{ID: 5, Name: "Free", Active: true,
+		AdjustmentType: models.AdjustmentPercentage, AdjustmentValue: -150}
+	third := models.PricingRule{ID: 6, Name: "Third", Active: true,
+		AdjustmentType: models.AdjustmentPercentage, AdjustmentValue: 100.0 / 3}
+
+	tests := []struct {
+		name    string
+		rules   []models.PricingRule
+		price   float64
+		applied []models.AppliedRule
+	}{
+		{"no rules", nil, 50000, []models.AppliedRule{}},
+		{
+			// Priority naik, priority sama diurutkan berdasarkan ID; persentase dari harga dasar
+			"ordered by priority then id",
+			[]models.PricingRule{weekend, midnight, couple, weekday},
+			70000,
+			[]models.AppliedRule{{RuleID: 2, Name: "Couple", Amount: 15000}, {RuleID: 3, Name: "Midnight", Amount: -5000},
+				{RuleID: 1, Name: "Weekend", Amount: 10000}},
+		},
+		{"never negative", []models.PricingRule{huge}, 0, []models.AppliedRule{{RuleID: 5, Name: "Free", Amount: -75000}}},
+		{"rounded to cents", []models.PricingRule{third}, 66666.67, []models.AppliedRule{{RuleID: 6, Name: "Third", Amount: 16666.67}}},
+	}
+	for _, tt := range tests {
+		got := Calculate(sched, seat, tt.rules)
+		if got.Price != tt.price || got.BasePrice != 50000 || got.SeatID != 9 || got.SeatLabel != "J1" {
+			t.Errorf("%s: price = %v (base %v, seat %d %s), want %v", tt.name, got.Price, got.BasePrice, got.SeatID, got.SeatLabel, tt.price)
+		}
+		if !reflect.DeepEqual(got.AppliedRules, tt.applied) {
+			t.Errorf("%s: applied = %+v, want %+v", tt.name, got.AppliedRules, tt.applied)
+		}
+	}
+}
+
+func TestCalculateDoesNotReorderInput(t *testing.T) {
+	rules := []models.PricingRule{{ID: 2, Priority: 9, Active: true}, {ID: 1, Priority: 1, Active: true}}
+	Calculate(ScheduleContext{BasePrice: 1}, Seat{}, rules)
+	if rules[0].ID != 2 || rules[1].ID != 1 {
+		t.Errorf("rules reordered to %d, %d", rules[0].ID, rules[1].ID)
+	}
+}
@@ -0,0 +1,204 @@
+package promo
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"mkp/models"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotFound kode promo tidak terdaftar
+	ErrNotFound = errors.New("promo code not found")
+	// ErrNotActive promo dinonaktifkan atau di luar periode berlaku
+	ErrNotActive = errors.New("promo code is not active")
+	// ErrUsageLimitReached kuota pemakaian global sudah habis
+	ErrUsageLimitReached = errors.New("promo code usage limit reached")
+	// ErrUserLimitReached user sudah memakai promo sebanyak batas per user
+	ErrUserLimitReached = errors.New("promo code already used the maximum number of times")
+	// ErrMinSpendNotMet subtotal belum mencapai minimal belanja
+	ErrMinSpendNotMet = errors.New("minimum spend not met")
+	// ErrNotApplicable promo tidak berlaku untuk film atau cinema jadwal ini
+	ErrNotApplicable = errors.New("promo code not applicable to this schedule")
+)
+
+// SelectQuery query dasar promo, dipakai bersama Scan
+const SelectQuery = `
+	SELECT id, code, COALESCE(description, ''), discount_type, discount_value, max_discount, min_spend,
+		valid_from, valid_until, usage_limit, usage_limit_per_user, used_count, movie_ids, cinema_ids,
+		active, created_at, updated_at
+	FROM promotions
+`
+
+// NormalizeCode menyamakan format kode promo (huruf besar, tanpa spasi di pinggir)
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Scan membaca satu baris hasil SelectQuery
+func Scan(row interface{ Scan(...interface{}) error }) (models.Promotion, error) {
+	var p models.Promotion
+	var movieIDs, cinemaIDs pq.Int64Array
+	err := row.Scan(
+		&p.ID,
+		&p.Code,
+		&p.Description,
+		&p.DiscountType,
+		&p.DiscountValue,
+		&p.MaxDiscount,
+		&p.MinSpend,
+		&p.ValidFrom,
+		&p.ValidUntil,
+		&p.UsageLimit,
+		&p.UsageLimitPerUser,
+		&p.UsedCount,
+		&movieIDs,
+		&cinemaIDs,
+		&p.Active,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	p.MovieIDs = toInts(movieIDs)
+	p.CinemaIDs = toInts(cinemaIDs)
+	return p, err
+}
+
+// IDArray mengubah daftar ID menjadi parameter array PostgreSQL (NULL jika kosong)
+func IDArray(ids []int) interface{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	values := make(pq.Int64Array, len(ids))
+	for i, id := range ids {
+		values[i] = int64(id)
+	}
+	return values
+}
+
+// Discount menghitung potongan harga untuk subtotal tertentu.
+// Potongan tidak pernah melebihi subtotal maupun max_discount.
+func Discount(p models.Promotion, subtotal float64) float64 {
+	discount := p.DiscountValue
+	if p.DiscountType == models.DiscountPercentage {
+		discount = subtotal * p.DiscountValue / 100
+		if p.MaxDiscount != nil && discount > *p.MaxDiscount {
+			discount = *p.MaxDiscount
+		}
+	}
+	discount = math.Min(discount, subtotal)
+	return math.Round(discount*100) / 100
+}
+
+// Redeem memvalidasi promo lalu menambah hitungan pemakaiannya secara atomik.
+// Harus dipanggil di dalam transaksi booking: baris promo dikunci sampai commit
+// sehingga dua booking paralel tidak bisa melewati batas pemakaian.
+func Redeem(tx *sql.Tx, code string, userID, scheduleID int, subtotal float64, now time.Time) (models.AppliedPromotion, error) {
+	p, err := Scan(tx.QueryRow(SelectQuery+" WHERE code = $1 FOR UPDATE", NormalizeCode(code)))
+	if err == sql.ErrNoRows {
+		return models.AppliedPromotion{}, ErrNotFound
+	}
+	if err != nil {
+		return models.AppliedPromotion{}, err
+	}
+
+	if !p.Active || now.Before(p.ValidFrom) || !now.Before(p.ValidUntil) {
+		return models.AppliedPromotion{}, ErrNotActive
+	}
+	if subtotal < p.MinSpend {
+		return models.AppliedPromotion{}, ErrMinSpendNotMet
+	}
+
+	if len(p.MovieIDs) > 0 || len(p.CinemaIDs) > 0 {
+		var movieID, cinemaID int
+		query := `
+			SELECT s.movie_id, st.cinema_id
+			FROM schedules s
+			JOIN studios st ON s.studio_id = st.id
+			WHERE s.id = $1
+		`
+		if err := tx.QueryRow(query, scheduleID).Scan(&movieID, &cinemaID); err != nil {
+			return models.AppliedPromotion{}, err
+		}
+		if len(p.MovieIDs) > 0 && !contains(p.MovieIDs, movieID) {
+			return models.AppliedPromotion{}, ErrNotApplicable
+		}
+		if len(p.CinemaIDs) > 0 && !contains(p.CinemaIDs, cinemaID) {
+			return models.AppliedPromotion{}, ErrNotApplicable
+		}
+	}
+
+	if p.UsageLimitPerUser != nil {
+		var used int
+		err := tx.QueryRow("SELECT COUNT(*) FROM promotion_usages WHERE promotion_id = $1 AND user_id = $2", p.ID, userID).
+			Scan(&used)
+		if err != nil {
+			return models.AppliedPromotion{}, err
+		}
+		if used >= *p.UsageLimitPerUser {
+			return models.AppliedPromotion{}, ErrUserLimitReached
+		}
+	}
+
+	result, err := tx.Exec(`
+		UPDATE promotions
+		SET used_count = used_count + 1, updated_at = NOW()
+		WHERE id = $1 AND (usage_limit IS NULL OR used_count < usage_limit)
+	`, p.ID)
+	if err != nil {
+		return models.AppliedPromotion{}, err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return models.AppliedPromotion{}, ErrUsageLimitReached
+	}
+
+	return models.AppliedPromotion{
+		PromotionID:    p.ID,
+		Code:           p.Code,
+		DiscountAmount: Discount(p, subtotal),
+	}, nil
+}
+
+// RecordUsage mencatat pemakaian promo untuk transaksi yang baru dibuat
+func RecordUsage(tx *sql.Tx, applied models.AppliedPromotion, userID, transactionID int) error {
+	_, err := tx.Exec(`
+		INSERT INTO promotion_usages (promotion_id, user_id, transaction_id, discount_amount, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`, applied.PromotionID, userID, transactionID, applied.DiscountAmount)
+	return err
+}
+
+// Release mengembalikan kuota promo yang dipakai transaksi yang batal, baik booking PENDING yang
+// dibatalkan maupun booking PAID yang di-refund, sehingga user dan kuota global bisa memakainya lagi
+func Release(tx *sql.Tx, transactionID int) error {
+	_, err := tx.Exec(`
+		WITH released AS (
+			DELETE FROM promotion_usages WHERE transaction_id = $1 RETURNING promotion_id
+		)
+		UPDATE promotions p
+		SET used_count = GREATEST(p.used_count - 1, 0), updated_at = NOW()
+		FROM released
+		WHERE p.id = released.promotion_id
+	`, transactionID)
+	return err
+}
+
+func toInts(values pq.Int64Array) []int {
+	ints := []int{}
+	for _, v := range values {
+		ints = append(ints, int(v))
+	}
+	return ints
+}
+
+func contains(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
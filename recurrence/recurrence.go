@@ -0,0 +1,175 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frekuensi yang didukung
+const (
+	FreqDaily  = "DAILY"
+	FreqWeekly = "WEEKLY"
+)
+
+// DateLayout format tanggal untuk UNTIL dan tanggal hasil Dates
+const DateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule subset RRULE (RFC 5545) yang cukup untuk menyusun jadwal tayang:
+// FREQ=DAILY|WEEKLY, INTERVAL, BYDAY, COUNT dan UNTIL.
+// Jam tayang tidak diambil dari rule, melainkan dari time slot.
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    *time.Time
+}
+
+// Parse membaca string RRULE, misal "FREQ=WEEKLY;BYDAY=FR,SA,SU;UNTIL=20250131".
+// Prefix "RRULE:" boleh ada atau tidak.
+func Parse(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return rule, errors.New("recurrence rule is empty")
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return rule, fmt.Errorf("invalid recurrence part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+			if rule.Freq != FreqDaily && rule.Freq != FreqWeekly {
+				return rule, fmt.Errorf("unsupported FREQ %q, use DAILY or WEEKLY", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rule, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rule, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return rule, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := weekdays[strings.ToUpper(strings.TrimSpace(day))]
+				if !ok {
+					return rule, fmt.Errorf("invalid BYDAY value %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		default:
+			return rule, fmt.Errorf("unsupported recurrence part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return rule, errors.New("FREQ is required")
+	}
+	return rule, nil
+}
+
+// Dates menghasilkan tanggal kemunculan mulai dari `from` sampai `to` (inklusif),
+// dibatasi juga oleh UNTIL dan COUNT. Tanggal dikembalikan sebagai tengah malam UTC.
+func (r Rule) Dates(from, to time.Time) []time.Time {
+	from = truncateDate(from)
+	to = truncateDate(to)
+	if r.Until != nil && r.Until.Before(to) {
+		to = truncateDate(*r.Until)
+	}
+
+	dates := []time.Time{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !r.matches(from, day) {
+			continue
+		}
+		dates = append(dates, day)
+		if r.Count > 0 && len(dates) >= r.Count {
+			break
+		}
+	}
+	return dates
+}
+
+// matches mengecek apakah sebuah tanggal termasuk kemunculan rule yang dimulai dari `start`
+func (r Rule) matches(start, day time.Time) bool {
+	daysSince := int(day.Sub(start).Hours() / 24)
+
+	switch r.Freq {
+	case FreqDaily:
+		if daysSince%r.Interval != 0 {
+			return false
+		}
+		return len(r.ByDay) == 0 || containsWeekday(r.ByDay, day.Weekday())
+	case FreqWeekly:
+		// Minggu dihitung mulai Senin, relatif terhadap minggu tanggal mulai
+		weeksSince := (daysSince + mondayOffset(start)) / 7
+		if weeksSince%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		return containsWeekday(r.ByDay, day.Weekday())
+	}
+	return false
+}
+
+// Daily rule sederhana setiap hari, dipakai jika request tidak membawa recurrence
+func Daily() Rule {
+	return Rule{Freq: FreqDaily, Interval: 1}
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", DateLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date")
+}
+
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// mondayOffset jumlah hari sejak Senin pada minggu tanggal t
+func mondayOffset(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
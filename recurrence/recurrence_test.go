@@ -0,0 +1,135 @@
+package recurrence
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	until := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	untilUTC := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  Rule
+	}{
+		{"FREQ=DAILY", Rule{Freq: FreqDaily, Interval: 1}},
+		{"RRULE:freq=weekly;interval=2", Rule{Freq: FreqWeekly, Interval: 2}},
+		{" FREQ=WEEKLY;BYDAY=FR,sa, SU ", Rule{Freq: FreqWeekly, Interval: 1, ByDay: []time.Weekday{time.Friday, time.Saturday, time.Sunday}}},
+		{"FREQ=DAILY;COUNT=5", Rule{Freq: FreqDaily, Interval: 1, Count: 5}},
+		{"FREQ=DAILY;UNTIL=20260131", Rule{Freq: FreqDaily, Interval: 1, Until: &until}},
+		{"FREQ=DAILY;UNTIL=2026-01-31", Rule{Freq: FreqDaily, Interval: 1, Until: &until}},
+		{"FREQ=DAILY;UNTIL=20260131T235959Z", Rule{Freq: FreqDaily, Interval: 1, Until: &untilUTC}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.value)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		value string
+		err   string
+	}{
+		{"", "empty"},
+		{"RRULE:", "empty"},
+		{"INTERVAL=2", "FREQ is required"},
+		{"FREQ=MONTHLY", "unsupported FREQ"},
+		{"FREQ=DAILY;INTERVAL=0", "invalid INTERVAL"},
+		{"FREQ=DAILY;INTERVAL=x", "invalid INTERVAL"},
+		{"FREQ=DAILY;COUNT=-1", "invalid COUNT"},
+		{"FREQ=DAILY;UNTIL=31-01-2026", "invalid UNTIL"},
+		{"FREQ=WEEKLY;BYDAY=XX", "invalid BYDAY"},
+		{"FREQ=WEEKLY;BYDAY=1MO", "invalid BYDAY"},
+		{"FREQ=DAILY;BYMONTH=1", "unsupported recurrence part"},
+		{"FREQ=DAILY;COUNT", "invalid recurrence part"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.value, err, tt.err)
+		}
+	}
+}
+
+func TestDates(t *testing.T) {
+	// 2026-01-07 hari Rabu
+	from := time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		rule  string
+		from  time.Time
+		to    time.Time
+		dates []string
+	}{
+		{"daily", "FREQ=DAILY", from, date("2026-01-10"),
+			[]string{"2026-01-07", "2026-01-08", "2026-01-09", "2026-01-10"}},
+		{"daily interval", "FREQ=DAILY;INTERVAL=3", from, date("2026-01-17"),
+			[]string{"2026-01-07", "2026-01-10", "2026-01-13", "2026-01-16"}},
+		{"daily interval with byday", "FREQ=DAILY;INTERVAL=2;BYDAY=SA,SU", from, date("2026-01-20"),
+			[]string{"2026-01-11", "2026-01-17"}},
+		{"weekly defaults to start weekday", "FREQ=WEEKLY", from, date("2026-01-28"),
+			[]string{"2026-01-07", "2026-01-14", "2026-01-21", "2026-01-28"}},
+		// Minggu pertama adalah minggu (Senin-Minggu) tanggal mulai, meski mulai di tengah minggu
+		{"weekly byday from midweek", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", from, date("2026-01-31"),
+			[]string{"2026-01-09", "2026-01-19", "2026-01-23"}},
+		// Minggu (SU) masih satu minggu dengan Senin sebelumnya
+		{"weekly sunday ends week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU", from, date("2026-02-01"),
+			[]string{"2026-01-11", "2026-01-25"}},
+		{"weekly start on sunday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU", date("2026-01-11"), date("2026-01-26"),
+			[]string{"2026-01-11", "2026-01-19", "2026-01-25"}},
+		{"weekly interval 3 across months", "FREQ=WEEKLY;INTERVAL=3;BYDAY=TU", date("2026-01-26"), date("2026-03-10"),
+			[]string{"2026-01-27", "2026-02-17", "2026-03-10"}},
+		{"count", "FREQ=WEEKLY;BYDAY=FR,SA,SU;COUNT=4", from, date("2026-02-28"),
+			[]string{"2026-01-09", "2026-01-10", "2026-01-11", "2026-01-16"}},
+		{"until before to", "FREQ=DAILY;UNTIL=20260109", from, date("2026-01-31"),
+			[]string{"2026-01-07", "2026-01-08", "2026-01-09"}},
+		{"until with time", "FREQ=DAILY;UNTIL=20260108T120000Z", from, date("2026-01-31"),
+			[]string{"2026-01-07", "2026-01-08"}},
+		{"until before from", "FREQ=DAILY;UNTIL=20260101", from, date("2026-01-31"), []string{}},
+		{"to before from", "FREQ=DAILY", from, date("2026-01-06"), []string{}},
+		// Jam pada from/to diabaikan
+		{"truncates time", "FREQ=DAILY", from.Add(23 * time.Hour), time.Date(2026, 1, 8, 1, 0, 0, 0, time.UTC),
+			[]string{"2026-01-07", "2026-01-08"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			got := []string{}
+			for _, d := range rule.Dates(tt.from, tt.to) {
+				got = append(got, d.Format(DateLayout))
+			}
+			if !reflect.DeepEqual(got, tt.dates) {
+				t.Errorf("Dates = %v, want %v", got, tt.dates)
+			}
+		})
+	}
+}
+
+func TestDaily(t *testing.T) {
+	dates := Daily().Dates(date("2026-01-30"), date("2026-02-02"))
+	if len(dates) != 4 || dates[3].Format(DateLayout) != "2026-02-02" {
+		t.Errorf("Daily dates = %v, want 4 days ending 2026-02-02", dates)
+	}
+}
+
+func date(value string) time.Time {
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
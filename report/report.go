@@ -0,0 +1,426 @@
+package report
+
+import (
+	"database/sql"
+	"fmt"
+	"mkp/config"
+	"mkp/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pengelompokan laporan revenue dan refund
+const (
+	GroupByDay    = "day"
+	GroupByCinema = "cinema"
+	GroupByMovie  = "movie"
+)
+
+// Urutan laporan top movies
+const (
+	SortByRevenue = "revenue"
+	SortByTickets = "tickets"
+)
+
+// DateLayout format tanggal pada filter dan kolom day
+const DateLayout = "2006-01-02"
+
+// Querier dipenuhi oleh *sql.DB dan *sql.Tx
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Filter rentang dan filter laporan. Semua laporan memakai tanggal tayang (start_time)
+// di timezone lokal cinema, From dan To inklusif.
+type Filter struct {
+	From     time.Time
+	To       time.Time
+	CinemaID *int
+	MovieID  *int
+}
+
+// Table hasil laporan yang bisa ditulis sebagai CSV
+type Table interface {
+	Header() []string
+	Records() [][]string
+}
+
+// conditions menyusun klausa WHERE dari filter dengan placeholder berurutan
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add menambah kondisi; setiap tanda ? diganti placeholder untuk value berurutan
+func (c *conditions) add(clause string, values ...interface{}) {
+	for _, value := range values {
+		clause = strings.Replace(clause, "?", c.arg(value), 1)
+	}
+	c.clauses = append(c.clauses, clause)
+}
+
+// arg menambah argumen tanpa kondisi dan mengembalikan placeholder-nya, misal "$3"
+func (c *conditions) arg(value interface{}) string {
+	c.args = append(c.args, value)
+	return "$" + strconv.Itoa(len(c.args))
+}
+
+func (c *conditions) where() string {
+	return " WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// showDate ekspresi tanggal tayang lokal cinema
+const showDate = "(s.start_time AT TIME ZONE c.timezone)::date"
+
+func newConditions(f Filter) *conditions {
+	c := &conditions{}
+	c.add(showDate+" >= ?", f.From.Format(DateLayout))
+	c.add(showDate+" <= ?", f.To.Format(DateLayout))
+	if f.CinemaID != nil {
+		c.add("c.id = ?", *f.CinemaID)
+	}
+	if f.MovieID != nil {
+		c.add("s.movie_id = ?", *f.MovieID)
+	}
+	return c
+}
+
+// groupColumns ekspresi key dan label untuk GROUP BY
+func groupColumns(groupBy string) (key, label string) {
+	switch groupBy {
+	case GroupByCinema:
+		return "c.id::text", "c.name"
+	case GroupByMovie:
+		return "m.id::text", "m.title"
+	default:
+		return "to_char(" + showDate + ", 'YYYY-MM-DD')", "to_char(" + showDate + ", 'YYYY-MM-DD')"
+	}
+}
+
+// scheduleJoins join standar dari schedules ke studio, cinema dan film
+const scheduleJoins = `
+	JOIN studios st ON s.studio_id = st.id
+	JOIN cinemas c ON st.cinema_id = c.id
+	JOIN movies m ON s.movie_id = m.id
+`
+
+// RevenueRow pendapatan satu kelompok (hari, cinema atau film) dari transaksi PAID
+type RevenueRow struct {
+	Key            string  `json:"key"`
+	Label          string  `json:"label"`
+	Transactions   int     `json:"transactions"`
+	TicketsSold    int     `json:"tickets_sold"`
+	GrossAmount    float64 `json:"gross_amount"`
+	DiscountAmount float64 `json:"discount_amount"`
+	NetAmount      float64 `json:"net_amount"`
+}
+
+// RevenueRows hasil laporan revenue
+type RevenueRows []RevenueRow
+
+// Header kolom CSV
+func (RevenueRows) Header() []string {
+	return []string{"key", "label", "transactions", "tickets_sold", "gross_amount", "discount_amount", "net_amount"}
+}
+
+// Records baris CSV
+func (rows RevenueRows) Records() [][]string {
+	records := [][]string{}
+	for _, r := range rows {
+		records = append(records, []string{
+			r.Key, Cell(r.Label), strconv.Itoa(r.Transactions), strconv.Itoa(r.TicketsSold),
+			money(r.GrossAmount), money(r.DiscountAmount), money(r.NetAmount),
+		})
+	}
+	return records
+}
+
+// Revenue laporan pendapatan transaksi PAID dikelompokkan per hari, cinema atau film
+func Revenue(q Querier, f Filter, groupBy string) (RevenueRows, error) {
+	key, label := groupColumns(groupBy)
+	c := newConditions(f)
+	c.add("tr.status = ?", models.TransactionStatusPaid)
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s, COUNT(*), COALESCE(SUM(tc.tickets), 0),
+			COALESCE(SUM(COALESCE(tr.subtotal_amount, tr.total_amount)), 0),
+			COALESCE(SUM(tr.discount_amount), 0),
+			COALESCE(SUM(tr.total_amount), 0)
+		FROM transactions tr
+		JOIN schedules s ON tr.schedule_id = s.id
+		%s
+		JOIN LATERAL (SELECT COUNT(*) AS tickets FROM tickets t WHERE t.transaction_id = tr.id) tc ON true
+		%s
+		GROUP BY 1, 2
+		ORDER BY 1
+	`, key, label, scheduleJoins, c.where())
+
+	rows, err := q.Query(query, c.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := RevenueRows{}
+	for rows.Next() {
+		var r RevenueRow
+		if err := rows.Scan(&r.Key, &r.Label, &r.Transactions, &r.TicketsSold, &r.GrossAmount, &r.DiscountAmount, &r.NetAmount); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// OccupancyRow keterisian satu jadwal tayang. OccupancyRate = tickets_sold / total_seats,
+// Revenue jumlah harga tiket sebelum diskon promo.
+type OccupancyRow struct {
+	ScheduleID     int     `json:"schedule_id"`
+	StartTimeLocal string  `json:"start_time_local"`
+	MovieID        int     `json:"movie_id"`
+	MovieTitle     string  `json:"movie_title"`
+	CinemaName     string  `json:"cinema_name"`
+	StudioName     string  `json:"studio_name"`
+	Status         string  `json:"status"`
+	TotalSeats     int     `json:"total_seats"`
+	TicketsSold    int     `json:"tickets_sold"`
+	OccupancyRate  float64 `json:"occupancy_rate"`
+	Revenue        float64 `json:"revenue"`
+}
+
+// OccupancyRows hasil laporan occupancy
+type OccupancyRows []OccupancyRow
+
+// Header kolom CSV
+func (OccupancyRows) Header() []string {
+	return []string{"schedule_id", "start_time_local", "movie_id", "movie_title", "cinema_name", "studio_name",
+		"status", "total_seats", "tickets_sold", "occupancy_rate", "revenue"}
+}
+
+// Records baris CSV
+func (rows OccupancyRows) Records() [][]string {
+	records := [][]string{}
+	for _, r := range rows {
+		records = append(records, []string{
+			strconv.Itoa(r.ScheduleID), r.StartTimeLocal, strconv.Itoa(r.MovieID), Cell(r.MovieTitle), Cell(r.CinemaName), Cell(r.StudioName),
+			r.Status, strconv.Itoa(r.TotalSeats), strconv.Itoa(r.TicketsSold), rate(r.OccupancyRate), money(r.Revenue),
+		})
+	}
+	return records
+}
+
+// Occupancy laporan keterisian per jadwal; hanya tiket dari transaksi PAID yang dihitung terjual
+func Occupancy(q Querier, f Filter) (OccupancyRows, error) {
+	c := newConditions(f)
+	c.add("s.deleted_at IS NULL")
+	paid := c.arg(models.TransactionStatusPaid)
+
+	query := fmt.Sprintf(`
+		SELECT s.id, s.start_time, c.timezone, m.id, m.title, c.name, st.name, COALESCE(s.status, ''), st.total_seats,
+			COALESCE(sold.tickets, 0), COALESCE(sold.revenue, 0)
+		FROM schedules s
+		%s
+		LEFT JOIN (
+			SELECT t.schedule_id, COUNT(*) AS tickets, SUM(t.price) AS revenue
+			FROM tickets t
+			JOIN transactions tr ON t.transaction_id = tr.id
+			WHERE tr.status = %s
+			GROUP BY t.schedule_id
+		) sold ON sold.schedule_id = s.id
+		%s
+		ORDER BY s.start_time, c.name, st.name
+	`, scheduleJoins, paid, c.where())
+
+	rows, err := q.Query(query, c.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := OccupancyRows{}
+	for rows.Next() {
+		var r OccupancyRow
+		var start time.Time
+		var timezone string
+		err := rows.Scan(&r.ScheduleID, &start, &timezone, &r.MovieID, &r.MovieTitle, &r.CinemaName, &r.StudioName,
+			&r.Status, &r.TotalSeats, &r.TicketsSold, &r.Revenue)
+		if err != nil {
+			return nil, err
+		}
+		r.StartTimeLocal = start.In(config.LoadLocation(timezone)).Format(time.RFC3339)
+		if r.TotalSeats > 0 {
+			r.OccupancyRate = float64(r.TicketsSold) / float64(r.TotalSeats)
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// TopMovieRow performa satu film pada rentang laporan
+type TopMovieRow struct {
+	Rank                 int     `json:"rank"`
+	MovieID              int     `json:"movie_id"`
+	MovieTitle           string  `json:"movie_title"`
+	Showings             int     `json:"showings"`
+	TicketsSold          int     `json:"tickets_sold"`
+	Revenue              float64 `json:"revenue"`
+	AverageOccupancyRate float64 `json:"average_occupancy_rate"`
+}
+
+// TopMovieRows hasil laporan top movies
+type TopMovieRows []TopMovieRow
+
+// Header kolom CSV
+func (TopMovieRows) Header() []string {
+	return []string{"rank", "movie_id", "movie_title", "showings", "tickets_sold", "revenue", "average_occupancy_rate"}
+}
+
+// Records baris CSV
+func (rows TopMovieRows) Records() [][]string {
+	records := [][]string{}
+	for _, r := range rows {
+		records = append(records, []string{
+			strconv.Itoa(r.Rank), strconv.Itoa(r.MovieID), Cell(r.MovieTitle), strconv.Itoa(r.Showings),
+			strconv.Itoa(r.TicketsSold), money(r.Revenue), rate(r.AverageOccupancyRate),
+		})
+	}
+	return records
+}
+
+// TopMovies film terlaris berdasarkan revenue atau jumlah tiket, dihitung dari Occupancy
+// sehingga angka tiket dan revenue konsisten dengan laporan per jadwal
+func TopMovies(q Querier, f Filter, sortBy string, limit int) (TopMovieRows, error) {
+	schedules, err := Occupancy(q, f)
+	if err != nil {
+		return nil, err
+	}
+
+	index := map[int]int{}
+	result := TopMovieRows{}
+	for _, s := range schedules {
+		if s.Status == models.ScheduleStatusCancelled {
+			continue
+		}
+		i, ok := index[s.MovieID]
+		if !ok {
+			i = len(result)
+			index[s.MovieID] = i
+			result = append(result, TopMovieRow{MovieID: s.MovieID, MovieTitle: s.MovieTitle})
+		}
+		result[i].Showings++
+		result[i].TicketsSold += s.TicketsSold
+		result[i].Revenue += s.Revenue
+		result[i].AverageOccupancyRate += s.OccupancyRate
+	}
+
+	for i := range result {
+		result[i].AverageOccupancyRate /= float64(result[i].Showings)
+	}
+	sort.SliceStable(result, func(a, b int) bool {
+		if sortBy == SortByTickets && result[a].TicketsSold != result[b].TicketsSold {
+			return result[a].TicketsSold > result[b].TicketsSold
+		}
+		if result[a].Revenue != result[b].Revenue {
+			return result[a].Revenue > result[b].Revenue
+		}
+		return result[a].TicketsSold > result[b].TicketsSold
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	for i := range result {
+		result[i].Rank = i + 1
+	}
+	return result, nil
+}
+
+// RefundRow rasio refund satu kelompok. RefundRate = refunded / (paid + refunded).
+type RefundRow struct {
+	Key                string  `json:"key"`
+	Label              string  `json:"label"`
+	PaidTransactions   int     `json:"paid_transactions"`
+	RefundTransactions int     `json:"refunded_transactions"`
+	RefundedAmount     float64 `json:"refunded_amount"`
+	RefundRate         float64 `json:"refund_rate"`
+}
+
+// RefundRows hasil laporan refund
+type RefundRows []RefundRow
+
+// Header kolom CSV
+func (RefundRows) Header() []string {
+	return []string{"key", "label", "paid_transactions", "refunded_transactions", "refunded_amount", "refund_rate"}
+}
+
+// Records baris CSV
+func (rows RefundRows) Records() [][]string {
+	records := [][]string{}
+	for _, r := range rows {
+		records = append(records, []string{
+			r.Key, Cell(r.Label), strconv.Itoa(r.PaidTransactions), strconv.Itoa(r.RefundTransactions),
+			money(r.RefundedAmount), rate(r.RefundRate),
+		})
+	}
+	return records
+}
+
+// Refunds laporan rasio refund dikelompokkan per hari, cinema atau film
+func Refunds(q Querier, f Filter, groupBy string) (RefundRows, error) {
+	key, label := groupColumns(groupBy)
+	c := newConditions(f)
+	paid := c.arg(models.TransactionStatusPaid)
+	refunded := c.arg(models.TransactionStatusRefunded)
+	c.add("tr.status IN (" + paid + ", " + refunded + ")")
+
+	query := fmt.Sprintf(`
+		SELECT %[1]s, %[2]s,
+			COUNT(*) FILTER (WHERE tr.status = %[5]s),
+			COUNT(*) FILTER (WHERE tr.status = %[6]s),
+			COALESCE(SUM(tr.total_amount) FILTER (WHERE tr.status = %[6]s), 0)
+		FROM transactions tr
+		JOIN schedules s ON tr.schedule_id = s.id
+		%[3]s
+		%[4]s
+		GROUP BY 1, 2
+		ORDER BY 1
+	`, key, label, scheduleJoins, c.where(), paid, refunded)
+
+	rows, err := q.Query(query, c.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := RefundRows{}
+	for rows.Next() {
+		var r RefundRow
+		if err := rows.Scan(&r.Key, &r.Label, &r.PaidTransactions, &r.RefundTransactions, &r.RefundedAmount); err != nil {
+			return nil, err
+		}
+		if total := r.PaidTransactions + r.RefundTransactions; total > 0 {
+			r.RefundRate = float64(r.RefundTransactions) / float64(total)
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// Cell mengamankan teks bebas (judul film, nama cinema) untuk sel CSV: teks yang diawali
+// =, +, -, @, tab atau carriage return diberi prefix ' agar tidak dieksekusi sebagai formula oleh aplikasi spreadsheet
+func Cell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func money(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func rate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
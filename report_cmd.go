@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mkp/config"
+	"mkp/report"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// runReport subcommand laporan
+func runReport(args []string) int {
+	if len(args) == 0 || args[0] != "sales" {
+		fmt.Fprintln(os.Stderr, "usage: report sales [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-cinema id] [-movie id] [-group-by day|cinema|movie] [-format table|csv|json]")
+		return 2
+	}
+	return runReportSales(args[1:])
+}
+
+// runReportSales laporan penjualan (transaksi PAID) sama seperti GET /api/admin/reports/revenue.
+// Default 30 hari terakhir sampai hari ini.
+func runReportSales(args []string) int {
+	fs := flag.NewFlagSet("report sales", flag.ContinueOnError)
+	from := fs.String("from", "", "tanggal tayang awal YYYY-MM-DD (default 29 hari sebelum -to)")
+	to := fs.String("to", "", "tanggal tayang akhir YYYY-MM-DD (default hari ini)")
+	cinemaID := fs.Int("cinema", 0, "filter ID cinema")
+	movieID := fs.Int("movie", 0, "filter ID film")
+	groupBy := fs.String("group-by", report.GroupByDay, "day, cinema atau movie")
+	format := fs.String("format", "table", "table, csv atau json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	now := time.Now().In(config.LoadLocation(""))
+	filter := report.Filter{To: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)}
+	if *to != "" {
+		date, err := time.Parse(report.DateLayout, *to)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "-to must use format YYYY-MM-DD")
+			return 2
+		}
+		filter.To = date
+	}
+	filter.From = filter.To.AddDate(0, 0, -29)
+	if *from != "" {
+		date, err := time.Parse(report.DateLayout, *from)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "-from must use format YYYY-MM-DD")
+			return 2
+		}
+		filter.From = date
+	}
+	if filter.To.Before(filter.From) {
+		fmt.Fprintln(os.Stderr, "-to must not be before -from")
+		return 2
+	}
+	if *cinemaID > 0 {
+		filter.CinemaID = cinemaID
+	}
+	if *movieID > 0 {
+		filter.MovieID = movieID
+	}
+	switch *groupBy {
+	case report.GroupByDay, report.GroupByCinema, report.GroupByMovie:
+	default:
+		fmt.Fprintln(os.Stderr, "-group-by must be one of: day, cinema, movie")
+		return 2
+	}
+	if *format != "table" && *format != "csv" && *format != "json" {
+		fmt.Fprintln(os.Stderr, "-format must be one of: table, csv, json")
+		return 2
+	}
+
+	config.InitDB()
+	defer config.CloseDB()
+
+	rows, err := report.Revenue(config.DB, filter, *groupBy)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "report sales:", err)
+		return 1
+	}
+
+	switch *format {
+	case "json":
+		out, _ := json.MarshalIndent(rows, "", "  ")
+		fmt.Println(string(out))
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		writer.Write(rows.Header())
+		writer.WriteAll(rows.Records())
+	default:
+		writeReportTable(rows, filter)
+	}
+	return 0
+}
+
+// writeReportTable menulis laporan sebagai tabel teks beserta baris total
+func writeReportTable(rows report.RevenueRows, filter report.Filter) {
+	fmt.Printf("Sales %s to %s\n\n", filter.From.Format(report.DateLayout), filter.To.Format(report.DateLayout))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, strings.Join(rows.Header()[1:], "\t")+"\t")
+
+	var total report.RevenueRow
+	for i, record := range rows.Records() {
+		fmt.Fprintln(w, strings.Join(record[1:], "\t")+"\t")
+		total.Transactions += rows[i].Transactions
+		total.TicketsSold += rows[i].TicketsSold
+		total.GrossAmount += rows[i].GrossAmount
+		total.DiscountAmount += rows[i].DiscountAmount
+		total.NetAmount += rows[i].NetAmount
+	}
+	fmt.Fprintf(w, "TOTAL\t%d\t%d\t%.2f\t%.2f\t%.2f\t\n",
+		total.Transactions, total.TicketsSold, total.GrossAmount, total.DiscountAmount, total.NetAmount)
+	w.Flush()
+}
@@ -1,28 +1,24 @@
--- Insert sample movies
-INSERT INTO movies (title, description, duration_minutes, release_date, created_at)
-VALUES 
-('Spider-Man: No Way Home', 'Peter Parker mengatasi konsekuensi dari identitas Spider-Man yang terungkap', 148, '2021-12-15', NOW()),
-('Avatar: The Way of Water', 'Jake Sully dan keluarganya berjuang untuk tetap bersama', 192, '2022-12-14', NOW()),
-('Top Gun: Maverick', 'Pilot test yang berani menghadapi masa lalu dan masa depan', 130, '2022-05-24', NOW());
-
--- Insert sample cinemas
-INSERT INTO cinemas (name, city, address, created_at)
-VALUES 
-('MKP XXI Jakarta Pusat', 'Jakarta', 'Jl. Thamrin No. 1', NOW()),
-('MKP XXI Bandung', 'Bandung', 'Jl. Asia Afrika No. 10', NOW());
+-- Semua data contoh (file ini dan CSV di folder sample_data/) bisa diisi sekaligus dengan:
+--   go run . seed
+-- Data katalog (cinemas, studios beserta kursinya, movies, schedules) di-import dari CSV di folder sample_data/:
+--   go run . import -entity cinemas -file sample_data/cinemas.csv
+--   go run . import -entity studios -file sample_data/studios.csv
+--   go run . import -entity movies -file sample_data/movies.csv
+--   go run . import -entity schedules -file sample_data/schedules.csv
 
--- Insert sample studios
-INSERT INTO studios (cinema_id, name, total_seats, created_at)
+-- Insert sample holidays
+INSERT INTO holidays (holiday_date, name)
 VALUES 
-(1, 'Studio 1', 100, NOW()),
-(1, 'Studio 2', 80, NOW()),
-(1, 'IMAX', 150, NOW()),
-(2, 'Studio 1', 100, NOW());
+('2024-12-25', 'Hari Raya Natal'),
+('2025-01-01', 'Tahun Baru Masehi');
 
--- Insert sample schedules
-INSERT INTO schedules (movie_id, studio_id, start_time, end_time, price, status, created_at)
+-- Insert sample pricing rules
+INSERT INTO pricing_rules (name, days_of_week, time_from, time_to, studio_type, seat_category, holiday_only, adjustment_type, adjustment_value, priority)
 VALUES 
-(1, 1, '2024-12-05 14:00:00', '2024-12-05 16:30:00', 50000, 'SHOWING', NOW()),
-(1, 1, '2024-12-05 19:00:00', '2024-12-05 21:30:00', 50000, 'SHOWING', NOW()),
-(2, 3, '2024-12-05 15:00:00', '2024-12-05 18:15:00', 75000, 'SHOWING', NOW()),
-(3, 2, '2024-12-05 16:00:00', '2024-12-05 18:15:00', 45000, 'SHOWING', NOW());
\ No newline at end of file
+('Weekend', '{0,6}', NULL, NULL, NULL, NULL, false, 'FIXED', 10000, 10),
+('Matinee', NULL, '10:00', '13:00', NULL, NULL, false, 'PERCENTAGE', -20, 20),
+('Prime time', NULL, '17:00', '21:00', NULL, NULL, false, 'FIXED', 5000, 20),
+('IMAX', NULL, NULL, NULL, 'IMAX', NULL, false, 'PERCENTAGE', 30, 30),
+('Premium row', NULL, NULL, NULL, NULL, 'PREMIUM', false, 'FIXED', 15000, 40),
+('Couple seat', NULL, NULL, NULL, NULL, 'COUPLE', false, 'FIXED', 25000, 40),
+('Holiday', NULL, NULL, NULL, NULL, NULL, true, 'PERCENTAGE', 25, 50);
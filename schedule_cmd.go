@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/events"
+	"mkp/models"
+	"mkp/waitlist"
+	"os"
+	"time"
+)
+
+// cancelledSchedule jadwal yang dibatalkan subcommand schedule cancel
+type cancelledSchedule struct {
+	ID        int
+	Movie     string
+	StartTime time.Time
+	Paid      int
+}
+
+// runSchedule subcommand pengelolaan jadwal
+func runSchedule(args []string) int {
+	if len(args) == 0 || args[0] != "cancel" {
+		fmt.Fprintln(os.Stderr, "usage: schedule cancel -studio <id> -from YYYY-MM-DD -to YYYY-MM-DD [-dry-run]")
+		return 2
+	}
+	return runScheduleCancel(args[1:])
+}
+
+// runScheduleCancel membatalkan semua jadwal sebuah studio yang tayang (tanggal lokal cinema)
+// antara -from dan -to, misal karena studio maintenance. Semua jadwal dibatalkan dalam satu
+// transaksi lewat waitlist.CancelShowing, rutin yang juga dipakai PUT/PATCH status CANCELLED:
+// pemilik tiket diberi notifikasi, booking PENDING dan waitlist ditutup dan webhook
+// schedule.cancelled dikirim. Setelah commit event ScheduleCancelled di-publish di proses ini;
+// subscriber yang hanya terdaftar di server API tidak menerimanya. Booking PAID tetap ada dan
+// perlu di-refund.
+func runScheduleCancel(args []string) int {
+	fs := flag.NewFlagSet("schedule cancel", flag.ContinueOnError)
+	studioID := fs.Int("studio", 0, "ID studio")
+	from := fs.String("from", "", "tanggal tayang awal YYYY-MM-DD (inklusif)")
+	to := fs.String("to", "", "tanggal tayang akhir YYYY-MM-DD (inklusif)")
+	dryRun := fs.Bool("dry-run", false, "tampilkan jadwal yang akan dibatalkan tanpa mengubah data")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	fromDate, errFrom := time.Parse(time.DateOnly, *from)
+	toDate, errTo := time.Parse(time.DateOnly, *to)
+	if *studioID <= 0 || errFrom != nil || errTo != nil {
+		fmt.Fprintln(os.Stderr, "usage: schedule cancel -studio <id> -from YYYY-MM-DD -to YYYY-MM-DD [-dry-run]")
+		return 2
+	}
+	if toDate.Before(fromDate) {
+		fmt.Fprintln(os.Stderr, "-to must not be before -from")
+		return 2
+	}
+
+	config.InitDB()
+	defer config.CloseDB()
+
+	var studio, timezone string
+	err := config.DB.QueryRow(`
+		SELECT st.name || ' (' || c.name || ')', c.timezone
+		FROM studios st JOIN cinemas c ON st.cinema_id = c.id
+		WHERE st.id = $1
+	`, *studioID).Scan(&studio, &timezone)
+	if err == sql.ErrNoRows {
+		fmt.Fprintln(os.Stderr, "studio not found:", *studioID)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "schedule cancel:", err)
+		return 1
+	}
+
+	cancelled, err := cancelStudioSchedules(*studioID, *from, *to, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "schedule cancel:", err)
+		return 1
+	}
+
+	loc := config.LoadLocation(timezone)
+	paid := 0
+	for _, schedule := range cancelled {
+		fmt.Printf("%d\t%s\t%s\t%d paid bookings\n", schedule.ID, schedule.StartTime.In(loc).Format("2006-01-02 15:04"),
+			schedule.Movie, schedule.Paid)
+		paid += schedule.Paid
+	}
+	verb := "Cancelled"
+	if *dryRun {
+		verb = "Would cancel"
+	}
+	fmt.Printf("%s %d schedules in %s from %s to %s", verb, len(cancelled), studio, *from, *to)
+	if paid > 0 {
+		fmt.Printf("; %d paid bookings need a refund", paid)
+	}
+	fmt.Println()
+	return 0
+}
+
+// cancelStudioSchedules membatalkan jadwal studio yang belum dihapus atau dibatalkan dalam
+// rentang tanggal. Dengan dryRun transaksi di-rollback.
+func cancelStudioSchedules(studioID int, from, to string, dryRun bool) ([]cancelledSchedule, error) {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT s.id, m.title, s.start_time,
+			(SELECT COUNT(*) FROM transactions tr WHERE tr.schedule_id = s.id AND tr.status = $5)
+		FROM schedules s
+		JOIN movies m ON s.movie_id = m.id
+		JOIN studios st ON s.studio_id = st.id
+		JOIN cinemas c ON st.cinema_id = c.id
+		WHERE s.studio_id = $1 AND s.deleted_at IS NULL AND COALESCE(s.status, '') <> $2
+			AND (s.start_time AT TIME ZONE c.timezone)::date BETWEEN $3 AND $4
+		ORDER BY s.start_time
+		FOR UPDATE OF s
+	`, studioID, models.ScheduleStatusCancelled, from, to, models.TransactionStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+	cancelled := []cancelledSchedule{}
+	for rows.Next() {
+		var schedule cancelledSchedule
+		if err := rows.Scan(&schedule.ID, &schedule.Movie, &schedule.StartTime, &schedule.Paid); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		cancelled = append(cancelled, schedule)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return cancelled, nil
+	}
+
+	for _, schedule := range cancelled {
+		if err := cancelSchedule(tx, schedule.ID); err != nil {
+			return nil, fmt.Errorf("schedule %d: %w", schedule.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	for _, schedule := range cancelled {
+		events.Publish(context.Background(), events.ScheduleCancelled{ScheduleID: schedule.ID})
+	}
+	return cancelled, nil
+}
+
+// cancelSchedule membatalkan satu jadwal lewat waitlist.CancelShowing dan mencatat audit log
+func cancelSchedule(tx *sql.Tx, id int) error {
+	before, err := audit.Snapshot(tx, "schedules", id)
+	if err != nil {
+		return err
+	}
+	if err := waitlist.CancelShowing(tx, cliActor, id); err != nil {
+		return err
+	}
+	after, err := audit.Snapshot(tx, "schedules", id)
+	if err != nil {
+		return err
+	}
+	return audit.Record(tx, cliActor, audit.ActionUpdate, "schedules", &id, before, after)
+}
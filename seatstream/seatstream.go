@@ -0,0 +1,183 @@
+package seatstream
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Channel nama channel PostgreSQL untuk perubahan kursi
+const Channel = "seat_changes"
+
+// Status kursi yang dikirim ke client
+const (
+	StateHeld     = "held"
+	StateReleased = "released"
+	StateSold     = "sold"
+)
+
+// maxSeatsPerNotify batas kursi per NOTIFY agar payload tetap di bawah batas 8000 byte PostgreSQL
+const maxSeatsPerNotify = 500
+
+// bufferSize jumlah perubahan yang boleh antri per client sebelum client dianggap terlalu lambat
+const bufferSize = 64
+
+// Change perubahan status sekumpulan kursi pada satu jadwal
+type Change struct {
+	ScheduleID int    `json:"schedule_id"`
+	State      string `json:"state"`
+	SeatIDs    []int  `json:"seat_ids"`
+}
+
+// Querier dipenuhi oleh *sql.Tx
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Notify mengirim perubahan status kursi tiket sebuah booking lewat pg_notify. Dipanggil di dalam
+// transaksi perubahan booking: PostgreSQL baru mengirim notifikasi saat commit dan membuangnya
+// saat rollback, sehingga client tidak pernah melihat status yang tidak jadi.
+func Notify(tx Querier, transactionID int, state string) error {
+	rows, err := tx.Query("SELECT schedule_id, seat_id FROM tickets WHERE transaction_id = $1 ORDER BY seat_id", transactionID)
+	if err != nil {
+		return err
+	}
+	var scheduleID int
+	seatIDs := []int{}
+	for rows.Next() {
+		var seatID int
+		if err := rows.Scan(&scheduleID, &seatID); err != nil {
+			rows.Close()
+			return err
+		}
+		seatIDs = append(seatIDs, seatID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for len(seatIDs) > 0 {
+		n := len(seatIDs)
+		if n > maxSeatsPerNotify {
+			n = maxSeatsPerNotify
+		}
+		payload, err := json.Marshal(Change{ScheduleID: scheduleID, State: state, SeatIDs: seatIDs[:n]})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("SELECT pg_notify($1, $2)", Channel, string(payload)); err != nil {
+			return err
+		}
+		seatIDs = seatIDs[n:]
+	}
+	return nil
+}
+
+// Hub membagikan notifikasi dari satu koneksi LISTEN ke semua client stream di instance ini
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan Change]struct{}
+}
+
+// DefaultHub hub yang diisi oleh Listen dan dipakai handler stream
+var DefaultHub = NewHub()
+
+// NewHub membuat hub kosong
+func NewHub() *Hub {
+	return &Hub{subscribers: map[int]map[chan Change]struct{}{}}
+}
+
+// Subscribe mendaftarkan client untuk satu jadwal. Channel ditutup jika client terlalu lambat
+// atau koneksi LISTEN sempat putus (ada notifikasi yang mungkin hilang); client harus
+// menyambung ulang dan mengambil snapshot baru. Panggil cancel saat client selesai.
+func (h *Hub) Subscribe(scheduleID int) (<-chan Change, func()) {
+	ch := make(chan Change, bufferSize)
+	h.mu.Lock()
+	if h.subscribers[scheduleID] == nil {
+		h.subscribers[scheduleID] = map[chan Change]struct{}{}
+	}
+	h.subscribers[scheduleID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[scheduleID][ch]; ok {
+			h.remove(scheduleID, ch)
+		}
+	}
+	return ch, cancel
+}
+
+// Broadcast meneruskan perubahan ke semua client jadwal tersebut tanpa menunggu client lambat
+func (h *Hub) Broadcast(change Change) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers[change.ScheduleID] {
+		select {
+		case ch <- change:
+		default:
+			h.remove(change.ScheduleID, ch)
+		}
+	}
+}
+
+// Reset memutus semua client, dipanggil setelah koneksi LISTEN tersambung ulang
+func (h *Hub) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for scheduleID, chans := range h.subscribers {
+		for ch := range chans {
+			h.remove(scheduleID, ch)
+		}
+	}
+}
+
+// remove menutup channel client; h.mu harus sudah dikunci
+func (h *Hub) remove(scheduleID int, ch chan Change) {
+	delete(h.subscribers[scheduleID], ch)
+	if len(h.subscribers[scheduleID]) == 0 {
+		delete(h.subscribers, scheduleID)
+	}
+	close(ch)
+}
+
+// Listen membuka koneksi LISTEN ke Channel dan meneruskan notifikasinya ke DefaultHub.
+// Dipanggil sebagai goroutine dari main; setiap instance API punya koneksi LISTEN sendiri,
+// sehingga perubahan dari instance lain juga sampai ke client di instance ini.
+func Listen(connString string) {
+	listener := pq.NewListener(connString, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println("seat stream listener:", err)
+		}
+	})
+	if err := listener.Listen(Channel); err != nil {
+		log.Println("seat stream listener:", err)
+	}
+
+	for {
+		select {
+		case notification := <-listener.Notify:
+			// nil berarti koneksi baru tersambung ulang dan notifikasi di antaranya bisa hilang
+			if notification == nil {
+				DefaultHub.Reset()
+				continue
+			}
+			var change Change
+			if err := json.Unmarshal([]byte(notification.Extra), &change); err != nil {
+				log.Println("seat stream listener: invalid payload:", err)
+				continue
+			}
+			DefaultHub.Broadcast(change)
+		case <-time.After(90 * time.Second):
+			// Ping memastikan koneksi yang diam masih hidup
+			go listener.Ping()
+		}
+	}
+}
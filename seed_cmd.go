@@ -0,0 +1,148 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/importer"
+	"mkp/seeder"
+	"os"
+	"time"
+)
+
+// sampleData data contoh: katalog dalam CSV dan holidays serta pricing rules dalam SQL
+//
+//go:embed sample_data.sql sample_data/*.csv
+var sampleData embed.FS
+
+// runSeed mengisi database dengan data contoh. Tanpa -generate katalog kecil di sample_data/
+// di-import lewat importer (upsert by id, aman dijalankan ulang). Dengan -generate dataset
+// dibangkitkan oleh package seeder dari seed RNG sehingga bisa diulang persis sama; -start
+// menentukan hari acuan jendela jadwal (default hari ini). Pada keduanya sample_data.sql
+// (holidays dan pricing rules) dijalankan jika pricing_rules masih kosong.
+func runSeed(args []string) int {
+	defaults := seeder.DefaultConfig()
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	generate := fs.Bool("generate", false, "bangkitkan dataset dengan ukuran dari flag di bawah")
+	seed := fs.Uint64("seed", defaults.Seed, "seed RNG, nilai yang sama menghasilkan data yang sama")
+	start := fs.String("start", defaults.Start.Format(time.DateOnly), "hari acuan YYYY-MM-DD: jadwal sebelumnya sudah lewat dan punya transaksi")
+	cities := fs.Int("cities", defaults.Cities, "jumlah kota")
+	cinemas := fs.Int("cinemas-per-city", defaults.CinemasPerCity, "jumlah cinema per kota")
+	studios := fs.Int("studios-per-cinema", defaults.StudiosPerCinema, "jumlah studio per cinema")
+	movies := fs.Int("movies", defaults.Movies, "jumlah film")
+	users := fs.Int("users", defaults.Users, "jumlah customer")
+	historyDays := fs.Int("history-days", defaults.HistoryDays, "jumlah hari jadwal sebelum -start beserta transaksinya")
+	days := fs.Int("days", defaults.Days, "jumlah hari jadwal mulai -start")
+	showsPerDay := fs.Int("shows-per-day", defaults.ShowsPerDay, "maksimum jadwal per studio per hari")
+	occupancy := fs.Float64("occupancy", defaults.Occupancy, "rata-rata porsi kursi terjual jadwal yang sudah lewat (0-1)")
+	password := fs.String("password", defaults.Password, "password semua customer hasil generate")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := seeder.Config{
+		Seed:             *seed,
+		Cities:           *cities,
+		CinemasPerCity:   *cinemas,
+		StudiosPerCinema: *studios,
+		Movies:           *movies,
+		Users:            *users,
+		HistoryDays:      *historyDays,
+		Days:             *days,
+		ShowsPerDay:      *showsPerDay,
+		Occupancy:        *occupancy,
+		Password:         *password,
+	}
+	if *generate {
+		var err error
+		if cfg.Start, err = time.Parse(time.DateOnly, *start); err != nil {
+			fmt.Fprintln(os.Stderr, "-start must use format YYYY-MM-DD")
+			return 2
+		}
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintln(os.Stderr, "seed:", err)
+			return 2
+		}
+	}
+
+	config.InitDB()
+	defer config.CloseDB()
+
+	// Pricing rules lebih dulu agar harga tiket hasil generate sudah memakai rule
+	if err := seedPricing(); err != nil {
+		fmt.Fprintln(os.Stderr, "seed:", err)
+		return 1
+	}
+	if *generate {
+		return seedGenerate(cfg)
+	}
+	return seedSample()
+}
+
+// seedPricing menjalankan sample_data.sql jika pricing_rules masih kosong
+func seedPricing() error {
+	var hasRules bool
+	if err := config.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM pricing_rules)").Scan(&hasRules); err != nil {
+		return err
+	}
+	if hasRules {
+		fmt.Println("pricing rules already present, skipping sample_data.sql")
+		return nil
+	}
+	script, err := sampleData.ReadFile("sample_data.sql")
+	if err != nil {
+		return err
+	}
+	if _, err := config.DB.Exec(string(script)); err != nil {
+		return fmt.Errorf("sample_data.sql: %w", err)
+	}
+	fmt.Println("holidays and pricing rules inserted")
+	return nil
+}
+
+// seedSample meng-import katalog CSV di sample_data/
+func seedSample() int {
+	for _, entity := range importer.Entities() {
+		f, err := sampleData.Open("sample_data/" + entity + ".csv")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "seed:", err)
+			return 1
+		}
+		result, err := importer.Import(config.DB, cliActor, entity, importer.FormatCSV, f, false)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "seed %s: %v\n", entity, err)
+			return 1
+		}
+		if len(result.Errors) > 0 {
+			out, _ := json.MarshalIndent(result.Errors, "", "  ")
+			fmt.Fprintf(os.Stderr, "seed %s: invalid rows\n%s\n", entity, out)
+			return 1
+		}
+		fmt.Printf("%s: %d inserted, %d updated\n", entity, result.Inserted, result.Updated)
+	}
+	return 0
+}
+
+// seedGenerate membangkitkan dataset dan mencatat ringkasannya ke audit log
+func seedGenerate(cfg seeder.Config) int {
+	started := time.Now()
+	summary, err := seeder.Generate(config.DB, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "seed:", err)
+		return 1
+	}
+
+	out, _ := json.MarshalIndent(summary, "", "  ")
+	fmt.Println(string(out))
+	fmt.Printf("generated in %s; customers log in with password %q\n", time.Since(started).Round(time.Millisecond), cfg.Password)
+
+	record, _ := json.Marshal(map[string]interface{}{"seed": cfg.Seed, "start": cfg.Start.Format(time.DateOnly), "summary": summary})
+	if err := audit.Record(config.DB, cliActor, audit.ActionImport, "seed", nil, nil, record); err != nil {
+		fmt.Fprintln(os.Stderr, "audit:", err)
+	}
+	return 0
+}
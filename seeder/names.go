@@ -0,0 +1,80 @@
+package seeder
+
+// city kota beserta timezone IANA-nya
+type city struct {
+	Name     string
+	Timezone string
+}
+
+var cities = []city{
+	{"Jakarta", "Asia/Jakarta"},
+	{"Surabaya", "Asia/Jakarta"},
+	{"Bandung", "Asia/Jakarta"},
+	{"Medan", "Asia/Jakarta"},
+	{"Makassar", "Asia/Makassar"},
+	{"Semarang", "Asia/Jakarta"},
+	{"Denpasar", "Asia/Makassar"},
+	{"Yogyakarta", "Asia/Jakarta"},
+	{"Palembang", "Asia/Jakarta"},
+	{"Balikpapan", "Asia/Makassar"},
+	{"Pontianak", "Asia/Pontianak"},
+	{"Manado", "Asia/Makassar"},
+	{"Malang", "Asia/Jakarta"},
+	{"Padang", "Asia/Jakarta"},
+	{"Pekanbaru", "Asia/Jakarta"},
+	{"Banjarmasin", "Asia/Makassar"},
+	{"Jayapura", "Asia/Jayapura"},
+	{"Ambon", "Asia/Jayapura"},
+	{"Batam", "Asia/Jakarta"},
+	{"Samarinda", "Asia/Makassar"},
+}
+
+var malls = []string{
+	"Grand Mall", "City Square", "Plaza", "Town Square", "Central Park", "Trade Center",
+	"Mega Mall", "Galeria", "Sun Plaza", "Lippo Mall", "Paragon", "Festival Walk",
+}
+
+var streets = []string{
+	"Sudirman", "Thamrin", "Gatot Subroto", "Diponegoro", "Ahmad Yani", "Gajah Mada",
+	"Pemuda", "Merdeka", "Asia Afrika", "Pahlawan", "Veteran", "Hayam Wuruk",
+}
+
+var titleWords = []string{
+	"Midnight", "Shadow", "Crimson", "Silent", "Last", "Broken", "Golden", "Hidden", "Eternal",
+	"Wild", "Lost", "Iron", "Frozen", "Burning", "Distant", "Secret", "Fallen", "Rising",
+}
+
+var titleNouns = []string{
+	"Horizon", "Kingdom", "Echo", "Voyage", "Legacy", "Storm", "Garden", "Empire", "Signal",
+	"Harbor", "Frontier", "Dream", "Protocol", "River", "Mirror", "Island", "Promise", "Code",
+}
+
+var subtitles = []string{
+	"Part Two", "Reborn", "The Beginning", "Final Chapter", "Returns", "Awakening", "Origins",
+}
+
+var genres = []string{
+	"Film aksi", "Drama keluarga", "Komedi romantis", "Film horor", "Petualangan fantasi",
+	"Thriller psikologis", "Fiksi ilmiah", "Animasi keluarga",
+}
+
+var plots = []string{
+	"tentang seorang detektif yang memburu dalang di balik serangkaian kejadian misterius",
+	"tentang sekelompok sahabat yang melakukan perjalanan terakhir sebelum berpisah",
+	"tentang keluarga yang pindah ke rumah tua dengan masa lalu kelam",
+	"tentang kru pesawat luar angkasa yang terdampar di planet asing",
+	"tentang pemuda desa yang menemukan kekuatan tersembunyi",
+	"tentang dua rival yang terpaksa bekerja sama untuk menyelamatkan kota",
+	"tentang koki muda yang mengejar mimpinya membuka restoran sendiri",
+	"tentang agen rahasia yang dikhianati oleh organisasinya sendiri",
+}
+
+var firstNames = []string{
+	"Andi", "Budi", "Citra", "Dewi", "Eko", "Fajar", "Gita", "Hadi", "Indah", "Joko", "Kartika",
+	"Lestari", "Made", "Nadia", "Oki", "Putri", "Rizky", "Sari", "Teguh", "Utami", "Wahyu", "Yuni",
+}
+
+var lastNames = []string{
+	"Pratama", "Saputra", "Wijaya", "Santoso", "Hidayat", "Nugroho", "Kurniawan", "Lestari",
+	"Siregar", "Situmorang", "Halim", "Gunawan", "Setiawan", "Permana", "Wibowo", "Susanto",
+}
@@ -0,0 +1,646 @@
+// Package seeder membangkitkan dataset contoh yang ukurannya bisa diatur untuk development dan
+// load testing: kota, cinema, studio beserta layout kursinya, film, user, jadwal dalam jendela
+// tanggal di sekitar Config.Start, serta transaksi dan tiket historis. Semua nilai diambil dari
+// RNG ber-seed sehingga Config yang sama pada database kosong selalu menghasilkan baris yang sama
+// (termasuk id); hanya password_hash yang berbeda karena bcrypt memakai salt acak.
+package seeder
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"mkp/config"
+	"mkp/models"
+	"mkp/pricing"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrNotEmpty database sudah berisi katalog atau user hasil generate sebelumnya
+var ErrNotEmpty = errors.New("database already has cinemas, movies or generated users; seed generator needs an empty catalogue")
+
+// emailDomain domain email user hasil generate
+const emailDomain = "example.com"
+
+// movieRunDays lama sebuah film tayang sejak tanggal rilis
+const movieRunDays = 60
+
+// Config ukuran dan seed dataset
+type Config struct {
+	Seed             uint64
+	Start            time.Time // hari acuan; jadwal sebelum tanggal ini sudah lewat dan punya transaksi historis
+	Cities           int
+	CinemasPerCity   int
+	StudiosPerCinema int
+	Movies           int
+	Users            int
+	HistoryDays      int     // jumlah hari jadwal sebelum Start
+	Days             int     // jumlah hari jadwal mulai Start
+	ShowsPerDay      int     // maksimum jadwal per studio per hari
+	Occupancy        float64 // rata-rata porsi kursi terjual jadwal yang sudah lewat, 0..1
+	Password         string  // password semua user hasil generate
+}
+
+// DefaultConfig dataset kecil yang cukup untuk development, Start hari ini
+func DefaultConfig() Config {
+	now := time.Now().In(config.LoadLocation(""))
+	return Config{
+		Seed:             1,
+		Start:            time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Cities:           3,
+		CinemasPerCity:   2,
+		StudiosPerCinema: 4,
+		Movies:           20,
+		Users:            200,
+		HistoryDays:      30,
+		Days:             14,
+		ShowsPerDay:      5,
+		Occupancy:        0.35,
+		Password:         "password123",
+	}
+}
+
+// Validate memastikan ukuran dataset masuk akal
+func (c Config) Validate() error {
+	switch {
+	case c.Cities < 1 || c.Cities > len(cities):
+		return fmt.Errorf("cities must be between 1 and %d", len(cities))
+	case c.CinemasPerCity < 1 || c.CinemasPerCity > len(malls):
+		return fmt.Errorf("cinemas per city must be between 1 and %d", len(malls))
+	case c.StudiosPerCinema < 1 || c.StudiosPerCinema > 20:
+		return errors.New("studios per cinema must be between 1 and 20")
+	case c.Movies < 1:
+		return errors.New("movies must be at least 1")
+	case c.Users < 0:
+		return errors.New("users must not be negative")
+	case c.HistoryDays < 0 || c.Days < 0 || c.HistoryDays+c.Days > 366:
+		return errors.New("history days and days must not be negative and cover at most 366 days")
+	case c.ShowsPerDay < 1 || c.ShowsPerDay > 8:
+		return errors.New("shows per day must be between 1 and 8")
+	case c.Occupancy < 0 || c.Occupancy > 1:
+		return errors.New("occupancy must be between 0 and 1")
+	case c.Occupancy > 0 && c.Users == 0:
+		return errors.New("users must be at least 1 to generate transactions")
+	case len(c.Password) < 6:
+		return errors.New("password must be at least 6 characters")
+	}
+	return nil
+}
+
+// Summary jumlah baris yang dibuat
+type Summary struct {
+	Cities       int `json:"cities"`
+	Cinemas      int `json:"cinemas"`
+	Studios      int `json:"studios"`
+	Seats        int `json:"seats"`
+	Movies       int `json:"movies"`
+	Users        int `json:"users"`
+	Schedules    int `json:"schedules"`
+	Transactions int `json:"transactions"`
+	Tickets      int `json:"tickets"`
+}
+
+type cinema struct {
+	id        int
+	loc       *time.Location
+	basePrice float64
+}
+
+type studio struct {
+	id         int
+	cinema     *cinema
+	studioType string
+	seats      []seat
+}
+
+type seat struct {
+	pricing.Seat
+	row    int
+	number int
+}
+
+type movie struct {
+	id         int
+	duration   int
+	release    time.Time
+	popularity float64
+}
+
+type schedule struct {
+	id         int
+	studio     *studio
+	movie      *movie
+	start, end time.Time
+	price      float64
+}
+
+// generator menyimpan state selama satu kali Generate
+type generator struct {
+	cfg      Config
+	rng      *rand.Rand
+	tx       *sql.Tx
+	rules    []models.PricingRule
+	holidays map[string]bool
+	nextID   map[string]int
+	summary  Summary
+
+	studios []*studio
+	movies  []*movie
+	users   []int
+}
+
+// Generate membuat dataset dalam satu transaksi. Katalog (cinemas dan movies) harus masih kosong
+// agar id dan relasinya sama setiap kali dijalankan. Harga tiket dihitung dengan pricing rule
+// yang aktif, jadi isi pricing_rules dan holidays sebelum memanggil Generate.
+func Generate(db *sql.DB, cfg Config) (Summary, error) {
+	if err := cfg.Validate(); err != nil {
+		return Summary{}, err
+	}
+	cfg.Start = time.Date(cfg.Start.Year(), cfg.Start.Month(), cfg.Start.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Satu hash untuk semua user, bcrypt per user terlalu lambat untuk ribuan user
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(cfg.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return Summary{}, err
+	}
+	defer tx.Rollback()
+
+	var notEmpty bool
+	err = tx.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM cinemas) OR EXISTS(SELECT 1 FROM movies)
+			OR EXISTS(SELECT 1 FROM users WHERE email LIKE $1)
+	`, "%@"+emailDomain).Scan(&notEmpty)
+	if err != nil {
+		return Summary{}, err
+	}
+	if notEmpty {
+		return Summary{}, ErrNotEmpty
+	}
+
+	g := &generator{
+		cfg:      cfg,
+		rng:      rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15)),
+		tx:       tx,
+		holidays: map[string]bool{},
+		nextID:   map[string]int{},
+	}
+	if err := g.load(); err != nil {
+		return Summary{}, err
+	}
+
+	steps := []func() error{
+		g.generateCinemas,
+		g.generateMovies,
+		func() error { return g.generateUsers(string(passwordHash)) },
+		g.generateSchedules,
+		g.syncSequences,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return Summary{}, err
+		}
+	}
+	return g.summary, tx.Commit()
+}
+
+// load mengambil pricing rule, hari libur dan id terakhir setiap tabel
+func (g *generator) load() error {
+	var err error
+	if g.rules, err = pricing.LoadActiveRules(g.tx); err != nil {
+		return err
+	}
+
+	rows, err := g.tx.Query("SELECT to_char(holiday_date, 'YYYY-MM-DD') FROM holidays")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			rows.Close()
+			return err
+		}
+		g.holidays[date] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, table := range sequenceTables {
+		var maxID int
+		if err := g.tx.QueryRow("SELECT COALESCE(MAX(id), 0) FROM " + table).Scan(&maxID); err != nil {
+			return err
+		}
+		g.nextID[table] = maxID
+	}
+	return nil
+}
+
+// id mengalokasikan id berikutnya untuk tabel
+func (g *generator) id(table string) int {
+	g.nextID[table]++
+	return g.nextID[table]
+}
+
+// generateCinemas membuat cinema per kota beserta studio dan kursinya
+func (g *generator) generateCinemas() error {
+	cinemaRows := [][]interface{}{}
+	studioRows := [][]interface{}{}
+	seatRows := [][]interface{}{}
+	opened := g.cfg.Start.AddDate(-1, 0, 0)
+
+	for _, c := range cities[:g.cfg.Cities] {
+		g.summary.Cities++
+		for _, mall := range pick(g.rng, malls, g.cfg.CinemasPerCity) {
+			cin := &cinema{
+				id:        g.id("cinemas"),
+				loc:       config.LoadLocation(c.Timezone),
+				basePrice: float64(35000 + 5000*g.rng.IntN(4)),
+			}
+			address := fmt.Sprintf("Jl. %s No. %d", streets[g.rng.IntN(len(streets))], 1+g.rng.IntN(200))
+			cinemaRows = append(cinemaRows, []interface{}{
+				cin.id, fmt.Sprintf("MKP XXI %s %s", c.Name, mall), c.Name, address, c.Timezone, opened,
+			})
+
+			for i := 1; i <= g.cfg.StudiosPerCinema; i++ {
+				st := &studio{id: g.id("studios"), cinema: cin, studioType: g.studioType(i)}
+				g.seatLayout(st)
+				studioRows = append(studioRows, []interface{}{
+					st.id, cin.id, fmt.Sprintf("Studio %d", i), len(st.seats), st.studioType, opened,
+				})
+				for _, seat := range st.seats {
+					seatRows = append(seatRows, []interface{}{seat.ID, st.id, string(rune('A' + seat.row)), seat.number, seat.Category})
+				}
+				g.studios = append(g.studios, st)
+			}
+		}
+	}
+
+	g.summary.Cinemas = len(cinemaRows)
+	g.summary.Studios = len(studioRows)
+	g.summary.Seats = len(seatRows)
+	if err := copyRows(g.tx, "cinemas", []string{"id", "name", "city", "address", "timezone", "created_at"}, cinemaRows); err != nil {
+		return err
+	}
+	if err := copyRows(g.tx, "studios", []string{"id", "cinema_id", "name", "total_seats", "studio_type", "created_at"}, studioRows); err != nil {
+		return err
+	}
+	return copyRows(g.tx, "seats", []string{"id", "studio_id", "row_code", "seat_number", "category"}, seatRows)
+}
+
+// studioType studio terakhir kadang IMAX dan yang sebelumnya kadang PREMIERE, sisanya REGULAR
+func (g *generator) studioType(number int) string {
+	n := g.cfg.StudiosPerCinema
+	roll := g.rng.Float64()
+	switch {
+	case n >= 3 && number == n && roll < 0.5:
+		return models.StudioTypeIMAX
+	case n >= 4 && number == n-1 && roll < 0.3:
+		return models.StudioTypePremiere
+	}
+	return models.StudioTypeRegular
+}
+
+// seatLayout membuat kursi studio: baris terakhir COUPLE (setengah jumlah kursi), dua baris
+// sebelumnya PREMIUM, sisanya REGULAR. Studio PREMIERE seluruhnya PREMIUM kecuali baris COUPLE.
+func (g *generator) seatLayout(st *studio) {
+	var rows, perRow int
+	switch st.studioType {
+	case models.StudioTypeIMAX:
+		rows, perRow = 12+g.rng.IntN(4), 16+2*g.rng.IntN(3)
+	case models.StudioTypePremiere:
+		rows, perRow = 4+g.rng.IntN(3), 8
+	default:
+		rows, perRow = 8+g.rng.IntN(5), 10+2*g.rng.IntN(4)
+	}
+
+	for r := 0; r < rows; r++ {
+		category := models.SeatCategoryRegular
+		seats := perRow
+		switch {
+		case r == rows-1:
+			category = models.SeatCategoryCouple
+			seats = perRow / 2
+		case r >= rows-3 || st.studioType == models.StudioTypePremiere:
+			category = models.SeatCategoryPremium
+		}
+		for n := 1; n <= seats; n++ {
+			st.seats = append(st.seats, seat{
+				Seat:   pricing.Seat{ID: g.id("seats"), Label: fmt.Sprintf("%c%d", 'A'+r, n), Category: category},
+				row:    r,
+				number: n,
+			})
+		}
+	}
+}
+
+// generateMovies membuat film dengan tanggal rilis tersebar sehingga setiap hari dalam jendela
+// jadwal ada film yang sedang tayang
+func (g *generator) generateMovies() error {
+	rows := [][]interface{}{}
+	used := map[string]bool{}
+	first := g.cfg.Start.AddDate(0, 0, -g.cfg.HistoryDays-movieRunDays/2)
+	span := g.cfg.HistoryDays + g.cfg.Days + movieRunDays/2
+
+	for i := 0; i < g.cfg.Movies; i++ {
+		base := titleWords[g.rng.IntN(len(titleWords))] + " " + titleNouns[g.rng.IntN(len(titleNouns))]
+		if g.rng.IntN(4) == 0 {
+			base += ": " + subtitles[g.rng.IntN(len(subtitles))]
+		}
+		title := base
+		for n := 2; used[title]; n++ {
+			title = fmt.Sprintf("%s %d", base, n)
+		}
+		used[title] = true
+
+		m := &movie{
+			id:         g.id("movies"),
+			duration:   85 + g.rng.IntN(20)*5,
+			release:    first.AddDate(0, 0, g.rng.IntN(span)),
+			popularity: math.Pow(g.rng.Float64(), 2),
+		}
+		description := genres[g.rng.IntN(len(genres))] + " " + plots[g.rng.IntN(len(plots))]
+		rows = append(rows, []interface{}{m.id, title, description, m.duration, m.release, m.release})
+		g.movies = append(g.movies, m)
+	}
+
+	g.summary.Movies = len(rows)
+	return copyRows(g.tx, "movies", []string{"id", "title", "description", "duration_minutes", "release_date", "created_at"}, rows)
+}
+
+// generateUsers membuat customer dengan email <nama>.<nama><n>@example.com
+func (g *generator) generateUsers(passwordHash string) error {
+	rows := [][]interface{}{}
+	for i := 1; i <= g.cfg.Users; i++ {
+		first := firstNames[g.rng.IntN(len(firstNames))]
+		last := lastNames[g.rng.IntN(len(lastNames))]
+		locale := "id"
+		if g.rng.IntN(5) == 0 {
+			locale = "en"
+		}
+		created := g.cfg.Start.AddDate(0, 0, -g.cfg.HistoryDays-30-g.rng.IntN(335))
+		id := g.id("users")
+		email := strings.ToLower(fmt.Sprintf("%s.%s%d@%s", first, last, i, emailDomain))
+		rows = append(rows, []interface{}{
+			id, first + " " + last, email, passwordHash, models.RoleCustomer, locale, created, created,
+		})
+		g.users = append(g.users, id)
+	}
+
+	g.summary.Users = len(rows)
+	return copyRows(g.tx, "users", []string{"id", "fullname", "email", "password_hash", "role", "locale", "created_at", "updated_at"}, rows)
+}
+
+// generateSchedules membuat jadwal setiap studio untuk setiap hari dalam jendela beserta transaksinya.
+// Ditulis per hari agar memori tetap kecil untuk dataset besar.
+func (g *generator) generateSchedules() error {
+	for day := -g.cfg.HistoryDays; day < g.cfg.Days; day++ {
+		date := g.cfg.Start.AddDate(0, 0, day)
+		scheduleRows := [][]interface{}{}
+		transactionRows := [][]interface{}{}
+		ticketRows := [][]interface{}{}
+
+		for _, st := range g.studios {
+			for _, s := range g.studioDay(st, date) {
+				status := models.ScheduleStatusShowing
+				if day < 0 {
+					status = models.ScheduleStatusEnded
+				}
+				scheduleRows = append(scheduleRows, []interface{}{
+					s.id, s.movie.id, st.id, s.start, s.end, s.price, status, s.start.AddDate(0, 0, -14).UTC(),
+				})
+				g.bookings(s, day, &transactionRows, &ticketRows)
+			}
+		}
+
+		g.summary.Schedules += len(scheduleRows)
+		g.summary.Transactions += len(transactionRows)
+		g.summary.Tickets += len(ticketRows)
+		if err := copyRows(g.tx, "schedules", []string{"id", "movie_id", "studio_id", "start_time", "end_time", "price", "status", "created_at"}, scheduleRows); err != nil {
+			return err
+		}
+		if err := copyRows(g.tx, "transactions", []string{
+			"id", "user_id", "schedule_id", "subtotal_amount", "discount_amount", "total_amount",
+			"payment_method", "payment_time", "status", "created_at", "updated_at",
+		}, transactionRows); err != nil {
+			return err
+		}
+		if err := copyRows(g.tx, "tickets", []string{"id", "transaction_id", "schedule_id", "seat_id", "price", "created_at"}, ticketRows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// studioDay jadwal satu studio pada satu tanggal lokal cinema: mulai sekitar jam 10, diselingi
+// jeda bersih-bersih, jadwal terakhir paling lambat mulai jam 22
+func (g *generator) studioDay(st *studio, date time.Time) []schedule {
+	showing := []*movie{}
+	for _, m := range g.movies {
+		if !m.release.After(date) && date.Sub(m.release) < movieRunDays*24*time.Hour {
+			showing = append(showing, m)
+		}
+	}
+	if len(showing) == 0 {
+		showing = g.movies
+	}
+
+	price := st.cinema.basePrice
+	if st.studioType == models.StudioTypePremiere {
+		price *= 2
+	}
+
+	schedules := []schedule{}
+	start := time.Date(date.Year(), date.Month(), date.Day(), 10, 15*g.rng.IntN(4), 0, 0, st.cinema.loc)
+	for len(schedules) < g.cfg.ShowsPerDay && start.Hour() < 22 && start.Day() == date.Day() {
+		m := weighted(g.rng, showing)
+		s := schedule{
+			id:     g.id("schedules"),
+			studio: st,
+			movie:  m,
+			start:  start,
+			end:    start.Add(time.Duration(m.duration) * time.Minute),
+			price:  price,
+		}
+		schedules = append(schedules, s)
+		start = s.end.Add(time.Duration(15+15*g.rng.IntN(2)) * time.Minute).Truncate(15 * time.Minute)
+	}
+	return schedules
+}
+
+// bookings membuat transaksi dan tiket satu jadwal. Jadwal lampau terisi sekitar Occupancy
+// (lebih ramai di akhir pekan, malam hari dan untuk film populer); jadwal mendatang terisi
+// sebagian sebagai pre-sale yang makin sedikit untuk tanggal yang makin jauh.
+func (g *generator) bookings(s schedule, day int, transactionRows, ticketRows *[][]interface{}) {
+	if g.cfg.Occupancy == 0 {
+		return
+	}
+	local := s.start
+	demand := g.cfg.Occupancy * (0.5 + s.movie.popularity) * (0.7 + 0.6*g.rng.Float64())
+	if weekday := local.Weekday(); weekday == time.Friday || weekday == time.Saturday || weekday == time.Sunday {
+		demand *= 1.3
+	}
+	if local.Hour() >= 18 {
+		demand *= 1.2
+	}
+	if day >= 0 {
+		demand *= 0.5 * (1 - float64(day)/float64(g.cfg.Days))
+	}
+	target := int(math.Min(demand, 0.95) * float64(len(s.studio.seats)))
+
+	sched := pricing.ScheduleContext{
+		ScheduleID: s.id,
+		BasePrice:  s.price,
+		LocalStart: local,
+		StudioType: s.studio.studioType,
+		IsHoliday:  g.holidays[local.Format("2006-01-02")],
+	}
+	taken := make([]bool, len(s.studio.seats))
+	sold := 0
+	for attempts := 0; sold < target && attempts < 4*target; attempts++ {
+		seats := g.pickSeats(s.studio, taken)
+		if len(seats) == 0 {
+			continue
+		}
+
+		status := g.transactionStatus(day)
+		// Pre-sale jadwal mendatang dibeli sebelum Start, bukan di masa depan
+		bookedBefore := s.start
+		if day >= 0 {
+			bookedBefore = g.cfg.Start
+		}
+		created := bookedBefore.Add(-time.Duration(10+g.rng.IntN(7*24*60)) * time.Minute).UTC()
+		var paymentMethod, paymentTime interface{}
+		updated := created.Add(15 * time.Minute)
+		if status != models.TransactionStatusCancelled {
+			paymentMethod = paymentMethods[g.rng.IntN(len(paymentMethods))]
+			paid := created.Add(time.Duration(1+g.rng.IntN(10)) * time.Minute)
+			paymentTime, updated = paid, paid
+		}
+		if window := s.start.Sub(updated); status == models.TransactionStatusRefunded && window > 0 {
+			updated = updated.Add(time.Duration(g.rng.Int64N(int64(window))))
+		}
+
+		transactionID := g.id("transactions")
+		total := 0.0
+		for _, i := range seats {
+			seat := s.studio.seats[i]
+			price := pricing.Calculate(sched, seat.Seat, g.rules).Price
+			total += price
+			*ticketRows = append(*ticketRows, []interface{}{g.id("tickets"), transactionID, s.id, seat.ID, price, created})
+			if status == models.TransactionStatusPaid {
+				taken[i] = true
+				sold++
+			}
+		}
+		user := g.users[g.rng.IntN(len(g.users))]
+		*transactionRows = append(*transactionRows, []interface{}{
+			transactionID, user, s.id, total, 0, total, paymentMethod, paymentTime, status, created, updated,
+		})
+	}
+}
+
+var paymentMethods = []string{models.PaymentMethodEWallet, models.PaymentMethodBankTransfer, models.PaymentMethodCreditCard}
+
+// transactionStatus sebagian kecil transaksi dibatalkan atau di-refund
+func (g *generator) transactionStatus(day int) string {
+	roll := g.rng.Float64()
+	switch {
+	case roll < 0.04:
+		return models.TransactionStatusCancelled
+	case roll < 0.10 && day < 0:
+		return models.TransactionStatusRefunded
+	}
+	return models.TransactionStatusPaid
+}
+
+// pickSeats memilih 1-4 kursi kosong bersebelahan di baris yang sama (kursi COUPLE satu per
+// booking) dan mengembalikan indeksnya, kosong jika kursi awal yang terpilih sudah terisi
+func (g *generator) pickSeats(st *studio, taken []bool) []int {
+	start := g.rng.IntN(len(st.seats))
+	size := []int{1, 2, 2, 2, 3, 4, 4}[g.rng.IntN(7)]
+	if st.seats[start].Category == models.SeatCategoryCouple {
+		size = 1
+	}
+	seats := []int{}
+	for i := start; i < len(st.seats) && len(seats) < size; i++ {
+		if taken[i] || st.seats[i].row != st.seats[start].row {
+			break
+		}
+		seats = append(seats, i)
+	}
+	return seats
+}
+
+// sequenceTables tabel yang id-nya diisi eksplisit oleh generator
+var sequenceTables = []string{"cinemas", "studios", "seats", "movies", "users", "schedules", "transactions", "tickets"}
+
+// syncSequences memajukan identity sequence karena id diisi eksplisit
+func (g *generator) syncSequences() error {
+	for _, table := range sequenceTables {
+		_, err := g.tx.Exec(fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), GREATEST((SELECT COALESCE(MAX(id), 0) FROM %[1]s), 1))",
+			table,
+		))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyRows menulis banyak baris sekaligus dengan COPY
+func copyRows(tx *sql.Tx, table string, columns []string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := stmt.Exec(row...); err != nil {
+			stmt.Close()
+			return fmt.Errorf("copy %s: %w", table, err)
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return fmt.Errorf("copy %s: %w", table, err)
+	}
+	return stmt.Close()
+}
+
+// pick n elemen berbeda dari list dalam urutan acak
+func pick(rng *rand.Rand, list []string, n int) []string {
+	picked := append([]string{}, list...)
+	rng.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+	return picked[:n]
+}
+
+// weighted memilih film dengan peluang sebanding popularitasnya
+func weighted(rng *rand.Rand, movies []*movie) *movie {
+	total := 0.0
+	for _, m := range movies {
+		total += 0.1 + m.popularity
+	}
+	roll := rng.Float64() * total
+	for _, m := range movies {
+		roll -= 0.1 + m.popularity
+		if roll < 0 {
+			return m
+		}
+	}
+	return movies[len(movies)-1]
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"mkp/account"
+	"mkp/audit"
+	"mkp/config"
+	"mkp/models"
+	"mkp/notify"
+	"mkp/validation"
+	"os"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// errUserNotFound email tidak terdaftar
+var errUserNotFound = errors.New("user not found")
+
+// runUser subcommand pengelolaan user: create, promote dan reset-password
+func runUser(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: user <create|promote|reset-password> [flags]")
+		return 2
+	}
+	switch args[0] {
+	case "create":
+		return runUserCreate(args[1:])
+	case "promote":
+		return runUserPromote(args[1:])
+	case "reset-password":
+		return runUserResetPassword(args[1:])
+	}
+	fmt.Fprintf(os.Stderr, "unknown user command %q, use create, promote or reset-password\n", args[0])
+	return 2
+}
+
+// runUserCreate membuat user baru, misal admin pertama. Jika -password kosong password acak
+// dibuat dan ditampilkan sekali.
+func runUserCreate(args []string) int {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := fs.String("email", "", "email user")
+	name := fs.String("name", "", "nama lengkap")
+	password := fs.String("password", "", "password (default dibuat acak)")
+	role := fs.String("role", models.RoleCustomer, "role: CUSTOMER atau ADMIN")
+	locale := fs.String("locale", notify.DefaultLocale, "bahasa notifikasi: id atau en")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *role != models.RoleCustomer && *role != models.RoleAdmin {
+		fmt.Fprintln(os.Stderr, "role must be CUSTOMER or ADMIN")
+		return 2
+	}
+
+	generated := *password == ""
+	if generated {
+		*password = randomPassword()
+	}
+	input := models.RegisterRequest{Fullname: *name, Email: *email, Password: *password, Locale: *locale}
+	if !validUserInput(input) {
+		return 2
+	}
+
+	config.InitDB()
+	defer config.CloseDB()
+
+	user, err := account.Create(context.Background(), config.DB, cliActor, input, *role)
+	if err == account.ErrEmailTaken {
+		fmt.Fprintln(os.Stderr, "email already registered:", input.Email)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "user create:", err)
+		return 1
+	}
+
+	fmt.Printf("Created %s user %d <%s>\n", user.Role, user.ID, user.Email)
+	if generated {
+		fmt.Println("Password:", input.Password)
+	}
+	return 0
+}
+
+// runUserPromote mengubah role user, default menjadi ADMIN
+func runUserPromote(args []string) int {
+	fs := flag.NewFlagSet("user promote", flag.ContinueOnError)
+	email := fs.String("email", "", "email user")
+	role := fs.String("role", models.RoleAdmin, "role baru: CUSTOMER atau ADMIN")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *email == "" {
+		fmt.Fprintln(os.Stderr, "usage: user promote -email <email> [-role ADMIN|CUSTOMER]")
+		return 2
+	}
+	if *role != models.RoleCustomer && *role != models.RoleAdmin {
+		fmt.Fprintln(os.Stderr, "role must be CUSTOMER or ADMIN")
+		return 2
+	}
+
+	config.InitDB()
+	defer config.CloseDB()
+
+	err := updateUser(*email, "UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2", *role)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "user promote:", err)
+		return 1
+	}
+	// Token yang sudah diterbitkan masih membawa role lama sampai kedaluwarsa
+	fmt.Printf("%s is now %s; existing tokens keep the old role until they expire\n", *email, *role)
+	return 0
+}
+
+// runUserResetPassword mengganti password user. Jika -password kosong password acak dibuat
+// dan ditampilkan sekali.
+func runUserResetPassword(args []string) int {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	email := fs.String("email", "", "email user")
+	password := fs.String("password", "", "password baru (default dibuat acak)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *email == "" {
+		fmt.Fprintln(os.Stderr, "usage: user reset-password -email <email> [-password <password>]")
+		return 2
+	}
+
+	generated := *password == ""
+	if generated {
+		*password = randomPassword()
+	}
+	if !validUserInput(models.RegisterRequest{Fullname: "-", Email: *email, Password: *password, Locale: notify.DefaultLocale}) {
+		return 2
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hash password:", err)
+		return 1
+	}
+
+	config.InitDB()
+	defer config.CloseDB()
+
+	err = updateUser(*email, "UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2", string(hashedPassword))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "user reset-password:", err)
+		return 1
+	}
+	fmt.Println("Password updated for", *email)
+	if generated {
+		fmt.Println("Password:", *password)
+	}
+	return 0
+}
+
+// updateUser menjalankan query UPDATE ($1 value, $2 id) untuk user dengan email tersebut dan
+// mencatatnya ke audit log
+func updateUser(email, query string, value interface{}) error {
+	var id int
+	err := config.DB.QueryRow("SELECT id FROM users WHERE email = $1", email).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	_, err = audit.WithTx(config.DB, cliActor, audit.ActionUpdate, "users", id, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, value, id)
+		return id, err
+	})
+	return err
+}
+
+// validUserInput validasi sama seperti registrasi lewat API, error ditulis ke stderr
+func validUserInput(input models.RegisterRequest) bool {
+	errs := validation.Struct(input)
+	for _, fieldError := range errs {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", fieldError.Field, fieldError.Message)
+	}
+	return len(errs) == 0
+}
+
+// randomPassword password acak 16 karakter
+func randomPassword() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,274 @@
+package validation
+
+import (
+	"fmt"
+	"mkp/apierror"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kode error per field
+const (
+	CodeRequired          = "REQUIRED"
+	CodeTooShort          = "TOO_SHORT"
+	CodeTooLong           = "TOO_LONG"
+	CodeOutOfRange        = "OUT_OF_RANGE"
+	CodeInvalidEmail      = "INVALID_EMAIL"
+	CodeInvalidURL        = "INVALID_URL"
+	CodeInvalidEnum       = "INVALID_ENUM"
+	CodeInvalidDateTime   = "INVALID_DATETIME"
+	CodeInvalidClock      = "INVALID_CLOCK"
+	CodeInvalidDate       = "INVALID_DATE"
+	CodeInvalidRecurrence = "INVALID_RECURRENCE"
+	CodeScheduleOverlap   = "SCHEDULE_OVERLAP"
+	CodeInPast            = "IN_PAST"
+	CodeInvalidRange      = "INVALID_RANGE"
+	CodeUnknownReference  = "UNKNOWN_REFERENCE"
+	CodeDuplicate         = "DUPLICATE_VALUE"
+	CodeDeleted           = "DELETED"
+	CodeImmutable         = "IMMUTABLE_FIELD"
+)
+
+// DateTimeLayouts format waktu yang diterima oleh rule `datetime`.
+// RFC 3339 membawa offset sendiri, format lainnya adalah waktu lokal tanpa offset.
+var DateTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05"}
+
+// ClockLayout format jam yang diterima oleh rule `clock`
+const ClockLayout = "15:04"
+
+// DateLayout format tanggal yang diterima oleh rule `date`
+const DateLayout = "2006-01-02"
+
+// Validator diimplementasikan oleh model yang butuh validasi antar field (misal start < end).
+// Method ini dipanggil setelah semua rule dari tag selesai dicek.
+type Validator interface {
+	Validate() []apierror.FieldError
+}
+
+// Struct memvalidasi struct berdasarkan tag `validate` dan mengembalikan semua field error sekaligus.
+//
+// Rule yang didukung (dipisah koma):
+//
+//	required       field wajib diisi (string tidak kosong, angka bukan 0, pointer tidak nil)
+//	min=N, max=N   panjang string atau nilai angka minimal/maksimal N
+//	maxbytes=N     panjang string maksimal N byte UTF-8 (misal batas 72 byte bcrypt)
+//	gt=N           nilai angka harus lebih besar dari N
+//	email          format alamat email
+//	url            URL absolut http atau https
+//	oneof=A B C    nilai harus salah satu dari daftar
+//	datetime       string waktu sesuai DateTimeLayouts
+//	clock          jam dalam format HH:MM
+//	date           tanggal dalam format YYYY-MM-DD
+//
+// Field pointer yang nil dilewati kecuali memiliki rule required.
+func Struct(v interface{}) []apierror.FieldError {
+	errs := []apierror.FieldError{}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return errs
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errs
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if err, ok := validateField(fieldName(sf), rv.Field(i), strings.Split(tag, ",")); !ok {
+			errs = append(errs, err)
+		}
+	}
+
+	if validator, ok := v.(Validator); ok {
+		errs = append(errs, validator.Validate()...)
+	}
+
+	return errs
+}
+
+// Problem membungkus field error menjadi response 400 VALIDATION_FAILED
+func Problem(errs []apierror.FieldError) *apierror.Problem {
+	return apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Request validation failed").
+		WithErrors(errs...)
+}
+
+// ParseDateTime mencoba parse string waktu dengan semua DateTimeLayouts, waktu lokal dianggap UTC
+func ParseDateTime(value string) (time.Time, error) {
+	return ParseDateTimeIn(value, time.UTC)
+}
+
+// ParseDateTimeIn sama seperti ParseDateTime, tetapi waktu lokal tanpa offset diinterpretasikan di loc
+func ParseDateTimeIn(value string, loc *time.Location) (time.Time, error) {
+	var err error
+	for _, layout := range DateTimeLayouts {
+		var t time.Time
+		t, err = time.ParseInLocation(layout, value, loc)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// HasOffset mengecek apakah string waktu membawa offset timezone (format RFC 3339)
+func HasOffset(value string) bool {
+	_, err := time.Parse(time.RFC3339, value)
+	return err == nil
+}
+
+// validateField menjalankan rule satu per satu dan berhenti di rule pertama yang gagal
+func validateField(name string, fv reflect.Value, rules []string) (apierror.FieldError, bool) {
+	required := false
+	for _, rule := range rules {
+		if rule == "required" {
+			required = true
+		}
+	}
+
+	// Pointer yang tidak nil berarti field dikirim, sehingga nilai nol tetap divalidasi
+	explicit := false
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			if required {
+				return fieldError(name, CodeRequired, "%s is required", name), false
+			}
+			return apierror.FieldError{}, true
+		}
+		fv = fv.Elem()
+		explicit = true
+	}
+
+	if required && isZero(fv) {
+		return fieldError(name, CodeRequired, "%s is required", name), false
+	}
+	if !required && !explicit && isZero(fv) {
+		// Field opsional yang kosong tidak perlu dicek rule lainnya
+		return apierror.FieldError{}, true
+	}
+
+	for _, rule := range rules {
+		key, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "required", "":
+			continue
+		case "min":
+			if n, ok := number(fv); ok {
+				if limit := parseFloat(param); n < limit {
+					return fieldError(name, CodeOutOfRange, "%s must be at least %s", name, param), false
+				}
+			} else if limit, _ := strconv.Atoi(param); len([]rune(fv.String())) < limit {
+				return fieldError(name, CodeTooShort, "%s must be at least %s characters", name, param), false
+			}
+		case "max":
+			if n, ok := number(fv); ok {
+				if limit := parseFloat(param); n > limit {
+					return fieldError(name, CodeOutOfRange, "%s must be at most %s", name, param), false
+				}
+			} else if limit, _ := strconv.Atoi(param); len([]rune(fv.String())) > limit {
+				return fieldError(name, CodeTooLong, "%s must be at most %s characters", name, param), false
+			}
+		case "maxbytes":
+			if limit, _ := strconv.Atoi(param); len(fv.String()) > limit {
+				return fieldError(name, CodeTooLong, "%s must be at most %s bytes", name, param), false
+			}
+		case "gt":
+			if n, ok := number(fv); ok && n <= parseFloat(param) {
+				return fieldError(name, CodeOutOfRange, "%s must be greater than %s", name, param), false
+			}
+		case "email":
+			addr, err := mail.ParseAddress(fv.String())
+			if err != nil || addr.Address != fv.String() {
+				return fieldError(name, CodeInvalidEmail, "%s must be a valid email address", name), false
+			}
+		case "url":
+			u, err := url.Parse(fv.String())
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fieldError(name, CodeInvalidURL, "%s must be an absolute http or https URL", name), false
+			}
+		case "oneof":
+			options := strings.Fields(param)
+			if !contains(options, fmt.Sprint(fv.Interface())) {
+				return fieldError(name, CodeInvalidEnum, "%s must be one of: %s", name, strings.Join(options, ", ")), false
+			}
+		case "datetime":
+			if _, err := ParseDateTime(fv.String()); err != nil {
+				return fieldError(name, CodeInvalidDateTime, "%s must be RFC 3339 or YYYY-MM-DD HH:MM:SS", name), false
+			}
+		case "clock":
+			if _, err := time.Parse(ClockLayout, fv.String()); err != nil {
+				return fieldError(name, CodeInvalidClock, "%s must use format HH:MM", name), false
+			}
+		case "date":
+			if _, err := time.Parse(DateLayout, fv.String()); err != nil {
+				return fieldError(name, CodeInvalidDate, "%s must use format YYYY-MM-DD", name), false
+			}
+		default:
+			panic("validation: unknown rule " + key)
+		}
+	}
+
+	return apierror.FieldError{}, true
+}
+
+// fieldName mengambil nama field dari tag json, fallback ke nama field Go
+func fieldName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+func fieldError(field, code, format string, args ...interface{}) apierror.FieldError {
+	return apierror.FieldError{
+		Field:   field,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func isZero(v reflect.Value) bool {
+	if v.Kind() == reflect.String {
+		return strings.TrimSpace(v.String()) == ""
+	}
+	return v.IsZero()
+}
+
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
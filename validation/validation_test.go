@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"mkp/apierror"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testRequest struct {
+	Name     string   `json:"name" validate:"required,min=2,max=5"`
+	Password string   `json:"password" validate:"max=72,maxbytes=72"`
+	Email    string   `json:"email" validate:"email"`
+	Website  string   `json:"website" validate:"url"`
+	Seats    int      `json:"seats" validate:"gt=0,max=10"`
+	Price    *float64 `json:"price" validate:"gt=0"`
+	Stock    *int     `json:"stock" validate:"required,min=0"`
+	Type     string   `json:"type" validate:"oneof=REGULAR IMAX"`
+	Start    string   `json:"start" validate:"datetime"`
+	Clock    string   `json:"clock" validate:"clock"`
+	Date     string   `json:"date" validate:"date"`
+	Ignored  string   `validate:"-"`
+}
+
+// validRequest request yang lolos semua rule; tiap kasus test mengubah satu field
+func validRequest() testRequest {
+	stock := 3
+	return testRequest{Name: "Ana", Seats: 1, Stock: &stock}
+}
+
+func TestStruct(t *testing.T) {
+	zero := 0.0
+	tests := []struct {
+		name   string
+		modify func(r *testRequest)
+		field  string
+		code   string
+	}{
+		{"valid", func(r *testRequest) {}, "", ""},
+		{"required blank string", func(r *testRequest) { r.Name = "   " }, "name", CodeRequired},
+		{"min length", func(r *testRequest) { r.Name = "A" }, "name", CodeTooShort},
+		{"max length counts runes", func(r *testRequest) { r.Name = "ééééé" }, "", ""},
+		{"max length", func(r *testRequest) { r.Name = "Andika" }, "name", CodeTooLong},
+		{"maxbytes ascii at limit", func(r *testRequest) { r.Password = strings.Repeat("a", 72) }, "", ""},
+		{"maxbytes multibyte", func(r *testRequest) { r.Password = strings.Repeat("é", 40) }, "password", CodeTooLong},
+		{"email", func(r *testRequest) { r.Email = "ana@mkp.id" }, "", ""},
+		{"email with display name", func(r *testRequest) { r.Email = "Ana <ana@mkp.id>" }, "email", CodeInvalidEmail},
+		{"url", func(r *testRequest) { r.Website = "https://mkp.id/a" }, "", ""},
+		{"url without scheme", func(r *testRequest) { r.Website = "mkp.id" }, "website", CodeInvalidURL},
+		{"url ftp", func(r *testRequest) { r.Website = "ftp://mkp.id" }, "website", CodeInvalidURL},
+		{"optional zero number skipped", func(r *testRequest) { r.Seats = 0 }, "", ""},
+		{"gt", func(r *testRequest) { r.Seats = -1 }, "seats", CodeOutOfRange},
+		{"max number", func(r *testRequest) { r.Seats = 11 }, "seats", CodeOutOfRange},
+		{"nil optional pointer", func(r *testRequest) { r.Price = nil }, "", ""},
+		{"explicit zero pointer validated", func(r *testRequest) { r.Price = &zero }, "price", CodeOutOfRange},
+		{"nil required pointer", func(r *testRequest) { r.Stock = nil }, "stock", CodeRequired},
+		{"required pointer to zero", func(r *testRequest) { r.Stock = new(int) }, "stock", CodeRequired},
+		{"oneof", func(r *testRequest) { r.Type = "4DX" }, "type", CodeInvalidEnum},
+		{"datetime rfc3339", func(r *testRequest) { r.Start = "2026-01-02T19:00:00+07:00" }, "", ""},
+		{"datetime local", func(r *testRequest) { r.Start = "2026-01-02 19:00:00" }, "", ""},
+		{"datetime invalid", func(r *testRequest) { r.Start = "2026-01-02T19:00" }, "start", CodeInvalidDateTime},
+		{"clock", func(r *testRequest) { r.Clock = "24:00" }, "clock", CodeInvalidClock},
+		{"date", func(r *testRequest) { r.Date = "2026-02-30" }, "date", CodeInvalidDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.modify(&req)
+			errs := Struct(req)
+
+			if tt.field == "" {
+				if len(errs) != 0 {
+					t.Errorf("errors = %+v, want none", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Field != tt.field || errs[0].Code != tt.code {
+				t.Errorf("errors = %+v, want one %s on %s", errs, tt.code, tt.field)
+			}
+		})
+	}
+}
+
+func TestStructCollectsAllFields(t *testing.T) {
+	req := validRequest()
+	req.Name = ""
+	req.Seats = -1
+	req.Type = "4DX"
+
+	errs := Struct(&req)
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	if strings.Join(fields, ",") != "name,seats,type" {
+		t.Errorf("fields = %v, want [name seats type] in struct order", fields)
+	}
+}
+
+type rangeRequest struct {
+	From int `json:"from" validate:"required"`
+	To   int `json:"to" validate:"required"`
+}
+
+func (r rangeRequest) Validate() []apierror.FieldError {
+	if r.To < r.From {
+		return []apierror.FieldError{{Field: "to", Code: CodeInvalidRange, Message: "to must not be before from"}}
+	}
+	return nil
+}
+
+func TestStructValidator(t *testing.T) {
+	errs := Struct(rangeRequest{From: 5, To: 1})
+	if len(errs) != 1 || errs[0].Code != CodeInvalidRange {
+		t.Errorf("errors = %+v, want INVALID_RANGE from Validate", errs)
+	}
+	if errs := Struct((*rangeRequest)(nil)); len(errs) != 0 {
+		t.Errorf("nil pointer errors = %+v, want none", errs)
+	}
+}
+
+func TestStructUnknownRulePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("unknown rule did not panic")
+		}
+	}()
+	Struct(struct {
+		Name string `validate:"required,nonsense"`
+	}{Name: "x"})
+}
+
+func TestParseDateTimeIn(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2026-01-02 19:00:00", time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)},
+		{"2026-01-02T19:00:00Z", time.Date(2026, 1, 2, 19, 0, 0, 0, time.UTC)},
+		{"2026-01-02T19:00:00+08:00", time.Date(2026, 1, 2, 11, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDateTimeIn(tt.value, jakarta)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseDateTimeIn(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := ParseDateTimeIn("tomorrow", jakarta); err == nil {
+		t.Error("ParseDateTimeIn(tomorrow) err = nil")
+	}
+}
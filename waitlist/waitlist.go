@@ -0,0 +1,378 @@
+package waitlist
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"mkp/audit"
+	"mkp/events"
+	"mkp/models"
+	"mkp/notify"
+	"mkp/promo"
+	"mkp/seatstream"
+	"mkp/webhook"
+	"time"
+)
+
+// HoldDuration lama booking PENDING menahan kursi sebelum otomatis dibatalkan
+const HoldDuration = 15 * time.Minute
+
+// ClaimDuration lama user waitlist boleh mengklaim kursi yang ditawarkan
+const ClaimDuration = 30 * time.Minute
+
+// Querier dipenuhi oleh *sql.DB dan *sql.Tx
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// SelectQuery query dasar entry waitlist beserta posisi antrian, dipakai bersama Scan
+const SelectQuery = `
+	SELECT w.id, w.schedule_id, w.user_id, w.seats, w.status, w.offered_at, w.offer_expires_at, w.created_at,
+		CASE WHEN w.status = 'WAITING' THEN (
+			SELECT COUNT(*) FROM waitlist_entries o
+			WHERE o.schedule_id = w.schedule_id AND o.status = 'WAITING'
+				AND (o.created_at, o.id) <= (w.created_at, w.id)
+		) END
+	FROM waitlist_entries w
+`
+
+// workerActor actor audit untuk perubahan yang dilakukan worker
+var workerActor = audit.Actor{RequestID: "waitlist-worker"}
+
+// Available jumlah kursi yang masih bisa dibooking user pada sebuah jadwal: kursi studio
+// dikurangi tiket PENDING/PAID dan kursi yang sedang ditawarkan ke user waitlist lain.
+func Available(q Querier, scheduleID, userID int) (int, error) {
+	var available int
+	err := q.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM seats se JOIN schedules s ON s.studio_id = se.studio_id WHERE s.id = $1)
+			- (SELECT COUNT(*) FROM tickets t JOIN transactions tr ON t.transaction_id = tr.id
+				WHERE t.schedule_id = $1 AND tr.status IN ($2, $3))
+			- (SELECT COALESCE(SUM(seats), 0) FROM waitlist_entries
+				WHERE schedule_id = $1 AND status = $4 AND user_id <> $5)
+	`, scheduleID, models.TransactionStatusPending, models.TransactionStatusPaid,
+		models.WaitlistStatusOffered, userID).Scan(&available)
+	return available, err
+}
+
+// Claim menandai entry waitlist user pada jadwal ini sebagai CLAIMED setelah user berhasil booking
+func Claim(tx *sql.Tx, scheduleID, userID int) error {
+	_, err := tx.Exec(`
+		UPDATE waitlist_entries SET status = $1, updated_at = NOW()
+		WHERE schedule_id = $2 AND user_id = $3 AND status IN ($4, $5)
+	`, models.WaitlistStatusClaimed, scheduleID, userID, models.WaitlistStatusWaiting, models.WaitlistStatusOffered)
+	return err
+}
+
+// Offer menawarkan kursi kosong ke user waitlist secara FIFO. Antrian berhenti pada entry
+// pertama yang jumlah kursinya belum tersedia agar user yang lebih dulu tidak dilewati.
+// User yang ditawari mendapat notifikasi. Mengembalikan entry yang baru ditawari.
+func Offer(tx *sql.Tx, scheduleID int) ([]models.WaitlistEntry, error) {
+	// Kunci jadwal agar penawaran dan booking paralel diproses berurutan
+	var status string
+	var startTime time.Time
+	var deleted bool
+	err := tx.QueryRow("SELECT COALESCE(status, ''), start_time, deleted_at IS NOT NULL FROM schedules WHERE id = $1 FOR UPDATE", scheduleID).
+		Scan(&status, &startTime, &deleted)
+	if err != nil {
+		return nil, err
+	}
+	if status != models.ScheduleStatusShowing || deleted || !startTime.After(time.Now()) {
+		return nil, nil
+	}
+
+	free, err := Available(tx, scheduleID, 0)
+	if err != nil || free <= 0 {
+		return nil, err
+	}
+
+	rows, err := tx.Query(SelectQuery+" WHERE w.schedule_id = $1 AND w.status = $2 ORDER BY w.created_at, w.id",
+		scheduleID, models.WaitlistStatusWaiting)
+	if err != nil {
+		return nil, err
+	}
+	waiting := []models.WaitlistEntry{}
+	for rows.Next() {
+		entry, err := Scan(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		waiting = append(waiting, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	offered := []models.WaitlistEntry{}
+	for _, entry := range waiting {
+		if entry.Seats > free {
+			break
+		}
+		err := tx.QueryRow(`
+			UPDATE waitlist_entries
+			SET status = $1, offered_at = NOW(), offer_expires_at = NOW() + make_interval(secs => $2), updated_at = NOW()
+			WHERE id = $3
+			RETURNING offered_at, offer_expires_at
+		`, models.WaitlistStatusOffered, ClaimDuration.Seconds(), entry.ID).Scan(&entry.OfferedAt, &entry.OfferExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := notify.EnqueueWaitlistOffer(tx, entry.ID); err != nil {
+			return nil, err
+		}
+		entry.Status = models.WaitlistStatusOffered
+		offered = append(offered, entry)
+		free -= entry.Seats
+	}
+	return offered, nil
+}
+
+// CancelBooking membatalkan satu booking dan melepas kursinya: PENDING menjadi CANCELLED,
+// PAID menjadi REFUNDED. Kuota promo dikembalikan, perubahan dicatat di audit log, pemilik
+// booking mendapat notifikasi dan kursinya diumumkan lepas ke stream kursi.
+func CancelBooking(tx *sql.Tx, actor audit.Actor, transactionID int, status string) error {
+	before, err := audit.Snapshot(tx, "transactions", transactionID)
+	if err != nil {
+		return err
+	}
+	if err := promo.Release(tx, transactionID); err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE transactions SET status = $1, updated_at = NOW() WHERE id = $2", status, transactionID)
+	if err != nil {
+		return err
+	}
+	if err := notify.EnqueueBooking(tx, transactionID, notify.TemplateBookingCancelled); err != nil {
+		return err
+	}
+	if err := seatstream.Notify(tx, transactionID, seatstream.StateReleased); err != nil {
+		return err
+	}
+	after, err := audit.Snapshot(tx, "transactions", transactionID)
+	if err != nil {
+		return err
+	}
+	return audit.Record(tx, actor, audit.ActionUpdate, "transactions", &transactionID, before, after)
+}
+
+// CancelSchedule membatalkan semua booking PENDING sebuah jadwal yang dihapus atau dibatalkan
+// lewat CancelBooking, lalu menutup antrian waitlist-nya. Booking PAID tidak diubah.
+func CancelSchedule(tx *sql.Tx, actor audit.Actor, scheduleID int) error {
+	rows, err := tx.Query("SELECT id FROM transactions WHERE schedule_id = $1 AND status = $2 FOR UPDATE",
+		scheduleID, models.TransactionStatusPending)
+	if err != nil {
+		return err
+	}
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := CancelBooking(tx, actor, id, models.TransactionStatusCancelled); err != nil {
+			return err
+		}
+	}
+
+	// Jadwal tidak bisa dibooking lagi, antrian waitlist ikut ditutup
+	_, err = tx.Exec("UPDATE waitlist_entries SET status = $1, updated_at = NOW() WHERE schedule_id = $2 AND status IN ($3, $4)",
+		models.WaitlistStatusCancelled, scheduleID, models.WaitlistStatusWaiting, models.WaitlistStatusOffered)
+	return err
+}
+
+// CancelShowing membatalkan jadwal yang masih tayang: status menjadi CANCELLED dan version naik,
+// pemilik tiket diberi notifikasi, booking PENDING dan waitlist ditutup lewat CancelSchedule, lalu
+// webhook schedule.cancelled dikirim. Dipakai PUT/PATCH jadwal dan CLI schedule cancel; status
+// yang sudah ditulis CANCELLED oleh caller tidak diubah lagi. events.ScheduleCancelled di-publish
+// caller setelah commit.
+func CancelShowing(tx *sql.Tx, actor audit.Actor, scheduleID int) error {
+	_, err := tx.Exec("UPDATE schedules SET status = $1, version = version + 1 WHERE id = $2 AND COALESCE(status, '') <> $1",
+		models.ScheduleStatusCancelled, scheduleID)
+	if err != nil {
+		return err
+	}
+	if err := notify.EnqueueScheduleHolders(tx, scheduleID, notify.TemplateScheduleCancelled); err != nil {
+		return err
+	}
+	if err := CancelSchedule(tx, actor, scheduleID); err != nil {
+		return err
+	}
+	return webhook.PublishSchedule(tx, models.WebhookEventScheduleCancelled, scheduleID)
+}
+
+// Run menjalankan worker yang setiap interval membatalkan booking PENDING yang melewati
+// HoldDuration, mengakhiri tawaran yang tidak diklaim, lalu menawarkan kursi yang terlepas
+// ke user waitlist berikutnya. Dipanggil sebagai goroutine dari main.
+func Run(db *sql.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := Process(db); err != nil {
+			log.Println("waitlist worker:", err)
+		}
+	}
+}
+
+// Process satu putaran worker; setiap jadwal diproses dalam transaksinya sendiri. Jadwal yang
+// gagal diproses di-log dan dilewati agar tidak menahan jadwal lain; dicoba lagi putaran berikutnya.
+func Process(db *sql.DB) error {
+	// Jadwal yang punya hold kedaluwarsa atau tawaran kedaluwarsa
+	rows, err := db.Query(`
+		SELECT schedule_id FROM transactions
+		WHERE status = $1 AND created_at < NOW() - make_interval(secs => $2)
+		UNION
+		SELECT schedule_id FROM waitlist_entries
+		WHERE status = $3 AND offer_expires_at < NOW()
+	`, models.TransactionStatusPending, HoldDuration.Seconds(), models.WaitlistStatusOffered)
+	if err != nil {
+		return err
+	}
+	scheduleIDs := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		scheduleIDs = append(scheduleIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, scheduleID := range scheduleIDs {
+		if err := processSchedule(db, scheduleID); err != nil {
+			log.Printf("waitlist worker: schedule %d: %v", scheduleID, err)
+		}
+	}
+	return nil
+}
+
+// processSchedule melepas hold dan tawaran kedaluwarsa satu jadwal lalu menawarkan ulang kursinya
+func processSchedule(db *sql.DB, scheduleID int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Jadwal dikunci lebih dulu, urutannya sama dengan booking
+	if _, err := tx.Exec("SELECT id FROM schedules WHERE id = $1 FOR UPDATE", scheduleID); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`
+		SELECT id, user_id FROM transactions
+		WHERE schedule_id = $1 AND status = $2 AND created_at < NOW() - make_interval(secs => $3)
+		FOR UPDATE
+	`, scheduleID, models.TransactionStatusPending, HoldDuration.Seconds())
+	if err != nil {
+		return err
+	}
+	expired := []events.BookingCancelled{}
+	for rows.Next() {
+		event := events.BookingCancelled{ScheduleID: scheduleID, Status: models.TransactionStatusCancelled}
+		if err := rows.Scan(&event.TransactionID, &event.UserID); err != nil {
+			rows.Close()
+			return err
+		}
+		expired = append(expired, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, event := range expired {
+		if err := CancelBooking(tx, workerActor, event.TransactionID, models.TransactionStatusCancelled); err != nil {
+			return err
+		}
+	}
+
+	if err := expireOffers(tx, scheduleID); err != nil {
+		return err
+	}
+
+	if _, err := Offer(tx, scheduleID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, event := range expired {
+		events.Publish(context.Background(), event)
+	}
+	return nil
+}
+
+// expireOffers mengakhiri tawaran waitlist jadwal yang tidak diklaim sampai batas waktunya,
+// masing-masing dicatat di audit log
+func expireOffers(tx *sql.Tx, scheduleID int) error {
+	rows, err := tx.Query(`
+		SELECT id FROM waitlist_entries
+		WHERE schedule_id = $1 AND status = $2 AND offer_expires_at < NOW()
+		FOR UPDATE
+	`, scheduleID, models.WaitlistStatusOffered)
+	if err != nil {
+		return err
+	}
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		before, err := audit.Snapshot(tx, "waitlist_entries", id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE waitlist_entries SET status = $1, updated_at = NOW() WHERE id = $2",
+			models.WaitlistStatusExpired, id)
+		if err != nil {
+			return err
+		}
+		after, err := audit.Snapshot(tx, "waitlist_entries", id)
+		if err != nil {
+			return err
+		}
+		if err := audit.Record(tx, workerActor, audit.ActionUpdate, "waitlist_entries", &id, before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowScanner dipenuhi oleh *sql.Row dan *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scan membaca satu baris hasil SelectQuery
+func Scan(row rowScanner) (models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := row.Scan(&entry.ID, &entry.ScheduleID, &entry.UserID, &entry.Seats, &entry.Status,
+		&entry.OfferedAt, &entry.OfferExpiresAt, &entry.CreatedAt, &entry.Position)
+	return entry, err
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"mkp/models"
+	"time"
+)
+
+// ScheduleData isi data event schedule.*; deleted true berarti jadwal di-soft delete
+type ScheduleData struct {
+	ID         int       `json:"id"`
+	MovieID    int       `json:"movie_id"`
+	MovieTitle string    `json:"movie_title"`
+	StudioID   int       `json:"studio_id"`
+	StudioName string    `json:"studio_name"`
+	CinemaID   int       `json:"cinema_id"`
+	CinemaName string    `json:"cinema_name"`
+	City       string    `json:"city"`
+	Timezone   string    `json:"timezone"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	Price      float64   `json:"price"`
+	Status     string    `json:"status"`
+	Deleted    bool      `json:"deleted"`
+	Version    int       `json:"version"`
+}
+
+// TicketSoldData isi data event ticket.sold, tanpa data pribadi pembeli
+type TicketSoldData struct {
+	BookingID     int          `json:"booking_id"`
+	ScheduleID    int          `json:"schedule_id"`
+	TotalAmount   float64      `json:"total_amount"`
+	PaymentMethod string       `json:"payment_method"`
+	PaidAt        time.Time    `json:"paid_at"`
+	Tickets       []TicketData `json:"tickets"`
+}
+
+// TicketData satu kursi yang terjual
+type TicketData struct {
+	ID        int     `json:"id"`
+	SeatID    int     `json:"seat_id"`
+	SeatLabel string  `json:"seat_label"`
+	Price     float64 `json:"price"`
+}
+
+// PublishSchedule membaca kondisi jadwal terbaru di dalam transaksi lalu mem-publish eventType
+func PublishSchedule(tx Querier, eventType string, scheduleID int) error {
+	var data ScheduleData
+	err := tx.QueryRow(`
+		SELECT s.id, s.movie_id, COALESCE(m.title, ''), s.studio_id, COALESCE(st.name, ''),
+			COALESCE(c.id, 0), COALESCE(c.name, ''), COALESCE(c.city, ''), COALESCE(c.timezone, ''),
+			s.start_time, s.end_time, s.price, COALESCE(s.status, ''), s.deleted_at IS NOT NULL, s.version
+		FROM schedules s
+		LEFT JOIN movies m ON s.movie_id = m.id
+		LEFT JOIN studios st ON s.studio_id = st.id
+		LEFT JOIN cinemas c ON st.cinema_id = c.id
+		WHERE s.id = $1
+	`, scheduleID).Scan(&data.ID, &data.MovieID, &data.MovieTitle, &data.StudioID, &data.StudioName,
+		&data.CinemaID, &data.CinemaName, &data.City, &data.Timezone,
+		&data.StartTime, &data.EndTime, &data.Price, &data.Status, &data.Deleted, &data.Version)
+	if err != nil {
+		return err
+	}
+	return Publish(tx, eventType, data)
+}
+
+// PublishTicketSold mem-publish ticket.sold untuk booking yang baru dibayar
+func PublishTicketSold(tx Querier, transactionID int) error {
+	data := TicketSoldData{Tickets: []TicketData{}}
+	err := tx.QueryRow(`
+		SELECT id, schedule_id, total_amount, COALESCE(payment_method, ''), COALESCE(payment_time, NOW())
+		FROM transactions
+		WHERE id = $1
+	`, transactionID).Scan(&data.BookingID, &data.ScheduleID, &data.TotalAmount, &data.PaymentMethod, &data.PaidAt)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`
+		SELECT t.id, t.seat_id, se.row_code || se.seat_number, COALESCE(t.price, 0)
+		FROM tickets t
+		JOIN seats se ON t.seat_id = se.id
+		WHERE t.transaction_id = $1
+		ORDER BY se.row_code, se.seat_number
+	`, transactionID)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var ticket TicketData
+		if err := rows.Scan(&ticket.ID, &ticket.SeatID, &ticket.SeatLabel, &ticket.Price); err != nil {
+			rows.Close()
+			return err
+		}
+		data.Tickets = append(data.Tickets, ticket)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return Publish(tx, models.WebhookEventTicketSold, data)
+}
@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mkp/models"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// MaxAttempts jumlah percobaan kirim sebelum delivery ditandai DEAD
+const MaxAttempts = 10
+
+// Header yang dikirim bersama setiap delivery
+const (
+	HeaderEvent     = "X-MKP-Event"
+	HeaderEventID   = "X-MKP-Event-ID"
+	HeaderDelivery  = "X-MKP-Delivery"
+	HeaderSignature = "X-MKP-Signature"
+)
+
+// Querier dipenuhi oleh *sql.Tx, agar delivery ditulis dalam transaksi perubahan bisnisnya
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Event isi body yang diterima partner
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Publish menulis satu delivery untuk setiap subscription aktif yang berlangganan eventType.
+// Semua delivery dari satu event memakai event ID yang sama.
+func Publish(tx Querier, eventType string, data interface{}) error {
+	random, err := NewSecret()
+	if err != nil {
+		return err
+	}
+	eventID := "evt_" + random[:24]
+	payload, err := json.Marshal(Event{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		SELECT id, $1, $2, $3, $4, 0, NOW(), NOW()
+		FROM webhook_subscriptions
+		WHERE active AND $2 = ANY(event_types)
+	`, eventID, eventType, payload, models.WebhookDeliveryPending)
+	return err
+}
+
+// NewSecret membuat string acak 64 karakter hex, dipakai sebagai secret subscription
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Sign menghasilkan nilai header X-MKP-Signature: "t=<unix>,v1=<hex>" dengan v1 adalah
+// HMAC-SHA256 dari "<unix>.<body>". Partner memverifikasi dengan menghitung ulang HMAC
+// dan menolak timestamp yang terlalu lama untuk mencegah replay.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher mengirim delivery yang jatuh tempo dengan retry dan backoff eksponensial
+type Dispatcher struct {
+	DB        *sql.DB
+	Client    *http.Client
+	BatchSize int
+}
+
+// Run memproses antrian delivery setiap interval; dipanggil sebagai goroutine dari main
+func (d Dispatcher) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for {
+			n, err := d.DispatchOnce()
+			if err != nil {
+				log.Println("webhook dispatcher:", err)
+			}
+			if err != nil || n < d.batchSize() {
+				break
+			}
+		}
+	}
+}
+
+// claimLease lama delivery yang sudah diklaim tidak diambil dispatcher lain; cukup untuk satu
+// batch penuh dengan timeout client default. Jika instance mati sebelum mencatat hasil,
+// delivery dikirim lagi setelah lease habis (partner men-dedup lewat X-MKP-Event-ID).
+const claimLease = 15 * time.Minute
+
+// DispatchOnce mengirim satu batch delivery dan mengembalikan jumlah baris yang diproses.
+// Response 2xx berarti DELIVERED; selain itu dicoba lagi sampai MaxAttempts lalu DEAD.
+// Delivery untuk subscription yang sudah dinonaktifkan langsung DEAD dan bisa di-redeliver.
+// Batch diklaim dengan SKIP LOCKED dan next_attempt_at dimajukan sebagai lease lalu di-commit,
+// sehingga POST ke partner tidak menahan transaksi; hasil tiap delivery dicatat tersendiri.
+func (d Dispatcher) DispatchOnce() (int, error) {
+	rows, err := d.DB.Query(`
+		WITH claimed AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $1 AND next_attempt_at <= NOW()
+			ORDER BY id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $3)
+		FROM claimed, webhook_subscriptions s
+		WHERE d.id = claimed.id AND d.subscription_id = s.id
+		RETURNING d.id, d.event_id, d.event_type, d.payload, d.attempts, s.url, s.secret, s.active
+	`, models.WebhookDeliveryPending, d.batchSize(), claimLease.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	type deliveryRow struct {
+		id        int64
+		eventID   string
+		eventType string
+		payload   []byte
+		attempts  int
+		url       string
+		secret    string
+		active    bool
+	}
+	batch := []deliveryRow{}
+	for rows.Next() {
+		var row deliveryRow
+		if err := rows.Scan(&row.id, &row.eventID, &row.eventType, &row.payload, &row.attempts, &row.url, &row.secret, &row.active); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	// RETURNING tidak menjamin urutan; delivery dikirim sesuai urutan event
+	sort.Slice(batch, func(i, j int) bool { return batch[i].id < batch[j].id })
+
+	for i, row := range batch {
+		if !row.active {
+			_, err = d.DB.Exec("UPDATE webhook_deliveries SET status = $1, last_error = $2 WHERE id = $3",
+				models.WebhookDeliveryDead, "subscription is inactive", row.id)
+			if err != nil {
+				return i, err
+			}
+			continue
+		}
+
+		statusCode, sendErr := d.send(row.url, row.secret, row.id, row.eventID, row.eventType, row.payload)
+		var code *int
+		if statusCode != 0 {
+			code = &statusCode
+		}
+
+		if sendErr == nil {
+			_, err = d.DB.Exec(`
+				UPDATE webhook_deliveries
+				SET status = $1, attempts = attempts + 1, last_status_code = $2, last_error = NULL,
+					last_attempt_at = NOW(), delivered_at = NOW()
+				WHERE id = $3
+			`, models.WebhookDeliveryDelivered, code, row.id)
+		} else {
+			status := models.WebhookDeliveryPending
+			if row.attempts+1 >= MaxAttempts {
+				status = models.WebhookDeliveryDead
+			}
+			_, err = d.DB.Exec(`
+				UPDATE webhook_deliveries
+				SET status = $1, attempts = attempts + 1, last_status_code = $2, last_error = $3,
+					last_attempt_at = NOW(), next_attempt_at = NOW() + make_interval(secs => $4)
+				WHERE id = $5
+			`, status, code, sendErr.Error(), Backoff(row.attempts+1).Seconds(), row.id)
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(batch), nil
+}
+
+// send melakukan POST payload ke URL partner dan mengembalikan status code HTTP (0 jika tidak ada response)
+func (d Dispatcher) send(url, secret string, deliveryID int64, eventID, eventType string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MKP-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderEventID, eventID)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now(), payload))
+
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Potongan body response disimpan untuk membantu partner mencari penyebab gagal
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return resp.StatusCode, nil
+}
+
+func (d Dispatcher) client() *http.Client {
+	if d.Client == nil {
+		return &http.Client{Timeout: 10 * time.Second}
+	}
+	return d.Client
+}
+
+func (d Dispatcher) batchSize() int {
+	if d.BatchSize <= 0 {
+		return 20
+	}
+	return d.BatchSize
+}
+
+// Backoff jeda sebelum percobaan berikutnya: 1 menit dikali dua setiap gagal, maksimal 6 jam
+func Backoff(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempts && delay < 6*time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > 6*time.Hour {
+		delay = 6 * time.Hour
+	}
+	return delay
+}